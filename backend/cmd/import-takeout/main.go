@@ -0,0 +1,104 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"sukimise/internal/config"
+	"sukimise/internal/repositories"
+	"sukimise/internal/services"
+	"sukimise/internal/takeout"
+
+	"github.com/joho/godotenv"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	username := flag.String("user", "", "username recorded as the creator of the imported stores (required)")
+	listName := flag.String("list", "", "tag added to every imported store (default: the file name)")
+	dryRun := flag.Bool("dry-run", false, "report what would be imported without creating stores")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s -user USERNAME [-list NAME] [-dry-run] FILE\n\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), "Imports a Google Maps saved places list from a Google Takeout GeoJSON or CSV file, or a Google My Maps KML file.")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 || *username == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	filename := flag.Arg(0)
+	if *listName == "" {
+		*listName = takeout.ListName(filename)
+	}
+
+	// Load environment variables
+	if err := godotenv.Load("../.env"); err != nil {
+		log.Println("Info: .env file not found (normal in Docker environment)")
+	}
+
+	// Load configuration
+	cfg := config.LoadConfig()
+	if cfg.Database.URL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+
+	// Read the file before touching the database
+	file, err := os.Open(filename)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", filename, err)
+	}
+	places, err := takeout.Parse(filename, file)
+	file.Close()
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", filename, err)
+	}
+	log.Printf("Found %d places in %s (list: %s)", len(places), filename, *listName)
+
+	// Connect to database
+	db, err := sql.Open("postgres", cfg.Database.URL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Test database connection
+	if err := db.Ping(); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	user, err := repositories.NewUserRepository(db).GetByUsername(*username)
+	if err != nil {
+		log.Fatalf("Failed to find user '%s': %v", *username, err)
+	}
+
+	storeService := services.NewStoreService(repositories.NewStoreRepository(db), cfg.DuplicatePolicy())
+
+	createdCount := 0
+	skippedCount := 0
+	failedCount := 0
+
+	for _, result := range takeout.Import(places, *listName, user.ID, *dryRun, storeService) {
+		switch {
+		case result.Err != nil:
+			log.Printf("#%d %s: failed: %v", result.Index, result.Name, result.Err)
+			failedCount++
+		case result.DuplicateOf != nil:
+			log.Printf("#%d %s: skipped, duplicate of %s", result.Index, result.Name, result.DuplicateOf)
+			skippedCount++
+		default:
+			log.Printf("#%d %s: created", result.Index, result.Name)
+			createdCount++
+		}
+	}
+
+	log.Printf("Takeout import completed (dry_run=%t):", *dryRun)
+	log.Printf("  Created: %d", createdCount)
+	log.Printf("  Skipped: %d", skippedCount)
+	log.Printf("  Failed: %d", failedCount)
+	log.Printf("  Total: %d", len(places))
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Business hours time zones must load without system tzdata
 
 	"sukimise/internal/config"
 	"sukimise/internal/database"
@@ -38,6 +39,11 @@ func main() {
 		log.Fatal("Configuration validation failed:", err)
 	}
 
+	businessHoursLocation, err := time.LoadLocation(cfg.BusinessHours.TimeZone)
+	if err != nil {
+		log.Fatal("Failed to load business hours time zone:", err)
+	}
+
 	// Validate user environment variables
 	if err := validateUserEnvironmentVariables(); err != nil {
 		log.Fatal("User environment validation failed:", err)
@@ -61,13 +67,23 @@ func main() {
 	reviewRepo := repositories.NewReviewRepository(db)
 	viewerAuthRepo := repositories.NewViewerAuthRepository(db)
 	categoryCustomizationRepo := repositories.NewCategoryCustomizationRepository(db)
+	backupRepo := repositories.NewBackupRepository(db)
+	labelRepo := repositories.NewLabelRepository(db)
+	storeMergeRepo := repositories.NewStoreMergeRepository(db)
+	storeListRepo := repositories.NewStoreListRepository(db)
+	shareLinkRepo := repositories.NewShareLinkRepository(db)
 
 	// Initialize services
 	userService := services.NewUserService(userRepo)
-	storeService := services.NewStoreService(storeRepo)
+	storeService := services.NewStoreService(storeRepo, cfg.DuplicatePolicy())
 	reviewService := services.NewReviewService(reviewRepo)
 	viewerAuthService := services.NewViewerAuthService(viewerAuthRepo)
 	categoryCustomizationService := services.NewCategoryCustomizationService(categoryCustomizationRepo)
+	backupService := services.NewBackupService(backupRepo)
+	labelService := services.NewLabelService(labelRepo)
+	storeMergeService := services.NewStoreMergeService(storeMergeRepo)
+	storeListService := services.NewStoreListService(storeListRepo)
+	shareLinkService := services.NewShareLinkService(shareLinkRepo, cfg.JWT.Secret)
 
 	// Initialize users from environment variables
 	if err := initializeUsersFromEnv(userService); err != nil {
@@ -75,9 +91,19 @@ func main() {
 	}
 
 	// Initialize handlers
-	handler := handlers.NewHandler(userService, storeService, reviewService)
+	handler := handlers.NewHandler(userService, storeService, reviewService, categoryCustomizationService, storeListService, businessHoursLocation)
 	viewerAuthHandler := handlers.NewViewerAuthHandler(viewerAuthService)
 	categoryCustomizationHandler := handlers.NewCategoryCustomizationHandler(categoryCustomizationService, storeService)
+	backupHandler := handlers.NewBackupHandler(backupService)
+	trashHandler := handlers.NewTrashHandler(storeService, cfg.Trash.RetentionPeriod)
+	storeHistoryHandler := handlers.NewStoreHistoryHandler(storeService)
+	labelHandler := handlers.NewLabelHandler(labelService)
+	storeMergeHandler := handlers.NewStoreMergeHandler(storeMergeService)
+	storeListHandler := handlers.NewStoreListHandler(storeListService)
+	shareLinkHandler := handlers.NewShareLinkHandler(shareLinkService, handler)
+
+	// Periodically purge stores that have been in the trash longer than the retention period
+	go purgeTrashPeriodically(storeService, cfg.Trash)
 
 	// Set Gin mode based on environment
 	if cfg.IsProduction() {
@@ -152,15 +178,32 @@ func main() {
 		}
 
 		stores := api.Group("/stores")
+		stores.Use(middleware.OptionalAuth())
 		{
 			stores.GET("", handler.GetStores)
+			stores.POST("/search", handler.SearchStores)
+			stores.GET("/clusters", handler.GetStoreClusters)
 			stores.GET("/export/csv", handler.ExportStoresCSV)
+			stores.GET("/export/geojson", handler.ExportStoresGeoJSON)
+			stores.GET("/export/kml", handler.ExportStoresKML)
 			stores.GET("/categories", handler.GetCategories)
 			stores.GET("/tags", handler.GetTags)
 			stores.GET("/:id", handler.GetStore)
 			stores.GET("/:id/reviews", handler.GetReviewsByStore)
+			stores.GET("/:id/history", storeHistoryHandler.GetStoreHistory)
 		}
 
+		// Lists are shown to everyone their visibility allows
+		lists := api.Group("/lists")
+		lists.Use(middleware.OptionalAuth())
+		{
+			lists.GET("", storeListHandler.GetLists)
+			lists.GET("/:id", storeListHandler.GetList)
+		}
+
+		// Read-only access to a shared store, list or filter without login
+		api.GET("/share/:token", shareLinkHandler.GetShared)
+
 		categoryCustomizations := api.Group("/category-customizations")
 		{
 			categoryCustomizations.GET("", categoryCustomizationHandler.GetCategoryCustomizations)
@@ -173,10 +216,31 @@ func main() {
 			protectedStores := protected.Group("/stores")
 			{
 				protectedStores.POST("", handler.CreateStore)
+				protectedStores.POST("/import/csv", handler.ImportStoresCSV)
+				protectedStores.POST("/bulk", handler.BulkUpdateStores)
 				protectedStores.PUT("/:id", handler.UpdateStore)
+				protectedStores.PATCH("/:id", handler.PatchStore)
 				protectedStores.DELETE("/:id", handler.DeleteStore)
 			}
 
+			protectedLists := protected.Group("/lists")
+			{
+				protectedLists.POST("", storeListHandler.CreateList)
+				protectedLists.PUT("/:id", storeListHandler.UpdateList)
+				protectedLists.DELETE("/:id", storeListHandler.DeleteList)
+				protectedLists.POST("/:id/entries", storeListHandler.AddListEntry)
+				protectedLists.DELETE("/:id/entries/:store_id", storeListHandler.RemoveListEntry)
+			}
+
+			// Share links are created by editors; admins see and revoke everyone's links
+			shareLinks := protected.Group("/share-links")
+			shareLinks.Use(middleware.RequireRole("editor"))
+			{
+				shareLinks.POST("", shareLinkHandler.CreateShareLink)
+				shareLinks.GET("", shareLinkHandler.GetShareLinks)
+				shareLinks.DELETE("/:id", shareLinkHandler.RevokeShareLink)
+			}
+
 			reviews := protected.Group("/reviews")
 			{
 				reviews.POST("", handler.CreateReview)
@@ -211,6 +275,31 @@ func main() {
 				admin.PUT("/category-customizations/:categoryName", categoryCustomizationHandler.UpdateCategoryCustomization)
 				admin.DELETE("/category-customizations/:categoryName", categoryCustomizationHandler.DeleteCategoryCustomization)
 				admin.POST("/category-customizations/sync", categoryCustomizationHandler.SyncCategoriesWithStores)
+
+				// Full backup and restore (admin only)
+				admin.GET("/backup", backupHandler.GetBackup)
+				admin.POST("/restore", backupHandler.RestoreBackup)
+
+				// Trash for soft-deleted stores (admin only)
+				admin.GET("/trash", trashHandler.GetTrash)
+				admin.POST("/trash/purge", trashHandler.PurgeTrash)
+				admin.POST("/stores/:id/restore", trashHandler.RestoreStore)
+
+				// Import of Google Maps saved places lists (admin only)
+				admin.POST("/stores/import/takeout", handler.ImportStoresTakeout)
+
+				// Store change history (admin only)
+				admin.POST("/stores/:id/revisions/:revision_id/rollback", storeHistoryHandler.RollbackStore)
+
+				// Rename and merge tags and categories across all stores (admin only)
+				admin.POST("/tags/rename", labelHandler.RenameTag)
+				admin.POST("/tags/merge", labelHandler.MergeTags)
+				admin.POST("/categories/rename", labelHandler.RenameCategory)
+				admin.POST("/categories/merge", labelHandler.MergeCategories)
+
+				// Find and merge duplicate stores (admin only)
+				admin.GET("/stores/duplicates", storeMergeHandler.GetDuplicateCandidates)
+				admin.POST("/stores/merge", storeMergeHandler.MergeStores)
 			}
 		}
 	}
@@ -250,6 +339,27 @@ func main() {
 	log.Println("Server exited")
 }
 
+// purgeTrashPeriodically removes expired stores from the trash at the configured interval
+func purgeTrashPeriodically(storeService *services.StoreService, trashConfig config.TrashConfig) {
+	if trashConfig.PurgeInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(trashConfig.PurgeInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		purged, err := storeService.PurgeDeletedStores(trashConfig.RetentionPeriod)
+		if err != nil {
+			log.Printf("Failed to purge deleted stores: %v", err)
+			continue
+		}
+		if purged > 0 {
+			log.Printf("Purged %d deleted stores", purged)
+		}
+	}
+}
+
 // validateUserEnvironmentVariables validates that required user environment variables are set
 func validateUserEnvironmentVariables() error {
 	adminUsers := os.Getenv("ADMIN_USERS")
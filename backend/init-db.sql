@@ -24,7 +24,7 @@ CREATE TABLE stores (
     address TEXT NOT NULL,
     latitude DECIMAL(10, 8) NOT NULL,
     longitude DECIMAL(11, 8) NOT NULL,
-    location GEOGRAPHY(POINT, 4326),
+    location GEOGRAPHY(POINT, 4326) GENERATED ALWAYS AS (ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography) STORED,
     categories JSONB DEFAULT '[]',
     business_hours TEXT,
     price_range VARCHAR(50),
@@ -4,16 +4,20 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"sukimise/internal/models"
 	"time"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig   `yaml:"server"`
-	Database DatabaseConfig `yaml:"database"`
-	JWT      JWTConfig      `yaml:"jwt"`
-	Upload   UploadConfig   `yaml:"upload"`
-	CORS     CORSConfig     `yaml:"cors"`
+	Server        ServerConfig        `yaml:"server"`
+	Database      DatabaseConfig      `yaml:"database"`
+	JWT           JWTConfig           `yaml:"jwt"`
+	Upload        UploadConfig        `yaml:"upload"`
+	CORS          CORSConfig          `yaml:"cors"`
+	Trash         TrashConfig         `yaml:"trash"`
+	BusinessHours BusinessHoursConfig `yaml:"business_hours"`
+	Duplicate     DuplicateConfig     `yaml:"duplicate"`
 }
 
 // ServerConfig holds server configuration
@@ -60,6 +64,24 @@ type CORSConfig struct {
 	MaxAge           int      `yaml:"max_age"`
 }
 
+// BusinessHoursConfig holds configuration for evaluating store business hours
+type BusinessHoursConfig struct {
+	TimeZone string `yaml:"time_zone"` // IANA time zone of business hours, used by open_now/open_at
+}
+
+// DuplicateConfig holds the duplicate check done when a store is created
+type DuplicateConfig struct {
+	Radius        float64 `yaml:"radius"`         // meters
+	NameMatch     string  `yaml:"name_match"`     // exact, normalized or fuzzy
+	MinSimilarity float64 `yaml:"min_similarity"` // fuzzy only, 0-1
+}
+
+// TrashConfig holds configuration for soft-deleted stores
+type TrashConfig struct {
+	RetentionPeriod time.Duration `yaml:"retention_period"`
+	PurgeInterval   time.Duration `yaml:"purge_interval"`
+}
+
 // LoadConfig loads configuration from environment variables with defaults
 func LoadConfig() *Config {
 	return &Config{
@@ -92,11 +114,32 @@ func LoadConfig() *Config {
 		},
 		CORS: CORSConfig{
 			AllowedOrigins:   getStringSliceEnv("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000", "http://localhost:5173"}),
-			AllowedMethods:   getStringSliceEnv("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
-			AllowedHeaders:   getStringSliceEnv("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Accept", "Authorization"}),
+			AllowedMethods:   getStringSliceEnv("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
+			AllowedHeaders:   getStringSliceEnv("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Accept", "Authorization", "If-Match"}),
 			AllowCredentials: getBoolEnv("CORS_ALLOW_CREDENTIALS", true),
 			MaxAge:           getIntEnv("CORS_MAX_AGE", 86400), // 24 hours
 		},
+		Trash: TrashConfig{
+			RetentionPeriod: getDurationEnv("TRASH_RETENTION_PERIOD", 30*24*time.Hour), // 30 days
+			PurgeInterval:   getDurationEnv("TRASH_PURGE_INTERVAL", 24*time.Hour),
+		},
+		BusinessHours: BusinessHoursConfig{
+			TimeZone: getEnv("BUSINESS_HOURS_TIMEZONE", "Asia/Tokyo"),
+		},
+		Duplicate: DuplicateConfig{
+			Radius:        getFloatEnv("DUPLICATE_RADIUS", models.DefaultDuplicatePolicy.Radius),
+			NameMatch:     getEnv("DUPLICATE_NAME_MATCH", models.DefaultDuplicatePolicy.NameMatch),
+			MinSimilarity: getFloatEnv("DUPLICATE_MIN_SIMILARITY", 0.5),
+		},
+	}
+}
+
+// DuplicatePolicy returns the duplicate store policy of the configuration
+func (c *Config) DuplicatePolicy() models.DuplicatePolicy {
+	return models.DuplicatePolicy{
+		Radius:        c.Duplicate.Radius,
+		NameMatch:     c.Duplicate.NameMatch,
+		MinSimilarity: c.Duplicate.MinSimilarity,
 	}
 }
 
@@ -127,6 +170,10 @@ func (c *Config) Validate() error {
 	if c.Database.URL == "" {
 		log.Fatal("Database URL is required")
 	}
+
+	if err := c.DuplicatePolicy().Validate(); err != nil {
+		log.Fatalf("Invalid duplicate store settings: %v", err)
+	}
 	
 	return nil
 }
@@ -170,6 +217,16 @@ func getBoolEnv(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+func getFloatEnv(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+		log.Printf("Warning: Invalid float value for %s: %s, using default: %v", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
@@ -26,6 +26,60 @@ const (
 	AllowedImageTypes = "image/jpeg,image/png,image/gif,image/webp"
 )
 
+// Store
+const (
+	MaxStoreCategories     = 10
+	MaxStoreTags           = 20
+	MaxStorePhotos         = 20
+	MaxBulkStores          = 500  // Maximum number of stores changed by one bulk operation
+	MaxSearchQueryLength   = 100  // Maximum length of the q search parameter in characters
+	MaxSearchPolygonPoints = 1000 // Maximum number of positions of a POST /stores/search polygon
+)
+
+// Map
+const (
+	MaxMapZoom                = 22
+	StoreClusterMaxZoom       = 15  // Above this zoom level stores are returned individually
+	StoreClusterCellsPerTile  = 4   // Cluster cells per 256px map tile width
+	StoreClusterTopCategories = 3   // Categories listed per cluster
+	MaxMapStores              = 500 // Maximum number of individual stores returned for a map view
+)
+
+// Duplicate Stores
+const (
+	DefaultDuplicateRadius         = 100.0 // meters
+	MaxDuplicateRadius             = 1000.0
+	DefaultDuplicateNameSimilarity = 0.3  // Minimum trigram similarity of normalized names
+	DuplicateSameSpotDistance      = 10.0 // meters; closer pairs are candidates whatever their names
+	DefaultDuplicateCandidates     = 50
+	MaxDuplicateCandidates         = 200
+)
+
+// Store Lists
+const (
+	MaxListTitleLength       = 100
+	MaxListDescriptionLength = 1000
+	MaxListEntries           = 500
+	MaxListEntryNoteLength   = 500
+)
+
+// Share Links
+const (
+	DefaultShareLinkDays = 7
+	MaxShareLinkDays     = 365
+)
+
+// Store Import
+const (
+	MaxImportFileSize = 5 << 20 // 5MB
+	MaxImportRows     = 1000
+)
+
+// Backup
+const (
+	MaxRestoreFileSize = 100 << 20 // 100MB
+)
+
 // Database
 const (
 	DefaultTimeout = 30 // seconds
@@ -57,4 +111,6 @@ const (
 	ErrorCodeInternalError  = "INTERNAL_ERROR"
 	ErrorCodeDatabaseError  = "DATABASE_ERROR"
 	ErrorCodeFileUploadError = "FILE_UPLOAD_ERROR"
+	ErrorCodePreconditionFailed = "PRECONDITION_FAILED"
+	ErrorCodeConflict = "CONFLICT"
 )
\ No newline at end of file
@@ -90,7 +90,7 @@ func NewConflictError(message string, details string) *AppError {
 	return &AppError{
 		Message:    message,
 		StatusCode: http.StatusConflict,
-		Code:       "CONFLICT",
+		Code:       constants.ErrorCodeConflict,
 		Details:    details,
 	}
 }
@@ -141,6 +141,33 @@ func SendCreated(c *gin.Context, data interface{}) {
 	})
 }
 
+// SendPreconditionFailed sends a 412 response carrying the current server copy
+// of a resource, so that the client can merge its stale changes
+func SendPreconditionFailed(c *gin.Context, current interface{}) {
+	c.JSON(http.StatusPreconditionFailed, types.APIResponse{
+		Success: false,
+		Data:    current,
+		Error: &types.ErrorInfo{
+			Code:    constants.ErrorCodePreconditionFailed,
+			Message: "Resource has been modified by someone else",
+		},
+	})
+}
+
+// SendConflict sends a 409 response carrying the resource that conflicts with
+// the request, so that the client can point at it or retry knowingly
+func SendConflict(c *gin.Context, conflict interface{}, message, details string) {
+	c.JSON(http.StatusConflict, types.APIResponse{
+		Success: false,
+		Data:    conflict,
+		Error: &types.ErrorInfo{
+			Code:    constants.ErrorCodeConflict,
+			Message: message,
+			Details: details,
+		},
+	})
+}
+
 // SendNoContent sends a no content response
 func SendNoContent(c *gin.Context) {
 	c.JSON(http.StatusNoContent, types.APIResponse{
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sukimise/internal/constants"
+	"sukimise/internal/errors"
+	"sukimise/internal/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BackupHandler struct {
+	backupService *services.BackupService
+}
+
+func NewBackupHandler(backupService *services.BackupService) *BackupHandler {
+	return &BackupHandler{backupService: backupService}
+}
+
+// GetBackup streams a full NDJSON backup of all data
+func (h *BackupHandler) GetBackup(c *gin.Context) {
+	timestamp := time.Now().Format("20060102_150405")
+	filename := fmt.Sprintf("sukimise_backup_%s.ndjson", timestamp)
+	c.Header("Content-Type", "application/x-ndjson; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent once streaming starts, so failures can only be logged
+	if err := h.backupService.WriteBackup(c.Writer); err != nil {
+		log.Printf("Failed to write backup: %v", err)
+		c.Abort()
+		return
+	}
+
+	log.Printf("Backup export completed: %s", filename)
+}
+
+// RestoreBackup restores an NDJSON backup uploaded in the 'file' field
+func (h *BackupHandler) RestoreBackup(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constants.MaxRestoreFileSize)
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		if err == http.ErrMissingFile {
+			errors.HandleError(c, errors.NewValidationError("No file uploaded", "Backup file is required in the 'file' field"))
+		} else {
+			errors.HandleError(c, errors.NewValidationError("File too large or invalid", err.Error()))
+		}
+		return
+	}
+	defer file.Close()
+
+	data, err := services.ParseBackup(file)
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid backup file", err.Error()))
+		return
+	}
+
+	result, err := h.backupService.Restore(data)
+	if err != nil {
+		log.Printf("Failed to restore backup: %v", err)
+		appErr := errors.NewDatabaseError("Failed to restore backup")
+		appErr.Details = err.Error()
+		errors.HandleError(c, appErr)
+		return
+	}
+
+	log.Printf("Backup restore completed: %+v", *result)
+	errors.SendSuccess(c, result)
+}
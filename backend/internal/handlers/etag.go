@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Entity tags of stores and reviews are their updated_at timestamps, formatted
+// exactly as in the JSON responses. A client can therefore send back the
+// updated_at of the copy it edited as If-Match, even when it got that copy
+// from a list response without an ETag header.
+
+// etagFor returns the entity tag of a resource last updated at updatedAt
+func etagFor(updatedAt time.Time) string {
+	return `"` + updatedAt.Format(time.RFC3339Nano) + `"`
+}
+
+// setETag sets the ETag response header for a resource last updated at updatedAt
+func setETag(c *gin.Context, updatedAt time.Time) {
+	c.Header("ETag", etagFor(updatedAt))
+}
+
+// ifMatchSatisfied reports whether an If-Match header value matches a resource
+// last updated at updatedAt. A missing header or "*" always matches. Weak tags
+// never match, since If-Match requires strong comparison. Unquoted timestamps
+// are accepted as well.
+func ifMatchSatisfied(header string, updatedAt time.Time) bool {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return true
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if strings.HasPrefix(candidate, "W/") {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, strings.Trim(candidate, `"`))
+		if err != nil {
+			continue
+		}
+		if t.Equal(updatedAt) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatchSatisfied(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	updatedAt := time.Date(2024, 5, 1, 12, 30, 0, 123456000, jst)
+
+	tests := []struct {
+		name     string
+		header   string
+		expected bool
+	}{
+		{name: "no header", header: "", expected: true},
+		{name: "wildcard", header: "*", expected: true},
+		{name: "own etag", header: etagFor(updatedAt), expected: true},
+		{name: "same instant in UTC", header: `"2024-05-01T03:30:00.123456Z"`, expected: true},
+		{name: "unquoted", header: "2024-05-01T12:30:00.123456+09:00", expected: true},
+		{name: "one of several", header: `"2024-01-01T00:00:00Z", ` + etagFor(updatedAt), expected: true},
+		{name: "stale", header: `"2024-05-01T12:30:00.123455+09:00"`, expected: false},
+		{name: "weak tag", header: "W/" + etagFor(updatedAt), expected: false},
+		{name: "garbage", header: `"abc"`, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ifMatchSatisfied(tt.header, updatedAt))
+		})
+	}
+}
+
+func TestEtagFor(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 3, 30, 0, 0, time.UTC)
+	assert.Equal(t, `"2024-05-01T03:30:00Z"`, etagFor(updatedAt))
+}
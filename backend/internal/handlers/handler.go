@@ -2,18 +2,25 @@ package handlers
 
 import (
 	"sukimise/internal/services"
+	"time"
 )
 
 type Handler struct {
-	userService   *services.UserService
-	storeService  *services.StoreService
-	reviewService *services.ReviewService
+	userService                  *services.UserService
+	storeService                 *services.StoreService
+	reviewService                *services.ReviewService
+	categoryCustomizationService *services.CategoryCustomizationService
+	storeListService             *services.StoreListService
+	location                     *time.Location // Time zone of store business hours
 }
 
-func NewHandler(userService *services.UserService, storeService *services.StoreService, reviewService *services.ReviewService) *Handler {
+func NewHandler(userService *services.UserService, storeService *services.StoreService, reviewService *services.ReviewService, categoryCustomizationService *services.CategoryCustomizationService, storeListService *services.StoreListService, location *time.Location) *Handler {
 	return &Handler{
-		userService:   userService,
-		storeService:  storeService,
-		reviewService: reviewService,
+		userService:                  userService,
+		storeService:                 storeService,
+		reviewService:                reviewService,
+		categoryCustomizationService: categoryCustomizationService,
+		storeListService:             storeListService,
+		location:                     location,
 	}
 }
\ No newline at end of file
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"log"
+	"sukimise/internal/errors"
+	"sukimise/internal/models"
+	"sukimise/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// LabelRenameRequest represents the request body for renaming a tag or category
+type LabelRenameRequest struct {
+	From string `json:"from" binding:"required"`
+	To   string `json:"to" binding:"required"`
+}
+
+// LabelMergeRequest represents the request body for merging tags or categories into one
+type LabelMergeRequest struct {
+	From []string `json:"from" binding:"required"`
+	To   string   `json:"to" binding:"required"`
+}
+
+type LabelHandler struct {
+	labelService *services.LabelService
+}
+
+func NewLabelHandler(labelService *services.LabelService) *LabelHandler {
+	return &LabelHandler{labelService: labelService}
+}
+
+// RenameTag renames a tag on every store
+func (h *LabelHandler) RenameTag(c *gin.Context) {
+	h.rename(c, models.LabelKindTag)
+}
+
+// MergeTags merges several tags into one on every store
+func (h *LabelHandler) MergeTags(c *gin.Context) {
+	h.merge(c, models.LabelKindTag)
+}
+
+// RenameCategory renames a category on every store, together with its icon and color
+func (h *LabelHandler) RenameCategory(c *gin.Context) {
+	h.rename(c, models.LabelKindCategory)
+}
+
+// MergeCategories merges several categories into one on every store
+func (h *LabelHandler) MergeCategories(c *gin.Context) {
+	h.merge(c, models.LabelKindCategory)
+}
+
+func (h *LabelHandler) rename(c *gin.Context, kind string) {
+	var req LabelRenameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid request data", err.Error()))
+		return
+	}
+	h.apply(c, kind, []string{req.From}, req.To)
+}
+
+func (h *LabelHandler) merge(c *gin.Context, kind string) {
+	var req LabelMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid request data", err.Error()))
+		return
+	}
+	h.apply(c, kind, req.From, req.To)
+}
+
+// apply merges the labels, or only previews the number of affected stores when dry_run=true
+func (h *LabelHandler) apply(c *gin.Context, kind string, from []string, to string) {
+	from, to, err := services.NormalizeLabelMerge(from, to)
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid label merge", err.Error()))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	result, err := h.labelService.MergeLabels(kind, from, to, userID.(uuid.UUID), dryRun)
+	if err != nil {
+		log.Printf("Failed to merge %s labels %v into %s: %v", kind, from, to, err)
+		errors.HandleError(c, errors.NewInternalError("Failed to merge labels"))
+		return
+	}
+
+	if !dryRun {
+		log.Printf("Merged %s labels %v into %s on %d stores", kind, from, to, result.AffectedStores)
+	}
+	errors.SendSuccess(c, result)
+}
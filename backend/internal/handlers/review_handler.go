@@ -4,6 +4,7 @@ import (
 	"log"
 	"net/http"
 	"sukimise/internal/models"
+	"sukimise/internal/repositories"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -65,6 +66,7 @@ func (h *Handler) CreateReview(c *gin.Context) {
 		return
 	}
 
+	setETag(c, review.UpdatedAt)
 	c.JSON(http.StatusCreated, review)
 }
 
@@ -82,6 +84,7 @@ func (h *Handler) GetReview(c *gin.Context) {
 		return
 	}
 
+	setETag(c, review.UpdatedAt)
 	c.JSON(http.StatusOK, review)
 }
 
@@ -149,6 +152,13 @@ func (h *Handler) UpdateReview(c *gin.Context) {
 		return
 	}
 
+	// Reject changes made to an outdated copy of the review
+	if !ifMatchSatisfied(c.GetHeader("If-Match"), existingReview.UpdatedAt) {
+		setETag(c, existingReview.UpdatedAt)
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Review has been modified by someone else", "current": existingReview})
+		return
+	}
+
 	if req.Rating > 0 {
 		existingReview.Rating = req.Rating
 	}
@@ -172,10 +182,21 @@ func (h *Handler) UpdateReview(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 			return
 		}
+		if err == repositories.ErrVersionConflict {
+			currentReview, err := h.reviewService.GetReviewByID(id)
+			if err != nil {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+				return
+			}
+			setETag(c, currentReview.UpdatedAt)
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Review has been modified by someone else", "current": currentReview})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update review"})
 		return
 	}
 
+	setETag(c, existingReview.UpdatedAt)
 	c.JSON(http.StatusOK, existingReview)
 }
 
@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sukimise/internal/constants"
+	"sukimise/internal/errors"
+	"sukimise/internal/models"
+	"sukimise/internal/services"
+	"sukimise/internal/types"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// shareFilterExcludedParams are GET /stores parameters a shared filter cannot
+// keep: those that depend on who is asking, and paging, which is up to the reader
+var shareFilterExcludedParams = []string{"visited_by", "list_id", "cursor", "limit", "offset"}
+
+// ShareLinkRequest represents the request body for creating a share link
+type ShareLinkRequest struct {
+	Scope         string     `json:"scope"` // store, list or filter
+	StoreID       *uuid.UUID `json:"store_id"`
+	ListID        *uuid.UUID `json:"list_id"`
+	Filter        string     `json:"filter"`          // GET /stores query string, e.g. categories=カフェ&open_now=true
+	ExpiresInDays int        `json:"expires_in_days"` // Defaults to DefaultShareLinkDays
+}
+
+// Validate checks that the request names exactly the resource of its scope and a valid lifetime
+func (r *ShareLinkRequest) Validate() error {
+	switch r.Scope {
+	case models.ShareScopeStore:
+		if r.StoreID == nil || r.ListID != nil || r.Filter != "" {
+			return errors.NewValidationError("Invalid share link", "a store link needs store_id only")
+		}
+	case models.ShareScopeList:
+		if r.ListID == nil || r.StoreID != nil || r.Filter != "" {
+			return errors.NewValidationError("Invalid share link", "a list link needs list_id only")
+		}
+	case models.ShareScopeFilter:
+		if strings.TrimSpace(r.Filter) == "" || r.StoreID != nil || r.ListID != nil {
+			return errors.NewValidationError("Invalid share link", "a filter link needs filter only")
+		}
+	default:
+		return errors.NewValidationError("Invalid scope", "scope must be store, list or filter")
+	}
+
+	if r.ExpiresInDays < 0 || r.ExpiresInDays > constants.MaxShareLinkDays {
+		return errors.NewValidationError("Invalid expires_in_days", fmt.Sprintf("expires_in_days must be between 1 and %d", constants.MaxShareLinkDays))
+	}
+	return nil
+}
+
+// ToModel converts the request to a link created by createdBy at now.
+// The filter must have been normalized by parseShareFilter.
+func (r *ShareLinkRequest) ToModel(createdBy uuid.UUID, now time.Time) *models.ShareLink {
+	days := r.ExpiresInDays
+	if days == 0 {
+		days = constants.DefaultShareLinkDays
+	}
+
+	link := &models.ShareLink{
+		Scope:     r.Scope,
+		StoreID:   r.StoreID,
+		ListID:    r.ListID,
+		CreatedBy: createdBy,
+		ExpiresAt: now.AddDate(0, 0, days).Truncate(time.Second),
+	}
+	if r.Scope == models.ShareScopeFilter {
+		filter := r.Filter
+		link.FilterQuery = &filter
+	}
+	return link
+}
+
+type ShareLinkHandler struct {
+	shareLinkService *services.ShareLinkService
+	storeHandler     *Handler
+}
+
+// NewShareLinkHandler creates a handler serving shared resources through the
+// services and store filter parsing of storeHandler
+func NewShareLinkHandler(shareLinkService *services.ShareLinkService, storeHandler *Handler) *ShareLinkHandler {
+	return &ShareLinkHandler{shareLinkService: shareLinkService, storeHandler: storeHandler}
+}
+
+// CreateShareLink creates a read-only link to a store, a list the user owns
+// or the stores matching a filter, and returns it with its token
+func (h *ShareLinkHandler) CreateShareLink(c *gin.Context) {
+	var req ShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid request data", err.Error()))
+		return
+	}
+	if err := req.Validate(); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	userID := currentUserID(c)
+	if userID == nil {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	switch req.Scope {
+	case models.ShareScopeStore:
+		if _, err := h.storeHandler.storeService.GetStoreByID(*req.StoreID); err != nil {
+			errors.HandleError(c, errors.NewNotFoundError("Store"))
+			return
+		}
+	case models.ShareScopeList:
+		// Only the owner may hand a list out, since only the owner can revoke
+		// the links to it afterwards
+		err := h.storeHandler.storeListService.CheckListOwned(*req.ListID, *userID, c.GetString("role"))
+		if err == services.ErrNotListOwner {
+			errors.HandleError(c, errors.NewForbiddenError("You can only share lists you own"))
+			return
+		}
+		if err != nil {
+			handleStoreListError(c, err, "get list")
+			return
+		}
+	case models.ShareScopeFilter:
+		query, err := h.parseShareFilter(req.Filter)
+		if err != nil {
+			errors.HandleError(c, err)
+			return
+		}
+		req.Filter = query
+	}
+
+	link := req.ToModel(*userID, time.Now())
+	if err := h.shareLinkService.CreateShareLink(link); err != nil {
+		log.Printf("Failed to create share link: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to create share link"))
+		return
+	}
+
+	errors.SendCreated(c, link)
+}
+
+// GetShareLinks returns the links the user created, newest first. Admins see everyone's links.
+func (h *ShareLinkHandler) GetShareLinks(c *gin.Context) {
+	userID := currentUserID(c)
+	if userID == nil {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+	createdBy := userID
+	if c.GetString("role") == constants.RoleAdmin {
+		createdBy = nil
+	}
+
+	limit := constants.DefaultLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			if l > constants.MaxLimit {
+				l = constants.MaxLimit
+			}
+			limit = l
+		}
+	}
+
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	links, totalCount, err := h.shareLinkService.GetShareLinks(createdBy, limit, offset)
+	if err != nil {
+		log.Printf("Failed to get share links: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get share links"))
+		return
+	}
+
+	totalPages := (totalCount + limit - 1) / limit
+	currentPage := (offset / limit) + 1
+
+	meta := &types.MetaInfo{
+		Total:      totalCount,
+		Limit:      limit,
+		Offset:     offset,
+		Page:       &currentPage,
+		TotalPages: &totalPages,
+	}
+
+	errors.SendSuccess(c, map[string]interface{}{"share_links": links}, meta)
+}
+
+// RevokeShareLink ends access through a link. Only its creator or an admin can revoke it.
+func (h *ShareLinkHandler) RevokeShareLink(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid share link ID", err.Error()))
+		return
+	}
+
+	userID := currentUserID(c)
+	if userID == nil {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	link, err := h.shareLinkService.RevokeShareLink(id, *userID, c.GetString("role") == constants.RoleAdmin)
+	if err != nil {
+		switch err {
+		case sql.ErrNoRows:
+			errors.HandleError(c, errors.NewNotFoundError("Share link"))
+		case services.ErrNotShareLinkCreator:
+			errors.HandleError(c, errors.NewForbiddenError("You can only revoke share links you created"))
+		default:
+			log.Printf("Failed to revoke share link: %v", err)
+			errors.HandleError(c, errors.NewInternalError("Failed to revoke share link"))
+		}
+		return
+	}
+
+	errors.SendSuccess(c, link)
+}
+
+// GetShared returns the resource of a share token without login. Stores and
+// lists come with the scope and expiry of the link; filters respond like
+// GET /stores, paged with limit, offset and cursor.
+func (h *ShareLinkHandler) GetShared(c *gin.Context) {
+	link, err := h.shareLinkService.OpenShareLink(c.Param("token"), time.Now())
+	if err != nil {
+		if err == services.ErrInvalidShareToken {
+			errors.HandleError(c, errors.NewNotFoundError("Share link"))
+			return
+		}
+		log.Printf("Failed to open share link: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to open share link"))
+		return
+	}
+
+	switch link.Scope {
+	case models.ShareScopeStore:
+		h.sendSharedStore(c, link)
+	case models.ShareScopeList:
+		h.sendSharedList(c, link)
+	case models.ShareScopeFilter:
+		h.sendSharedFilter(c, link)
+	default:
+		log.Printf("Share link %s has unknown scope %q", link.ID, link.Scope)
+		errors.HandleError(c, errors.NewNotFoundError("Share link"))
+	}
+}
+
+func (h *ShareLinkHandler) sendSharedStore(c *gin.Context, link *models.ShareLink) {
+	store, err := h.storeHandler.storeService.GetStoreByID(*link.StoreID)
+	if err != nil {
+		errors.HandleError(c, errors.NewNotFoundError("Store"))
+		return
+	}
+
+	reviews, err := h.storeHandler.reviewService.GetReviewsByStoreID(store.ID)
+	if err != nil {
+		log.Printf("Failed to get reviews of shared store: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get reviews"))
+		return
+	}
+
+	errors.SendSuccess(c, map[string]interface{}{
+		"scope":      link.Scope,
+		"expires_at": link.ExpiresAt,
+		"store":      store,
+		"reviews":    reviews,
+	})
+}
+
+// sendSharedList shows the list as its creator, the owner and an editor, sees
+// it, so the link stops working when the creator loses access to the list
+func (h *ShareLinkHandler) sendSharedList(c *gin.Context, link *models.ShareLink) {
+	list, err := h.storeHandler.storeListService.GetList(*link.ListID, &link.CreatedBy, constants.RoleEditor)
+	if err != nil {
+		handleStoreListError(c, err, "get list")
+		return
+	}
+
+	errors.SendSuccess(c, map[string]interface{}{
+		"scope":      link.Scope,
+		"expires_at": link.ExpiresAt,
+		"list":       list,
+	})
+}
+
+func (h *ShareLinkHandler) sendSharedFilter(c *gin.Context, link *models.ShareLink) {
+	values, err := url.ParseQuery(*link.FilterQuery)
+	if err != nil {
+		log.Printf("Share link %s has an invalid filter: %v", link.ID, err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get stores"))
+		return
+	}
+	for _, param := range []string{"limit", "offset"} {
+		if value := c.Query(param); value != "" {
+			values.Set(param, value)
+		}
+	}
+
+	filter := h.storeHandler.parseStoreFilter(queryContext(values))
+	if err := h.storeHandler.validateStoreFilter(filter); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	h.storeHandler.sendStorePage(c, filter)
+}
+
+// parseShareFilter checks a GET /stores query string for a filter link and
+// returns it normalized
+func (h *ShareLinkHandler) parseShareFilter(query string) (string, error) {
+	values, err := url.ParseQuery(strings.TrimPrefix(strings.TrimSpace(query), "?"))
+	if err != nil {
+		return "", errors.NewValidationError("Invalid filter", err.Error())
+	}
+	for _, param := range shareFilterExcludedParams {
+		if values.Has(param) {
+			return "", errors.NewValidationError("Invalid filter", fmt.Sprintf("%s cannot be shared", param))
+		}
+	}
+
+	filter := h.storeHandler.parseStoreFilter(queryContext(values))
+	if err := h.storeHandler.validateStoreFilter(filter); err != nil {
+		return "", err
+	}
+	if !hasStoreFilterCriteria(filter) {
+		return "", errors.NewValidationError("Invalid filter", "filter must narrow down the stores")
+	}
+	return values.Encode(), nil
+}
+
+// queryContext returns a context holding only the query parameters values,
+// for parsing a stored store filter
+func queryContext(values url.Values) *gin.Context {
+	return &gin.Context{Request: &http.Request{URL: &url.URL{RawQuery: values.Encode()}}}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sukimise/internal/constants"
+	"sukimise/internal/models"
+	"sukimise/internal/services"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareLinkRequest_Validate(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name    string
+		req     ShareLinkRequest
+		wantErr bool
+	}{
+		{name: "store", req: ShareLinkRequest{Scope: models.ShareScopeStore, StoreID: &id}},
+		{name: "list", req: ShareLinkRequest{Scope: models.ShareScopeList, ListID: &id, ExpiresInDays: 30}},
+		{name: "filter", req: ShareLinkRequest{Scope: models.ShareScopeFilter, Filter: "categories=カフェ"}},
+		{name: "unknown scope", req: ShareLinkRequest{Scope: "user", StoreID: &id}, wantErr: true},
+		{name: "store without id", req: ShareLinkRequest{Scope: models.ShareScopeStore}, wantErr: true},
+		{name: "store with list", req: ShareLinkRequest{Scope: models.ShareScopeStore, StoreID: &id, ListID: &id}, wantErr: true},
+		{name: "blank filter", req: ShareLinkRequest{Scope: models.ShareScopeFilter, Filter: " "}, wantErr: true},
+		{name: "negative lifetime", req: ShareLinkRequest{Scope: models.ShareScopeStore, StoreID: &id, ExpiresInDays: -1}, wantErr: true},
+		{name: "too long lifetime", req: ShareLinkRequest{Scope: models.ShareScopeStore, StoreID: &id, ExpiresInDays: constants.MaxShareLinkDays + 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShareLinkRequest_ToModel(t *testing.T) {
+	userID, storeID := uuid.New(), uuid.New()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 500, time.UTC)
+
+	link := (&ShareLinkRequest{Scope: models.ShareScopeStore, StoreID: &storeID}).ToModel(userID, now)
+	assert.Equal(t, userID, link.CreatedBy)
+	assert.Equal(t, &storeID, link.StoreID)
+	assert.Nil(t, link.FilterQuery)
+	assert.Equal(t, time.Date(2026, 5, 8, 12, 0, 0, 0, time.UTC), link.ExpiresAt, "links last a week by default")
+
+	link = (&ShareLinkRequest{Scope: models.ShareScopeFilter, Filter: "tags=個室", ExpiresInDays: 1}).ToModel(userID, now)
+	require.NotNil(t, link.FilterQuery)
+	assert.Equal(t, "tags=個室", *link.FilterQuery)
+	assert.Equal(t, time.Date(2026, 5, 2, 12, 0, 0, 0, time.UTC), link.ExpiresAt)
+}
+
+func TestParseShareFilter(t *testing.T) {
+	h := NewShareLinkHandler(services.NewShareLinkService(nil, "test-secret"), &Handler{})
+
+	query, err := h.parseShareFilter("?tags=個室&categories=カフェ")
+	require.NoError(t, err)
+	values, err := url.ParseQuery(query)
+	require.NoError(t, err)
+	assert.Equal(t, "カフェ", values.Get("categories"))
+	assert.Equal(t, "個室", values.Get("tags"))
+
+	for _, filter := range []string{
+		"categories=カフェ&visited_by=me",
+		"list_id=" + uuid.New().String(),
+		"categories=カフェ&limit=10",
+		"sort=name",
+		"categories=カフェ&min_rating=9",
+		"categories=%zz",
+	} {
+		t.Run(filter, func(t *testing.T) {
+			_, err := h.parseShareFilter(filter)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestGetShared_InvalidToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewShareLinkHandler(services.NewShareLinkService(nil, "test-secret"), &Handler{})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/share/not-a-token", nil)
+	c.Params = gin.Params{{Key: "token", Value: "not-a-token"}}
+
+	h.GetShared(c)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sukimise/internal/constants"
+	"sukimise/internal/errors"
+	"sukimise/internal/models"
+	"sukimise/internal/repositories"
+	"sukimise/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StoreBulkRequest represents the request body of a bulk store operation.
+// When StoreIDs is empty, the stores are selected with the same query
+// parameters as GET /stores.
+type StoreBulkRequest struct {
+	StoreIDs  []uuid.UUID `json:"store_ids"`
+	Operation string      `json:"operation"`
+	Values    []string    `json:"values"`
+}
+
+// Validate validates the bulk operation
+func (r *StoreBulkRequest) Validate() error {
+	switch r.Operation {
+	case models.BulkOperationAddTags, models.BulkOperationRemoveTags:
+		if len(r.Values) == 0 {
+			return errors.NewValidationError("Tags are required", "Specify the tags in values")
+		}
+		if err := utils.ValidateStringArray(r.Values, "values", constants.MaxStoreTags); err != nil {
+			return err
+		}
+	case models.BulkOperationAddCategories, models.BulkOperationRemoveCategories:
+		if len(r.Values) == 0 {
+			return errors.NewValidationError("Categories are required", "Specify the categories in values")
+		}
+		if err := utils.ValidateStringArray(r.Values, "values", constants.MaxStoreCategories); err != nil {
+			return err
+		}
+	case models.BulkOperationDelete:
+	default:
+		return errors.NewValidationError("Invalid bulk operation", fmt.Sprintf("Operation must be one of %s", strings.Join([]string{
+			models.BulkOperationAddTags, models.BulkOperationRemoveTags,
+			models.BulkOperationAddCategories, models.BulkOperationRemoveCategories,
+			models.BulkOperationDelete,
+		}, ", ")))
+	}
+
+	if len(r.StoreIDs) > constants.MaxBulkStores {
+		return errors.NewValidationError("Too many stores", fmt.Sprintf("Maximum %d stores allowed", constants.MaxBulkStores))
+	}
+	return nil
+}
+
+// BulkUpdateStores applies one operation to many stores in a single transaction
+func (h *Handler) BulkUpdateStores(c *gin.Context) {
+	var req StoreBulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid request data", err.Error()))
+		return
+	}
+
+	for i, value := range req.Values {
+		req.Values[i] = strings.TrimSpace(value)
+	}
+
+	if err := req.Validate(); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	userRole, exists := c.Get("role")
+	if !exists {
+		errors.HandleError(c, errors.NewUnauthorizedError("User role not found in token"))
+		return
+	}
+
+	ids := req.StoreIDs
+	if len(ids) == 0 {
+		filter, err := h.storeFilterFromRequest(c)
+		if err != nil {
+			errors.HandleError(c, err)
+			return
+		}
+
+		// Never apply an operation to every store by accident
+		if !hasStoreFilterCriteria(filter) {
+			errors.HandleError(c, errors.NewValidationError("No stores selected", "Specify store_ids or filter query parameters"))
+			return
+		}
+
+		ids, err = h.storeService.GetStoreIDs(filter, constants.MaxBulkStores+1)
+		if err != nil {
+			log.Printf("Failed to get stores for bulk operation: %v", err)
+			errors.HandleError(c, errors.NewInternalError("Failed to get stores"))
+			return
+		}
+		if len(ids) > constants.MaxBulkStores {
+			errors.HandleError(c, errors.NewValidationError("Too many stores", fmt.Sprintf("The filter matches more than %d stores", constants.MaxBulkStores)))
+			return
+		}
+	}
+
+	operation := models.StoreBulkOperation{Operation: req.Operation, Values: req.Values}
+	results, err := h.storeService.BulkUpdateStores(ids, operation, userID.(uuid.UUID), userRole.(string) == constants.RoleAdmin)
+	if err != nil {
+		log.Printf("Failed to apply bulk operation %s: %v", req.Operation, err)
+		errors.HandleError(c, errors.NewInternalError("Failed to apply bulk operation"))
+		return
+	}
+
+	summary := map[string]int{}
+	for _, result := range results {
+		summary[result.Status]++
+	}
+
+	errors.SendSuccess(c, map[string]interface{}{
+		"operation": req.Operation,
+		"total":     len(results),
+		"summary":   summary,
+		"results":   results,
+	})
+}
+
+// hasStoreFilterCriteria reports whether the filter narrows down the stores at all
+func hasStoreFilterCriteria(filter *repositories.StoreFilter) bool {
+	return filter.Name != "" ||
+		filter.Query != "" ||
+		len(filter.Categories) > 0 ||
+		len(filter.Tags) > 0 ||
+		(filter.Latitude != nil && filter.Longitude != nil && filter.Radius != nil) ||
+		filter.BoundingBox != nil ||
+		filter.Polygon != "" ||
+		filter.BusinessDay != "" ||
+		filter.BusinessTime != "" ||
+		filter.OpenAt != nil ||
+		filter.MinRating != nil ||
+		filter.Visited != nil ||
+		filter.VisitedBy != nil ||
+		filter.MinPrice != nil ||
+		filter.MaxPrice != nil ||
+		filter.ListID != nil
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"sukimise/internal/constants"
+	"sukimise/internal/errors"
+	"sukimise/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStoreClusters returns the stores in the bbox grouped into clusters for a
+// map at zoom, or the stores themselves above constants.StoreClusterMaxZoom.
+// The other filters are the query parameters of GET /stores.
+func (h *Handler) GetStoreClusters(c *gin.Context) {
+	filter, err := h.storeFilterFromRequest(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	if filter.BoundingBox == nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid bbox", "bbox=minLng,minLat,maxLng,maxLat is required"))
+		return
+	}
+
+	zoom, err := strconv.Atoi(c.Query("zoom"))
+	if err != nil || zoom < 0 || zoom > constants.MaxMapZoom {
+		errors.HandleError(c, errors.NewValidationError("Invalid zoom", fmt.Sprintf("zoom must be an integer between 0 and %d", constants.MaxMapZoom)))
+		return
+	}
+
+	if zoom > constants.StoreClusterMaxZoom {
+		filter.Limit = constants.MaxMapStores
+		filter.Offset = 0
+		stores, next, err := h.storeService.GetStoresPage(filter)
+		if err != nil {
+			log.Printf("Failed to get stores for map: %v", err)
+			errors.HandleError(c, errors.NewInternalError("Failed to get stores"))
+			return
+		}
+		if stores == nil {
+			stores = []*models.Store{}
+		}
+
+		errors.SendSuccess(c, map[string]interface{}{
+			"zoom":      zoom,
+			"clusters":  []*models.StoreCluster{},
+			"stores":    stores,
+			"truncated": next != nil, // More than MaxMapStores stores are in the bbox
+		})
+		return
+	}
+
+	clusters, err := h.storeService.GetStoreClusters(filter, zoom)
+	if err != nil {
+		log.Printf("Failed to get store clusters: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get store clusters"))
+		return
+	}
+	if clusters == nil {
+		clusters = []*models.StoreCluster{}
+	}
+
+	customizations, err := h.categoryCustomizationService.GetAllCategoryCustomizations()
+	if err != nil {
+		log.Printf("Failed to get category customizations: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get category customizations"))
+		return
+	}
+	styleClusterCategories(clusters, customizations)
+
+	errors.SendSuccess(c, map[string]interface{}{
+		"zoom":      zoom,
+		"clusters":  clusters,
+		"stores":    []*models.Store{},
+		"truncated": false,
+	})
+}
+
+// styleClusterCategories sets the icon and color of each cluster category from its customization
+func styleClusterCategories(clusters []*models.StoreCluster, customizations []*models.CategoryCustomization) {
+	byName := customizationsByName(customizations)
+	for _, cluster := range clusters {
+		for i, category := range cluster.Categories {
+			if customization, ok := byName[category.Name]; ok {
+				cluster.Categories[i].Icon = customization.Icon
+				cluster.Categories[i].Color = customization.Color
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sukimise/internal/models"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetStoreClusters_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &Handler{}
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "missing bbox", query: "zoom=10"},
+		{name: "malformed bbox", query: "bbox=139.7,35.6&zoom=10"},
+		{name: "missing zoom", query: "bbox=139.7,35.6,139.8,35.7"},
+		{name: "zoom out of range", query: "bbox=139.7,35.6,139.8,35.7&zoom=23"},
+		{name: "swapped corners", query: "bbox=139.8,35.6,139.7,35.7&zoom=10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/stores/clusters?"+tt.query, nil)
+
+			h.GetStoreClusters(c)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestStyleClusterCategories(t *testing.T) {
+	icon, color := "☕", "#8B4513"
+	clusters := []*models.StoreCluster{
+		{Count: 2, Categories: []models.StoreClusterCategory{{Name: "カフェ", Count: 2}, {Name: "未設定", Count: 1}}},
+	}
+
+	styleClusterCategories(clusters, []*models.CategoryCustomization{{CategoryName: "カフェ", Icon: &icon, Color: &color}})
+
+	assert.Equal(t, &icon, clusters[0].Categories[0].Icon)
+	assert.Equal(t, &color, clusters[0].Categories[0].Color)
+	assert.Nil(t, clusters[0].Categories[1].Icon)
+	assert.Nil(t, clusters[0].Categories[1].Color)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sukimise/internal/constants"
+	"sukimise/internal/models"
+	mocks "sukimise/internal/repositories/mocks"
+	"sukimise/internal/services"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func createStoreRequest(t *testing.T, query, role string) (*gin.Context, *httptest.ResponseRecorder) {
+	body, err := json.Marshal(map[string]interface{}{
+		"name":      "喫茶すきま",
+		"address":   "東京都千代田区",
+		"latitude":  35.681,
+		"longitude": 139.767,
+	})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/stores"+query, bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Set("user_id", uuid.New())
+	c.Set("role", role)
+	return c, w
+}
+
+func TestCreateStore_Duplicate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storeRepo := mocks.NewMockStoreRepositoryInterface(ctrl)
+	policy := models.DuplicatePolicy{Radius: 100, NameMatch: models.DuplicateNameNormalized}
+	h := &Handler{storeService: services.NewStoreService(storeRepo, policy)}
+
+	duplicate := &models.Store{ID: uuid.New(), Name: "喫茶 すきま", CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+	storeRepo.EXPECT().FindDuplicateByLocationAndName("喫茶すきま", 35.681, 139.767, policy).Return(duplicate, nil)
+
+	c, w := createStoreRequest(t, "", constants.RoleEditor)
+	h.CreateStore(c)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	var response struct {
+		Success bool                          `json:"success"`
+		Data    models.StoreDuplicateConflict `json:"data"`
+		Error   struct {
+			Code    string `json:"code"`
+			Details string `json:"details"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	assert.False(t, response.Success)
+	assert.Equal(t, constants.ErrorCodeConflict, response.Error.Code)
+	assert.Contains(t, response.Error.Details, "似た名前「喫茶 すきま」")
+	assert.Equal(t, duplicate.ID, response.Data.DuplicateID)
+	require.NotNil(t, response.Data.Duplicate)
+	assert.Equal(t, "喫茶 すきま", response.Data.Duplicate.Name)
+	assert.Equal(t, policy, response.Data.Policy)
+}
+
+func TestCreateStore_Force(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("editor skips the duplicate check", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		storeRepo := mocks.NewMockStoreRepositoryInterface(ctrl)
+		h := &Handler{storeService: services.NewStoreService(storeRepo, models.DefaultDuplicatePolicy)}
+
+		storeRepo.EXPECT().FindDuplicateByLocationAndName(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+		storeRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(store *models.Store) error {
+			assert.Equal(t, "喫茶すきま", store.Name)
+			store.ID = uuid.New()
+			return nil
+		})
+
+		c, w := createStoreRequest(t, "?force=true", constants.RoleEditor)
+		h.CreateStore(c)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("viewer cannot skip the duplicate check", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		storeRepo := mocks.NewMockStoreRepositoryInterface(ctrl)
+		h := &Handler{storeService: services.NewStoreService(storeRepo, models.DefaultDuplicatePolicy)}
+
+		c, w := createStoreRequest(t, "?force=true", constants.RoleViewer)
+		h.CreateStore(c)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sukimise/internal/errors"
+	"sukimise/internal/models"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// kmlPinIcon is a white pin that map apps tint with the style color
+const kmlPinIcon = "https://maps.google.com/mapfiles/kml/paddle/wht-blank.png"
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONPoint           `json:"geometry"`
+	Properties storeFeatureProperties `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// storeFeatureProperties are the properties of an exported store. marker-color
+// follows the simplestyle spec, so that viewers such as geojson.io color the pins.
+type storeFeatureProperties struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Address       string    `json:"address"`
+	Categories    []string  `json:"categories"`
+	Tags          []string  `json:"tags"`
+	AverageRating *float64  `json:"average_rating"`
+	ReviewCount   int       `json:"review_count"`
+	ParkingInfo   string    `json:"parking_info,omitempty"`
+	WebsiteURL    string    `json:"website_url,omitempty"`
+	GoogleMapURL  string    `json:"google_map_url,omitempty"`
+	Icon          *string   `json:"icon"`
+	Color         *string   `json:"color"`
+	MarkerColor   string    `json:"marker-color,omitempty"`
+}
+
+type kmlRoot struct {
+	XMLName   xml.Name    `xml:"kml"`
+	Namespace string      `xml:"xmlns,attr"`
+	Document  kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name       string         `xml:"name"`
+	Styles     []kmlStyle     `xml:"Style"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlStyle struct {
+	ID        string       `xml:"id,attr"`
+	IconStyle kmlIconStyle `xml:"IconStyle"`
+}
+
+type kmlIconStyle struct {
+	Color string  `xml:"color"`
+	Icon  kmlIcon `xml:"Icon"`
+}
+
+type kmlIcon struct {
+	Href string `xml:"href"`
+}
+
+type kmlPlacemark struct {
+	Name         string    `xml:"name"`
+	Description  string    `xml:"description"`
+	StyleURL     string    `xml:"styleUrl,omitempty"`
+	ExtendedData []kmlData `xml:"ExtendedData>Data"`
+	Point        kmlPoint  `xml:"Point"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlPoint struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+// ExportStoresGeoJSON exports the stores selected by the GET /stores filters
+// as a GeoJSON FeatureCollection, styled with the category customizations
+func (h *Handler) ExportStoresGeoJSON(c *gin.Context) {
+	stores, customizations, ok := h.getStoresForExport(c, "GeoJSON")
+	if !ok {
+		return
+	}
+
+	body, err := json.Marshal(buildStoresGeoJSON(stores, customizations))
+	if err != nil {
+		log.Printf("Failed to encode GeoJSON: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to generate GeoJSON"))
+		return
+	}
+
+	filename := fmt.Sprintf("sukimise_stores_%s.geojson", time.Now().Format("20060102_150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(200, "application/geo+json", body)
+
+	log.Printf("GeoJSON export completed: %d stores exported", len(stores))
+}
+
+// ExportStoresKML exports the stores selected by the GET /stores filters as
+// KML for Google My Maps or GPS apps, with pins colored by category
+func (h *Handler) ExportStoresKML(c *gin.Context) {
+	stores, customizations, ok := h.getStoresForExport(c, "KML")
+	if !ok {
+		return
+	}
+
+	body, err := xml.MarshalIndent(buildStoresKML(stores, customizations), "", "  ")
+	if err != nil {
+		log.Printf("Failed to encode KML: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to generate KML"))
+		return
+	}
+
+	filename := fmt.Sprintf("sukimise_stores_%s.kml", time.Now().Format("20060102_150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(200, "application/vnd.google-earth.kml+xml", append([]byte(xml.Header), body...))
+
+	log.Printf("KML export completed: %d stores exported", len(stores))
+}
+
+// getStoresForExport returns the stores selected by the request filters and
+// the category customizations by name. On failure it responds with the error.
+func (h *Handler) getStoresForExport(c *gin.Context, format string) ([]*models.Store, map[string]*models.CategoryCustomization, bool) {
+	filter, err := h.storeFilterFromRequest(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return nil, nil, false
+	}
+
+	// Exports are opened in other map apps, so they hold every matching store
+	stores, err := h.storeService.GetAllStores(filter)
+	if err != nil {
+		log.Printf("Failed to get stores for %s export: %v", format, err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get stores"))
+		return nil, nil, false
+	}
+
+	customizations, err := h.categoryCustomizationService.GetAllCategoryCustomizations()
+	if err != nil {
+		log.Printf("Failed to get category customizations for %s export: %v", format, err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get category customizations"))
+		return nil, nil, false
+	}
+
+	return stores, customizationsByName(customizations), true
+}
+
+// customizationsByName indexes category customizations by category name
+func customizationsByName(customizations []*models.CategoryCustomization) map[string]*models.CategoryCustomization {
+	byName := make(map[string]*models.CategoryCustomization, len(customizations))
+	for _, customization := range customizations {
+		byName[customization.CategoryName] = customization
+	}
+	return byName
+}
+
+// storeStyle returns the icon and color of the first category of the store that has them
+func storeStyle(store *models.Store, customizations map[string]*models.CategoryCustomization) (icon, color *string) {
+	for _, category := range store.Categories {
+		customization, ok := customizations[category]
+		if !ok {
+			continue
+		}
+		if icon == nil {
+			icon = customization.Icon
+		}
+		if color == nil {
+			color = customization.Color
+		}
+		if icon != nil && color != nil {
+			break
+		}
+	}
+	return icon, color
+}
+
+// buildStoresGeoJSON converts stores into a FeatureCollection of points
+func buildStoresGeoJSON(stores []*models.Store, customizations map[string]*models.CategoryCustomization) geoJSONFeatureCollection {
+	collection := geoJSONFeatureCollection{Type: "FeatureCollection", Features: make([]geoJSONFeature, 0, len(stores))}
+	for _, store := range stores {
+		icon, color := storeStyle(store, customizations)
+		properties := storeFeatureProperties{
+			ID:           store.ID,
+			Name:         store.Name,
+			Address:      store.Address,
+			Categories:   nonNilStrings(store.Categories),
+			Tags:         nonNilStrings(store.Tags),
+			ParkingInfo:  store.ParkingInfo,
+			WebsiteURL:   store.WebsiteURL,
+			GoogleMapURL: store.GoogleMapURL,
+			Icon:         icon,
+			Color:        color,
+		}
+		if store.ReviewSummary != nil {
+			properties.AverageRating = store.ReviewSummary.AverageRating
+			properties.ReviewCount = store.ReviewSummary.ReviewCount
+		}
+		if color != nil {
+			properties.MarkerColor = *color
+		}
+
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geoJSONPoint{Type: "Point", Coordinates: [2]float64{store.Longitude, store.Latitude}},
+			Properties: properties,
+		})
+	}
+	return collection
+}
+
+// buildStoresKML converts stores into KML placemarks with one pin style per category color
+func buildStoresKML(stores []*models.Store, customizations map[string]*models.CategoryCustomization) kmlRoot {
+	document := kmlDocument{Name: "Sukimise"}
+	styles := map[string]string{} // KML color by style ID
+
+	for _, store := range stores {
+		icon, color := storeStyle(store, customizations)
+
+		placemark := kmlPlacemark{
+			Name:        store.Name,
+			Description: storeKMLDescription(store),
+			Point:       kmlPoint{Coordinates: fmt.Sprintf("%.6f,%.6f", store.Longitude, store.Latitude)},
+		}
+		if color != nil {
+			if kmlColor, ok := toKMLColor(*color); ok {
+				styleID := "color-" + strings.ToLower(strings.TrimPrefix(*color, "#"))
+				styles[styleID] = kmlColor
+				placemark.StyleURL = "#" + styleID
+			}
+		}
+
+		placemark.ExtendedData = append(placemark.ExtendedData,
+			kmlData{Name: "id", Value: store.ID.String()},
+			kmlData{Name: "categories", Value: strings.Join(store.Categories, ", ")},
+			kmlData{Name: "tags", Value: strings.Join(store.Tags, ", ")},
+		)
+		if store.ReviewSummary != nil && store.ReviewSummary.AverageRating != nil {
+			placemark.ExtendedData = append(placemark.ExtendedData, kmlData{Name: "average_rating", Value: fmt.Sprintf("%.1f", *store.ReviewSummary.AverageRating)})
+		}
+		if icon != nil {
+			placemark.ExtendedData = append(placemark.ExtendedData, kmlData{Name: "icon", Value: *icon})
+		}
+
+		document.Placemarks = append(document.Placemarks, placemark)
+	}
+
+	styleIDs := make([]string, 0, len(styles))
+	for id := range styles {
+		styleIDs = append(styleIDs, id)
+	}
+	sort.Strings(styleIDs)
+	for _, id := range styleIDs {
+		document.Styles = append(document.Styles, kmlStyle{
+			ID:        id,
+			IconStyle: kmlIconStyle{Color: styles[id], Icon: kmlIcon{Href: kmlPinIcon}},
+		})
+	}
+
+	return kmlRoot{Namespace: "http://www.opengis.net/kml/2.2", Document: document}
+}
+
+// storeKMLDescription is the text shown in the balloon of a placemark
+func storeKMLDescription(store *models.Store) string {
+	lines := []string{store.Address}
+	if len(store.Categories) > 0 {
+		lines = append(lines, "カテゴリ: "+strings.Join(store.Categories, ", "))
+	}
+	if len(store.Tags) > 0 {
+		lines = append(lines, "タグ: "+strings.Join(store.Tags, ", "))
+	}
+	if store.ReviewSummary != nil && store.ReviewSummary.AverageRating != nil {
+		lines = append(lines, fmt.Sprintf("評価: %.1f（%d件）", *store.ReviewSummary.AverageRating, store.ReviewSummary.ReviewCount))
+	}
+	if store.WebsiteURL != "" {
+		lines = append(lines, store.WebsiteURL)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// toKMLColor converts #RRGGBB to the opaque aabbggrr form used by KML
+func toKMLColor(color string) (string, bool) {
+	if len(color) != 7 || color[0] != '#' {
+		return "", false
+	}
+	for _, r := range color[1:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return "", false
+		}
+	}
+	hex := strings.ToLower(color[1:])
+	return "ff" + hex[4:6] + hex[2:4] + hex[0:2], true
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sukimise/internal/constants"
+	"sukimise/internal/models"
+	"sukimise/internal/repositories"
+	mocks "sukimise/internal/repositories/mocks"
+	"sukimise/internal/services"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func exportTestData() ([]*models.Store, map[string]*models.CategoryCustomization) {
+	icon, color := "☕", "#8B4513"
+	rating := 4.5
+	stores := []*models.Store{
+		{
+			ID:            uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			Name:          "喫茶 <すきま>",
+			Address:       "東京都千代田区",
+			Latitude:      35.681,
+			Longitude:     139.767,
+			Categories:    []string{"未設定", "カフェ"},
+			Tags:          []string{"静か"},
+			ReviewSummary: &models.StoreReviewSummary{AverageRating: &rating, ReviewCount: 2},
+		},
+		{
+			ID:        uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			Name:      "食堂",
+			Latitude:  35.6,
+			Longitude: 139.7,
+		},
+	}
+	customizations := customizationsByName([]*models.CategoryCustomization{{CategoryName: "カフェ", Icon: &icon, Color: &color}})
+	return stores, customizations
+}
+
+func TestBuildStoresGeoJSON(t *testing.T) {
+	stores, customizations := exportTestData()
+
+	body, err := json.Marshal(buildStoresGeoJSON(stores, customizations))
+	require.NoError(t, err)
+
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry struct {
+				Type        string    `json:"type"`
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	require.NoError(t, json.Unmarshal(body, &collection))
+
+	assert.Equal(t, "FeatureCollection", collection.Type)
+	require.Len(t, collection.Features, 2)
+
+	cafe := collection.Features[0]
+	assert.Equal(t, "Point", cafe.Geometry.Type)
+	assert.Equal(t, []float64{139.767, 35.681}, cafe.Geometry.Coordinates)
+	assert.Equal(t, []interface{}{"未設定", "カフェ"}, cafe.Properties["categories"])
+	assert.Equal(t, 4.5, cafe.Properties["average_rating"])
+	assert.Equal(t, "☕", cafe.Properties["icon"])
+	assert.Equal(t, "#8B4513", cafe.Properties["color"])
+	assert.Equal(t, "#8B4513", cafe.Properties["marker-color"])
+
+	plain := collection.Features[1]
+	assert.Equal(t, []interface{}{}, plain.Properties["categories"])
+	assert.Nil(t, plain.Properties["average_rating"])
+	assert.Nil(t, plain.Properties["color"])
+	assert.NotContains(t, plain.Properties, "marker-color")
+}
+
+func TestBuildStoresKML(t *testing.T) {
+	stores, customizations := exportTestData()
+
+	body, err := xml.Marshal(buildStoresKML(stores, customizations))
+	require.NoError(t, err)
+
+	var kml kmlRoot
+	require.NoError(t, xml.Unmarshal(body, &kml))
+
+	require.Len(t, kml.Document.Styles, 1)
+	assert.Equal(t, "color-8b4513", kml.Document.Styles[0].ID)
+	assert.Equal(t, "ff13458b", kml.Document.Styles[0].IconStyle.Color)
+
+	require.Len(t, kml.Document.Placemarks, 2)
+	cafe := kml.Document.Placemarks[0]
+	assert.Equal(t, "喫茶 <すきま>", cafe.Name)
+	assert.Equal(t, "#color-8b4513", cafe.StyleURL)
+	assert.Equal(t, "139.767000,35.681000", cafe.Point.Coordinates)
+	assert.Contains(t, cafe.ExtendedData, kmlData{Name: "average_rating", Value: "4.5"})
+	assert.Contains(t, cafe.ExtendedData, kmlData{Name: "icon", Value: "☕"})
+
+	assert.Empty(t, kml.Document.Placemarks[1].StyleURL)
+}
+
+func TestToKMLColor(t *testing.T) {
+	color, ok := toKMLColor("#FF8000")
+	assert.True(t, ok)
+	assert.Equal(t, "ff0080ff", color)
+
+	for _, invalid := range []string{"", "FF8000", "#FF80", "#GG8000"} {
+		_, ok := toKMLColor(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+func TestExportStoresGeoJSON_AllPages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	storeRepo := mocks.NewMockStoreRepositoryInterface(ctrl)
+	customizationRepo := mocks.NewMockCategoryCustomizationRepositoryInterface(ctrl)
+	h := &Handler{
+		storeService:                 services.NewStoreService(storeRepo, models.DefaultDuplicatePolicy),
+		categoryCustomizationService: services.NewCategoryCustomizationService(customizationRepo),
+	}
+
+	newStores := func(from, count int) []*models.Store {
+		stores := make([]*models.Store, count)
+		for i := range stores {
+			stores[i] = &models.Store{ID: uuid.New(), Name: fmt.Sprintf("店舗%d", from+i), Latitude: 35.6, Longitude: 139.7}
+		}
+		return stores
+	}
+	cursor := &repositories.StoreCursor{Order: repositories.StoreOrderCreatedAt, ID: uuid.New()}
+
+	gomock.InOrder(
+		storeRepo.EXPECT().GetPage(gomock.Any()).DoAndReturn(func(filter *repositories.StoreFilter) ([]*models.Store, *repositories.StoreCursor, error) {
+			assert.Equal(t, constants.MaxLimit, filter.Limit)
+			assert.Nil(t, filter.Cursor)
+			return newStores(0, constants.MaxLimit), cursor, nil
+		}),
+		storeRepo.EXPECT().GetPage(gomock.Any()).DoAndReturn(func(filter *repositories.StoreFilter) ([]*models.Store, *repositories.StoreCursor, error) {
+			assert.Equal(t, cursor, filter.Cursor)
+			return newStores(constants.MaxLimit, 5), nil, nil
+		}),
+	)
+	customizationRepo.EXPECT().GetAll().Return([]*models.CategoryCustomization{}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/stores/export/geojson?limit=20", nil)
+
+	h.ExportStoresGeoJSON(c)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var collection struct {
+		Features []json.RawMessage `json:"features"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &collection))
+	assert.Len(t, collection.Features, constants.MaxLimit+5)
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sukimise/internal/repositories"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFilterTestContext(query string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/v1/stores?"+query, nil)
+	return c
+}
+
+func TestParseStoreFilter_Query(t *testing.T) {
+	h := &Handler{}
+
+	filter := h.parseStoreFilter(newFilterTestContext("q=%20%E3%83%A9%E3%83%BC%E3%83%A1%E3%83%B3%20"))
+	assert.Equal(t, "ラーメン", filter.Query)
+	assert.NoError(t, h.validateStoreFilter(filter))
+	assert.True(t, hasStoreFilterCriteria(filter))
+
+	filter = h.parseStoreFilter(newFilterTestContext("q=" + strings.Repeat("a", 101)))
+	assert.Error(t, h.validateStoreFilter(filter))
+}
+
+func TestParseStoreFilter_Sort(t *testing.T) {
+	h := &Handler{}
+
+	tests := []struct {
+		query             string
+		expectedSort      string
+		expectedAscending bool
+		wantErr           bool
+	}{
+		{query: "", expectedSort: "", expectedAscending: false},
+		{query: "sort=avg_rating", expectedSort: "avg_rating", expectedAscending: false},
+		{query: "sort=name", expectedSort: "name", expectedAscending: true},
+		{query: "sort=name&order=desc", expectedSort: "name", expectedAscending: false},
+		{query: "sort=review_count&order=asc", expectedSort: "review_count", expectedAscending: true},
+		{query: "sort=price", expectedSort: "price", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			filter := h.parseStoreFilter(newFilterTestContext(tt.query))
+			assert.Equal(t, tt.expectedSort, filter.Sort)
+			if tt.wantErr {
+				assert.Error(t, h.validateStoreFilter(filter))
+				return
+			}
+			assert.Equal(t, tt.expectedAscending, filter.SortAscending)
+			assert.NoError(t, h.validateStoreFilter(filter))
+		})
+	}
+}
+
+func TestParseStoreFilter_ReviewFilters(t *testing.T) {
+	h := &Handler{}
+
+	filter := h.parseStoreFilter(newFilterTestContext("min_rating=4&visited=false&min_price=1000&max_price=1500"))
+	require.NotNil(t, filter.MinRating)
+	assert.Equal(t, 4.0, *filter.MinRating)
+	require.NotNil(t, filter.Visited)
+	assert.False(t, *filter.Visited)
+	require.NotNil(t, filter.MinPrice)
+	assert.Equal(t, 1000, *filter.MinPrice)
+	require.NotNil(t, filter.MaxPrice)
+	assert.Equal(t, 1500, *filter.MaxPrice)
+	assert.NoError(t, h.validateStoreFilter(filter))
+	assert.True(t, hasStoreFilterCriteria(filter))
+
+	for _, query := range []string{"min_rating=6", "min_rating=0", "min_price=-1", "min_price=2000&max_price=1000"} {
+		filter := h.parseStoreFilter(newFilterTestContext(query))
+		assert.Error(t, h.validateStoreFilter(filter), query)
+	}
+}
+
+func TestApplyVisitedBy(t *testing.T) {
+	h := &Handler{}
+	userID := uuid.New()
+
+	c := newFilterTestContext("visited_by=me")
+	c.Set("user_id", userID)
+	filter := h.parseStoreFilter(c)
+	require.NoError(t, h.applyVisitedBy(c, filter))
+	require.NotNil(t, filter.VisitedBy)
+	assert.Equal(t, userID, *filter.VisitedBy)
+
+	c = newFilterTestContext("visited_by=me")
+	assert.Error(t, h.applyVisitedBy(c, h.parseStoreFilter(c)), "anonymous users")
+
+	c = newFilterTestContext("visited_by=" + userID.String())
+	c.Set("user_id", userID)
+	assert.Error(t, h.applyVisitedBy(c, h.parseStoreFilter(c)), "only me is supported")
+
+	c = newFilterTestContext("")
+	filter = h.parseStoreFilter(c)
+	require.NoError(t, h.applyVisitedBy(c, filter))
+	assert.Nil(t, filter.VisitedBy)
+}
+
+func TestApplyListFilter(t *testing.T) {
+	h := &Handler{}
+
+	c := newFilterTestContext("list_id=abc")
+	assert.Error(t, h.applyListFilter(c, h.parseStoreFilter(c)), "invalid list ID")
+
+	c = newFilterTestContext("")
+	filter := h.parseStoreFilter(c)
+	require.NoError(t, h.applyListFilter(c, filter))
+	assert.Nil(t, filter.ListID)
+
+	listID := uuid.New()
+	assert.True(t, hasStoreFilterCriteria(&repositories.StoreFilter{ListID: &listID}))
+}
+
+func TestStoreFilterFromRequest(t *testing.T) {
+	h := &Handler{}
+	userID := uuid.New()
+
+	c := newFilterTestContext("q=ラーメン&visited_by=me")
+	c.Set("user_id", userID)
+	filter, err := h.storeFilterFromRequest(c)
+	require.NoError(t, err)
+	assert.Equal(t, "ラーメン", filter.Query)
+	require.NotNil(t, filter.VisitedBy)
+	assert.Equal(t, userID, *filter.VisitedBy)
+
+	for _, query := range []string{"min_rating=6", "visited_by=me", "list_id=abc"} {
+		_, err := h.storeFilterFromRequest(newFilterTestContext(query))
+		assert.Error(t, err, query)
+	}
+}
+
+func TestParseStoreFilter_OpenAt(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	h := &Handler{location: tokyo}
+
+	// 2024-05-06T16:30:00Z is Tuesday 01:30 in Tokyo
+	filter := h.parseStoreFilter(newFilterTestContext("open_at=2024-05-06T16:30:00Z&last_order_margin=30"))
+	require.NotNil(t, filter.OpenAt)
+	assert.Equal(t, time.Tuesday, filter.OpenAt.Weekday())
+	assert.Equal(t, 1, filter.OpenAt.Hour())
+	assert.Equal(t, 30, filter.OpenAt.Minute())
+	assert.Equal(t, 30, filter.LastOrderMargin)
+	assert.True(t, hasStoreFilterCriteria(filter))
+
+	// An unescaped "+" in the offset is decoded as a space
+	filter = h.parseStoreFilter(newFilterTestContext("open_at=2024-05-07T01:30:00+09:00"))
+	require.NotNil(t, filter.OpenAt)
+	assert.Equal(t, 1, filter.OpenAt.Hour())
+	assert.Equal(t, tokyo, filter.OpenAt.Location())
+
+	filter = h.parseStoreFilter(newFilterTestContext("open_now=true"))
+	require.NotNil(t, filter.OpenAt)
+	assert.Equal(t, tokyo, filter.OpenAt.Location())
+	assert.WithinDuration(t, time.Now(), *filter.OpenAt, time.Minute)
+
+	filter = h.parseStoreFilter(newFilterTestContext("open_now=false"))
+	assert.Nil(t, filter.OpenAt)
+}
+
+func TestParseStoreFilter_BusinessDate(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	h := &Handler{location: tokyo}
+
+	filter := h.parseStoreFilter(newFilterTestContext("business_day=tuesday"))
+	assert.Equal(t, "tuesday", filter.BusinessDay)
+	require.NotNil(t, filter.BusinessDate)
+	assert.Equal(t, time.Tuesday, filter.BusinessDate.Weekday())
+	today := time.Now().In(tokyo)
+	assert.False(t, filter.BusinessDate.Before(time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, tokyo)))
+	assert.True(t, filter.BusinessDate.Before(today.AddDate(0, 0, 7)))
+
+	// business_date overrides business_day
+	filter = h.parseStoreFilter(newFilterTestContext("business_day=tuesday&business_date=2025-01-01"))
+	assert.Equal(t, "wednesday", filter.BusinessDay)
+	require.NotNil(t, filter.BusinessDate)
+	assert.Equal(t, "2025-01-01", filter.BusinessDate.Format("2006-01-02"))
+
+	// Unknown days are left to validation
+	filter = h.parseStoreFilter(newFilterTestContext("business_day=someday"))
+	assert.Equal(t, "someday", filter.BusinessDay)
+	assert.Nil(t, filter.BusinessDate)
+	assert.Error(t, h.validateStoreFilter(filter))
+}
+
+func TestNextDateOfWeekday(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	wednesday := time.Date(2025, 1, 1, 23, 30, 0, 0, tokyo)
+
+	date, ok := nextDateOfWeekday(wednesday, "wednesday")
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, tokyo), date)
+
+	date, ok = nextDateOfWeekday(wednesday, "tuesday")
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 1, 7, 0, 0, 0, 0, tokyo), date)
+
+	_, ok = nextDateOfWeekday(wednesday, "")
+	assert.False(t, ok)
+}
+
+func TestParseStoreFilter_BoundingBox(t *testing.T) {
+	h := &Handler{}
+
+	filter := h.parseStoreFilter(newFilterTestContext("bbox=139.70,35.65,%20139.75,35.70"))
+	require.NotNil(t, filter.BoundingBox)
+	assert.Equal(t, 139.70, filter.BoundingBox.MinLongitude)
+	assert.Equal(t, 35.65, filter.BoundingBox.MinLatitude)
+	assert.Equal(t, 139.75, filter.BoundingBox.MaxLongitude)
+	assert.Equal(t, 35.70, filter.BoundingBox.MaxLatitude)
+	assert.NoError(t, h.validateStoreFilter(filter))
+	assert.True(t, hasStoreFilterCriteria(filter))
+
+	// Unparseable boxes are ignored like other malformed parameters
+	filter = h.parseStoreFilter(newFilterTestContext("bbox=139.70,35.65,139.75"))
+	assert.Nil(t, filter.BoundingBox)
+
+	// Swapped corners and out-of-range coordinates are rejected
+	filter = h.parseStoreFilter(newFilterTestContext("bbox=139.75,35.65,139.70,35.70"))
+	assert.Error(t, h.validateStoreFilter(filter))
+	filter = h.parseStoreFilter(newFilterTestContext("bbox=35.65,139.70,35.70,139.75"))
+	assert.Error(t, h.validateStoreFilter(filter))
+}
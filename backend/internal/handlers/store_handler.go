@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/csv"
 	"fmt"
 	"log"
@@ -13,6 +14,7 @@ import (
 	"sukimise/internal/types"
 	"sukimise/internal/utils"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -20,18 +22,19 @@ import (
 
 // StoreRequest represents the request body for store operations
 type StoreRequest struct {
-	Name          string                   `json:"name"`
-	Address       string                   `json:"address"`
-	Latitude      float64                  `json:"latitude"`
-	Longitude     float64                  `json:"longitude"`
-	Categories    []string                 `json:"categories"`
-	BusinessHours models.BusinessHoursData `json:"business_hours"`
-	ParkingInfo   string                   `json:"parking_info"`
-	WebsiteURL    string                   `json:"website_url"`
-	GoogleMapURL  string                   `json:"google_map_url"`
-	SnsUrls       []string                 `json:"sns_urls"`
-	Tags          []string                 `json:"tags"`
-	Photos        []string                 `json:"photos"`
+	Name                   string                        `json:"name"`
+	Address                string                        `json:"address"`
+	Latitude               float64                       `json:"latitude"`
+	Longitude              float64                       `json:"longitude"`
+	Categories             []string                      `json:"categories"`
+	BusinessHours          models.BusinessHoursData      `json:"business_hours"`
+	BusinessHourExceptions models.BusinessHourExceptions `json:"business_hour_exceptions"`
+	ParkingInfo            string                        `json:"parking_info"`
+	WebsiteURL             string                        `json:"website_url"`
+	GoogleMapURL           string                        `json:"google_map_url"`
+	SnsUrls                []string                      `json:"sns_urls"`
+	Tags                   []string                      `json:"tags"`
+	Photos                 []string                      `json:"photos"`
 }
 
 // ValidateForCreate validates store data for creation
@@ -61,13 +64,13 @@ func (r *StoreRequest) validate() error {
 	if err := utils.ValidateURL(r.GoogleMapURL); err != nil {
 		return err
 	}
-	if err := utils.ValidateStringArray(r.Categories, "categories", 10); err != nil {
+	if err := utils.ValidateStringArray(r.Categories, "categories", constants.MaxStoreCategories); err != nil {
 		return err
 	}
-	if err := utils.ValidateStringArray(r.Tags, "tags", 20); err != nil {
+	if err := utils.ValidateStringArray(r.Tags, "tags", constants.MaxStoreTags); err != nil {
 		return err
 	}
-	if err := utils.ValidateStringArray(r.Photos, "photos", 20); err != nil {
+	if err := utils.ValidateStringArray(r.Photos, "photos", constants.MaxStorePhotos); err != nil {
 		return err
 	}
 	for _, url := range r.SnsUrls {
@@ -75,25 +78,36 @@ func (r *StoreRequest) validate() error {
 			return err
 		}
 	}
+	if err := r.BusinessHours.Validate(); err != nil {
+		return errors.NewValidationError("Invalid business hours", err.Error())
+	}
+	if err := r.BusinessHourExceptions.Validate(); err != nil {
+		return errors.NewValidationError("Invalid business hour exceptions", err.Error())
+	}
 	return nil
 }
 
 // ToModel converts StoreRequest to Store model
 func (r *StoreRequest) ToModel(userID uuid.UUID) *models.Store {
+	exceptions := r.BusinessHourExceptions
+	if exceptions == nil {
+		exceptions = models.BusinessHourExceptions{}
+	}
 	return &models.Store{
-		Name:          r.Name,
-		Address:       r.Address,
-		Latitude:      r.Latitude,
-		Longitude:     r.Longitude,
-		Categories:    models.StringArray(r.Categories),
-		BusinessHours: r.BusinessHours,
-		ParkingInfo:   r.ParkingInfo,
-		WebsiteURL:    r.WebsiteURL,
-		GoogleMapURL:  r.GoogleMapURL,
-		SnsUrls:       models.StringArray(r.SnsUrls),
-		Tags:          models.StringArray(r.Tags),
-		Photos:        models.StringArray(r.Photos),
-		CreatedBy:     userID,
+		Name:                   r.Name,
+		Address:                r.Address,
+		Latitude:               r.Latitude,
+		Longitude:              r.Longitude,
+		Categories:             models.StringArray(r.Categories),
+		BusinessHours:          r.BusinessHours,
+		BusinessHourExceptions: exceptions,
+		ParkingInfo:            r.ParkingInfo,
+		WebsiteURL:             r.WebsiteURL,
+		GoogleMapURL:           r.GoogleMapURL,
+		SnsUrls:                models.StringArray(r.SnsUrls),
+		Tags:                   models.StringArray(r.Tags),
+		Photos:                 models.StringArray(r.Photos),
+		CreatedBy:              userID,
 	}
 }
 
@@ -113,6 +127,10 @@ func (r *StoreRequest) UpdateModel(store *models.Store) {
 	}
 	// Always update business hours since it's a structured object
 	store.BusinessHours = r.BusinessHours
+	// Exceptions are kept unless the request contains them
+	if r.BusinessHourExceptions != nil {
+		store.BusinessHourExceptions = r.BusinessHourExceptions
+	}
 	if r.ParkingInfo != "" {
 		store.ParkingInfo = r.ParkingInfo
 	}
@@ -157,19 +175,36 @@ func (h *Handler) CreateStore(c *gin.Context) {
 
 	store := req.ToModel(userID.(uuid.UUID))
 
-	// Check for duplicate stores before creating
-	duplicate, err := h.storeService.CheckForDuplicate(store.Name, store.Latitude, store.Longitude)
-	if err != nil {
-		log.Printf("Failed to check for duplicates: %v", err)
-		errors.HandleError(c, errors.NewInternalError("Failed to check for duplicates"))
-		return
-	}
+	// Check for duplicate stores before creating, unless the editor insists
+	if c.Query("force") == "true" {
+		if role := c.GetString("role"); role != constants.RoleEditor && role != constants.RoleAdmin {
+			errors.HandleError(c, errors.NewForbiddenError("Only editors can skip the duplicate check"))
+			return
+		}
+		log.Printf("Skipping duplicate check for store %q at the request of user %v", store.Name, userID)
+	} else {
+		duplicate, err := h.storeService.CheckForDuplicate(store.Name, store.Latitude, store.Longitude)
+		if err != nil {
+			log.Printf("Failed to check for duplicates: %v", err)
+			errors.HandleError(c, errors.NewInternalError("Failed to check for duplicates"))
+			return
+		}
 
-	if duplicate != nil {
-		details := fmt.Sprintf("既に同じ名前「%s」で同じ場所（50m以内）に店舗が登録されています。登録日: %s", 
-			duplicate.Name, duplicate.CreatedAt.Format("2006-01-02 15:04:05"))
-		errors.HandleError(c, errors.NewConflictError("重複する店舗が見つかりました", details))
-		return
+		if duplicate != nil {
+			policy := h.storeService.DuplicatePolicy()
+			sameName := "同じ名前"
+			if policy.NameMatch != models.DuplicateNameExact {
+				sameName = "似た名前"
+			}
+			details := fmt.Sprintf("既に%s「%s」で同じ場所（%gm以内）に店舗が登録されています。登録日: %s",
+				sameName, duplicate.Name, policy.Radius, duplicate.CreatedAt.Format("2006-01-02 15:04:05"))
+			errors.SendConflict(c, &models.StoreDuplicateConflict{
+				DuplicateID: duplicate.ID,
+				Duplicate:   duplicate,
+				Policy:      policy,
+			}, "重複する店舗が見つかりました", details)
+			return
+		}
 	}
 
 	if err := h.storeService.CreateStore(store); err != nil {
@@ -179,6 +214,7 @@ func (h *Handler) CreateStore(c *gin.Context) {
 		return
 	}
 
+	setETag(c, store.UpdatedAt)
 	errors.SendCreated(c, store)
 }
 
@@ -197,25 +233,59 @@ func (h *Handler) GetStore(c *gin.Context) {
 		return
 	}
 
+	setETag(c, store.UpdatedAt)
 	errors.SendSuccess(c, store)
 }
 
 func (h *Handler) GetStores(c *gin.Context) {
-	filter := h.parseStoreFilter(c)
-
-	// Validate filter parameters
-	if err := h.validateStoreFilter(filter); err != nil {
+	filter, err := h.storeFilterFromRequest(c)
+	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
 
-	stores, err := h.storeService.GetStores(filter)
+	h.sendStorePage(c, filter)
+}
+
+// sendStorePage responds with the page of stores selected by filter, starting
+// at the cursor query parameter when given
+func (h *Handler) sendStorePage(c *gin.Context, filter *repositories.StoreFilter) {
+	if cursor := c.Query("cursor"); cursor != "" {
+		storeCursor, err := repositories.DecodeStoreCursor(cursor)
+		if err != nil {
+			errors.HandleError(c, errors.NewValidationError("Invalid cursor", err.Error()))
+			return
+		}
+		if storeCursor.Order != filter.Order() {
+			errors.HandleError(c, errors.NewValidationError("Invalid cursor", "cursor was issued for a different ordering"))
+			return
+		}
+		filter.Cursor = storeCursor
+	}
+
+	stores, next, err := h.storeService.GetStoresPage(filter)
 	if err != nil {
 		log.Printf("Failed to get stores: %v", err)
 		errors.HandleError(c, errors.NewInternalError("Failed to get stores"))
 		return
 	}
 
+	var nextCursor string
+	if next != nil {
+		nextCursor = next.Encode()
+	}
+
+	// Cursor pages skip the count query; the client follows next_cursor until it is empty
+	if filter.Cursor != nil {
+		errors.SendSuccess(c, map[string]interface{}{
+			"stores": stores,
+		}, &types.MetaInfo{
+			Limit:      filter.Limit,
+			NextCursor: nextCursor,
+		})
+		return
+	}
+
 	// Get total count for pagination
 	totalCount, err := h.storeService.GetStoresCount(filter)
 	if err != nil {
@@ -235,6 +305,7 @@ func (h *Handler) GetStores(c *gin.Context) {
 		Offset:      filter.Offset,
 		Page:        &currentPage,
 		TotalPages:  &totalPages,
+		NextCursor:  nextCursor,
 	}
 
 	errors.SendSuccess(c, map[string]interface{}{
@@ -288,18 +359,116 @@ func (h *Handler) UpdateStore(c *gin.Context) {
 		return
 	}
 
+	// Reject changes made to an outdated copy of the store
+	if !ifMatchSatisfied(c.GetHeader("If-Match"), existingStore.UpdatedAt) {
+		setETag(c, existingStore.UpdatedAt)
+		errors.SendPreconditionFailed(c, existingStore)
+		return
+	}
+
 	// Update store with request data
 	req.UpdateModel(existingStore)
 
-	if err := h.storeService.UpdateStore(existingStore); err != nil {
+	if err := h.storeService.UpdateStore(existingStore, userID.(uuid.UUID)); err != nil {
+		if err == repositories.ErrVersionConflict {
+			h.sendCurrentStore(c, id)
+			return
+		}
 		log.Printf("Failed to update store: %v", err)
 		errors.HandleError(c, errors.NewInternalError("Failed to update store"))
 		return
 	}
 
+	setETag(c, existingStore.UpdatedAt)
 	errors.SendSuccess(c, existingStore)
 }
 
+// PatchStore partially updates a store with JSON Merge Patch (RFC 7396) semantics
+func (h *Handler) PatchStore(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid store ID", err.Error()))
+		return
+	}
+
+	var patch StorePatchRequest
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid request data", err.Error()))
+		return
+	}
+
+	if err := patch.Validate(); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	existingStore, err := h.storeService.GetStoreByID(id)
+	if err != nil {
+		log.Printf("Store not found for patch: %v", err)
+		errors.HandleError(c, errors.NewNotFoundError("Store"))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	userRole, exists := c.Get("role")
+	if !exists {
+		errors.HandleError(c, errors.NewUnauthorizedError("User role not found in token"))
+		return
+	}
+
+	// Check permissions
+	if existingStore.CreatedBy != userID.(uuid.UUID) && userRole.(string) != constants.RoleAdmin {
+		errors.HandleError(c, errors.NewForbiddenError("You can only update stores you created"))
+		return
+	}
+
+	// Reject changes made to an outdated copy of the store
+	if !ifMatchSatisfied(c.GetHeader("If-Match"), existingStore.UpdatedAt) {
+		setETag(c, existingStore.UpdatedAt)
+		errors.SendPreconditionFailed(c, existingStore)
+		return
+	}
+
+	store, err := patch.Apply(existingStore)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	if err := h.storeService.UpdateStore(store, userID.(uuid.UUID)); err != nil {
+		if err == repositories.ErrVersionConflict {
+			h.sendCurrentStore(c, id)
+			return
+		}
+		log.Printf("Failed to patch store: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to update store"))
+		return
+	}
+
+	setETag(c, store.UpdatedAt)
+	errors.SendSuccess(c, store)
+}
+
+// sendCurrentStore responds with 412 and the current server copy of a store
+// that was modified concurrently
+func (h *Handler) sendCurrentStore(c *gin.Context, id uuid.UUID) {
+	currentStore, err := h.storeService.GetStoreByID(id)
+	if err != nil {
+		log.Printf("Store not found after update conflict: %v", err)
+		errors.HandleError(c, errors.NewNotFoundError("Store"))
+		return
+	}
+
+	setETag(c, currentStore.UpdatedAt)
+	errors.SendPreconditionFailed(c, currentStore)
+}
+
 func (h *Handler) DeleteStore(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := uuid.Parse(idStr)
@@ -333,7 +502,7 @@ func (h *Handler) DeleteStore(c *gin.Context) {
 		return
 	}
 
-	if err := h.storeService.DeleteStore(id); err != nil {
+	if err := h.storeService.DeleteStore(id, userID.(uuid.UUID)); err != nil {
 		log.Printf("Failed to delete store: %v", err)
 		errors.HandleError(c, errors.NewInternalError("Failed to delete store"))
 		return
@@ -368,10 +537,8 @@ func (h *Handler) GetTags(c *gin.Context) {
 
 // ExportStoresCSV handles CSV export of stores with current filter conditions
 func (h *Handler) ExportStoresCSV(c *gin.Context) {
-	filter := h.parseStoreFilter(c)
-
-	// Validate filter parameters
-	if err := h.validateStoreFilter(filter); err != nil {
+	filter, err := h.storeFilterFromRequest(c)
+	if err != nil {
 		errors.HandleError(c, err)
 		return
 	}
@@ -450,6 +617,22 @@ func (h *Handler) ExportStoresCSV(c *gin.Context) {
 	log.Printf("CSV export completed: %d stores exported", len(stores))
 }
 
+// storeFilterFromRequest parses, validates and resolves the store filter
+// query parameters shared by every endpoint that lists stores
+func (h *Handler) storeFilterFromRequest(c *gin.Context) (*repositories.StoreFilter, error) {
+	filter := h.parseStoreFilter(c)
+	if err := h.validateStoreFilter(filter); err != nil {
+		return nil, err
+	}
+	if err := h.applyVisitedBy(c, filter); err != nil {
+		return nil, err
+	}
+	if err := h.applyListFilter(c, filter); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
 // parseStoreFilter parses query parameters into StoreFilter
 func (h *Handler) parseStoreFilter(c *gin.Context) *repositories.StoreFilter {
 	filter := &repositories.StoreFilter{
@@ -460,6 +643,10 @@ func (h *Handler) parseStoreFilter(c *gin.Context) *repositories.StoreFilter {
 		filter.Name = strings.TrimSpace(name)
 	}
 
+	if q := c.Query("q"); q != "" {
+		filter.Query = strings.TrimSpace(q)
+	}
+
 	if categories := c.Query("categories"); categories != "" {
 		filter.Categories = strings.Split(categories, ",")
 		for i, cat := range filter.Categories {
@@ -500,6 +687,13 @@ func (h *Handler) parseStoreFilter(c *gin.Context) *repositories.StoreFilter {
 		}
 	}
 
+	// Map area: bbox=minLng,minLat,maxLng,maxLat
+	if bbox := c.Query("bbox"); bbox != "" {
+		if box, err := parseBoundingBox(bbox); err == nil {
+			filter.BoundingBox = box
+		}
+	}
+
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
 			if limit > constants.MaxLimit {
@@ -515,14 +709,81 @@ func (h *Handler) parseStoreFilter(c *gin.Context) *repositories.StoreFilter {
 		}
 	}
 
+	// business_day means the next such day from today, so that holidays and
+	// other business hour exceptions apply. business_date (YYYY-MM-DD) picks the date.
 	if businessDay := c.Query("business_day"); businessDay != "" {
 		filter.BusinessDay = strings.TrimSpace(businessDay)
+		if date, ok := nextDateOfWeekday(time.Now().In(h.location), filter.BusinessDay); ok {
+			filter.BusinessDate = &date
+		}
+	}
+	if businessDateStr := c.Query("business_date"); businessDateStr != "" {
+		if businessDate, err := time.ParseInLocation("2006-01-02", businessDateStr, h.location); err == nil {
+			filter.BusinessDay = weekdayNames[businessDate.Weekday()]
+			filter.BusinessDate = &businessDate
+		}
 	}
 
 	if businessTime := c.Query("business_time"); businessTime != "" {
 		filter.BusinessTime = strings.TrimSpace(businessTime)
 	}
 
+	// Stores open at a time (open_at, RFC3339) or now (open_now=true), evaluated in the business hours time zone
+	if openAtStr := c.Query("open_at"); openAtStr != "" {
+		// An unescaped "+" in the offset arrives as a space
+		if openAt, err := time.Parse(time.RFC3339, strings.ReplaceAll(openAtStr, " ", "+")); err == nil {
+			openAt = openAt.In(h.location)
+			filter.OpenAt = &openAt
+		}
+	} else if c.Query("open_now") == "true" {
+		now := time.Now().In(h.location)
+		filter.OpenAt = &now
+	}
+
+	if marginStr := c.Query("last_order_margin"); marginStr != "" {
+		if margin, err := strconv.Atoi(marginStr); err == nil && margin > 0 {
+			filter.LastOrderMargin = margin
+		}
+	}
+
+	// Review filters
+	if minRatingStr := c.Query("min_rating"); minRatingStr != "" {
+		if minRating, err := strconv.ParseFloat(minRatingStr, 64); err == nil {
+			filter.MinRating = &minRating
+		}
+	}
+
+	if visitedStr := c.Query("visited"); visitedStr != "" {
+		if visited, err := strconv.ParseBool(visitedStr); err == nil {
+			filter.Visited = &visited
+		}
+	}
+
+	if minPriceStr := c.Query("min_price"); minPriceStr != "" {
+		if minPrice, err := strconv.Atoi(minPriceStr); err == nil {
+			filter.MinPrice = &minPrice
+		}
+	}
+
+	if maxPriceStr := c.Query("max_price"); maxPriceStr != "" {
+		if maxPrice, err := strconv.Atoi(maxPriceStr); err == nil {
+			filter.MaxPrice = &maxPrice
+		}
+	}
+
+	// Sort by a store field or review aggregate; name defaults to ascending, the others to descending
+	if sort := c.Query("sort"); sort != "" {
+		filter.Sort = strings.TrimSpace(sort)
+		filter.SortAscending = filter.Sort == repositories.StoreSortName
+	}
+
+	switch c.Query("order") {
+	case "asc":
+		filter.SortAscending = true
+	case "desc":
+		filter.SortAscending = false
+	}
+
 	// Parse order_by_proximity parameter
 	if orderByProximity := c.Query("order_by_proximity"); orderByProximity == "true" {
 		filter.OrderByProximity = true
@@ -531,8 +792,76 @@ func (h *Handler) parseStoreFilter(c *gin.Context) *repositories.StoreFilter {
 	return filter
 }
 
+// applyVisitedBy sets filter.VisitedBy for visited_by=me, which needs a logged-in user
+func (h *Handler) applyVisitedBy(c *gin.Context, filter *repositories.StoreFilter) error {
+	visitedBy := c.Query("visited_by")
+	if visitedBy == "" {
+		return nil
+	}
+	if visitedBy != "me" {
+		return errors.NewValidationError("Invalid visited_by", "visited_by only supports \"me\"")
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return errors.NewUnauthorizedError("Login is required for visited_by=me")
+	}
+	id := userID.(uuid.UUID)
+	filter.VisitedBy = &id
+	return nil
+}
+
+// applyListFilter sets filter.ListID for list_id, which must be a list the user may see
+func (h *Handler) applyListFilter(c *gin.Context, filter *repositories.StoreFilter) error {
+	listIDStr := c.Query("list_id")
+	if listIDStr == "" {
+		return nil
+	}
+	listID, err := uuid.Parse(listIDStr)
+	if err != nil {
+		return errors.NewValidationError("Invalid list_id", err.Error())
+	}
+
+	if err := h.storeListService.CheckListVisible(listID, currentUserID(c), c.GetString("role")); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.NewNotFoundError("List")
+		}
+		log.Printf("Failed to get list %s: %v", listID, err)
+		return errors.NewInternalError("Failed to get list")
+	}
+	filter.ListID = &listID
+	return nil
+}
+
+// weekdayNames are the business_hours keys indexed by time.Weekday
+var weekdayNames = []string{
+	constants.BusinessDaySunday, constants.BusinessDayMonday, constants.BusinessDayTuesday,
+	constants.BusinessDayWednesday, constants.BusinessDayThursday, constants.BusinessDayFriday,
+	constants.BusinessDaySaturday,
+}
+
+// nextDateOfWeekday returns the first date on or after today that falls on day.
+// It returns false when day is not a weekday name.
+func nextDateOfWeekday(today time.Time, day string) (time.Time, bool) {
+	for i := 0; i < len(weekdayNames); i++ {
+		date := today.AddDate(0, 0, i)
+		if weekdayNames[date.Weekday()] == day {
+			return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location()), true
+		}
+	}
+	return time.Time{}, false
+}
+
 // validateStoreFilter validates store filter parameters
 func (h *Handler) validateStoreFilter(filter *repositories.StoreFilter) error {
+	// Validate search query length
+	if utf8.RuneCountInString(filter.Query) > constants.MaxSearchQueryLength {
+		return errors.NewValidationError(
+			"Invalid search query",
+			fmt.Sprintf("q must be at most %d characters", constants.MaxSearchQueryLength),
+		)
+	}
+
 	// Validate coordinates if provided
 	if filter.Latitude != nil && filter.Longitude != nil {
 		if err := utils.ValidateCoordinates(*filter.Latitude, *filter.Longitude); err != nil {
@@ -547,6 +876,37 @@ func (h *Handler) validateStoreFilter(filter *repositories.StoreFilter) error {
 		}
 	}
 
+	// Validate review filters
+	if filter.MinRating != nil && (*filter.MinRating < 1 || *filter.MinRating > 5) {
+		return errors.NewValidationError("Invalid rating filter", "min_rating must be between 1 and 5")
+	}
+	if (filter.MinPrice != nil && *filter.MinPrice < 0) || (filter.MaxPrice != nil && *filter.MaxPrice < 0) {
+		return errors.NewValidationError("Invalid price filter", "min_price and max_price must not be negative")
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		return errors.NewValidationError("Invalid price filter", "min_price must not be greater than max_price")
+	}
+
+	// Validate sort key if provided
+	if filter.Sort != "" && !repositories.IsValidStoreSort(filter.Sort) {
+		return errors.NewValidationError(
+			"Invalid sort",
+			"sort must be one of avg_rating, review_count, last_visit, name, updated_at",
+		)
+	}
+
+	if box := filter.BoundingBox; box != nil {
+		if err := utils.ValidateCoordinates(box.MinLatitude, box.MinLongitude); err != nil {
+			return err
+		}
+		if err := utils.ValidateCoordinates(box.MaxLatitude, box.MaxLongitude); err != nil {
+			return err
+		}
+		if box.MinLongitude > box.MaxLongitude || box.MinLatitude > box.MaxLatitude {
+			return errors.NewValidationError("Invalid bbox", "bbox must be minLng,minLat,maxLng,maxLat")
+		}
+	}
+
 	// Validate radius requirements
 	if filter.Radius != nil && (filter.Latitude == nil || filter.Longitude == nil) {
 		return errors.NewValidationError(
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+	"sukimise/internal/constants"
+	"sukimise/internal/errors"
+	"sukimise/internal/services"
+	"sukimise/internal/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StoreHistoryHandler struct {
+	storeService *services.StoreService
+}
+
+func NewStoreHistoryHandler(storeService *services.StoreService) *StoreHistoryHandler {
+	return &StoreHistoryHandler{storeService: storeService}
+}
+
+// GetStoreHistory returns the change history of a store, newest first
+func (h *StoreHistoryHandler) GetStoreHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid store ID", err.Error()))
+		return
+	}
+
+	if _, err := h.storeService.GetStoreByID(id); err != nil {
+		log.Printf("Store not found for history: %v", err)
+		errors.HandleError(c, errors.NewNotFoundError("Store"))
+		return
+	}
+
+	limit := constants.DefaultLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			if l > constants.MaxLimit {
+				l = constants.MaxLimit
+			}
+			limit = l
+		}
+	}
+
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	revisions, err := h.storeService.GetStoreHistory(id, limit, offset)
+	if err != nil {
+		log.Printf("Failed to get store history: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get store history"))
+		return
+	}
+
+	totalCount, err := h.storeService.GetStoreHistoryCount(id)
+	if err != nil {
+		log.Printf("Failed to get store history count: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get store history count"))
+		return
+	}
+
+	totalPages := (totalCount + limit - 1) / limit
+	currentPage := (offset / limit) + 1
+
+	meta := &types.MetaInfo{
+		Total:      totalCount,
+		Limit:      limit,
+		Offset:     offset,
+		Page:       &currentPage,
+		TotalPages: &totalPages,
+	}
+
+	errors.SendSuccess(c, map[string]interface{}{
+		"revisions": revisions,
+	}, meta)
+}
+
+// RollbackStore restores a store to its state right after the given revision
+func (h *StoreHistoryHandler) RollbackStore(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid store ID", err.Error()))
+		return
+	}
+
+	revisionID, err := uuid.Parse(c.Param("revision_id"))
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid revision ID", err.Error()))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	store, err := h.storeService.RollbackStore(id, revisionID, userID.(uuid.UUID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			errors.HandleError(c, errors.NewNotFoundError("Store revision"))
+			return
+		}
+		if err == services.ErrRevisionNotRollbackable {
+			errors.HandleError(c, errors.NewValidationError("Cannot roll back to this revision", err.Error()))
+			return
+		}
+		log.Printf("Failed to roll back store: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to roll back store"))
+		return
+	}
+
+	errors.SendSuccess(c, store)
+}
@@ -0,0 +1,368 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sukimise/internal/constants"
+	"sukimise/internal/errors"
+	"sukimise/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Import row statuses
+const (
+	ImportStatusCreated   = "created"
+	ImportStatusDuplicate = "skipped_duplicate"
+	ImportStatusFailed    = "failed"
+)
+
+// StoreImportRowResult represents the import result of a single CSV row
+type StoreImportRowResult struct {
+	Row         int        `json:"row"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	StoreID     *uuid.UUID `json:"store_id,omitempty"`
+	DuplicateOf *uuid.UUID `json:"duplicate_of,omitempty"`
+	Error       string     `json:"error,omitempty"`
+}
+
+// StoreImportReport represents the per-row report of a CSV import
+type StoreImportReport struct {
+	DryRun  bool                   `json:"dry_run"`
+	Total   int                    `json:"total"`
+	Created int                    `json:"created"`
+	Skipped int                    `json:"skipped"`
+	Failed  int                    `json:"failed"`
+	Rows    []StoreImportRowResult `json:"rows"`
+}
+
+// csvRequiredColumns are the columns an import file must contain
+var csvRequiredColumns = []string{"店名", "住所", "緯度", "経度"}
+
+// ImportStoresCSV handles CSV import of stores in the ExportStoresCSV column layout
+func (h *Handler) ImportStoresCSV(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constants.MaxImportFileSize)
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		if err == http.ErrMissingFile {
+			errors.HandleError(c, errors.NewValidationError("No file uploaded", "CSV file is required in the 'file' field"))
+		} else {
+			errors.HandleError(c, errors.NewValidationError("File too large or invalid", err.Error()))
+		}
+		return
+	}
+	defer file.Close()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	records, err := readStoreCSV(file)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	report := &StoreImportReport{
+		DryRun: dryRun,
+		Rows:   make([]StoreImportRowResult, 0, len(records)),
+	}
+
+	for _, record := range records {
+		result := StoreImportRowResult{Row: record.row, Name: record.req.Name}
+
+		if record.err != nil {
+			result.Status = ImportStatusFailed
+			result.Error = record.err.Error()
+			report.addResult(result)
+			continue
+		}
+
+		if err := record.req.ValidateForCreate(); err != nil {
+			result.Status = ImportStatusFailed
+			result.Error = formatImportError(err)
+			report.addResult(result)
+			continue
+		}
+
+		store := record.req.ToModel(userID.(uuid.UUID))
+
+		duplicate, err := h.storeService.CheckForDuplicate(store.Name, store.Latitude, store.Longitude)
+		if err != nil {
+			log.Printf("Failed to check for duplicates (row %d): %v", record.row, err)
+			result.Status = ImportStatusFailed
+			result.Error = "Failed to check for duplicates"
+			report.addResult(result)
+			continue
+		}
+
+		if duplicate != nil {
+			result.Status = ImportStatusDuplicate
+			result.DuplicateOf = &duplicate.ID
+			report.addResult(result)
+			continue
+		}
+
+		if !dryRun {
+			if err := h.storeService.CreateStore(store); err != nil {
+				log.Printf("Failed to create store (row %d): %v", record.row, err)
+				result.Status = ImportStatusFailed
+				result.Error = "Failed to create store"
+				report.addResult(result)
+				continue
+			}
+			result.StoreID = &store.ID
+		}
+
+		result.Status = ImportStatusCreated
+		report.addResult(result)
+	}
+
+	log.Printf("CSV import completed (dry_run=%t): %d created, %d skipped, %d failed",
+		dryRun, report.Created, report.Skipped, report.Failed)
+	errors.SendSuccess(c, report)
+}
+
+// addResult appends a row result and updates the summary counters
+func (r *StoreImportReport) addResult(result StoreImportRowResult) {
+	r.Rows = append(r.Rows, result)
+	r.Total++
+	switch result.Status {
+	case ImportStatusCreated:
+		r.Created++
+	case ImportStatusDuplicate:
+		r.Skipped++
+	case ImportStatusFailed:
+		r.Failed++
+	}
+}
+
+// storeCSVRecord holds a parsed CSV row, or the error that prevented parsing it
+type storeCSVRecord struct {
+	row int
+	req StoreRequest
+	err error
+}
+
+// readStoreCSV reads an exported store CSV and converts each data row into a StoreRequest
+func readStoreCSV(r io.Reader) ([]storeCSVRecord, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.NewValidationError("Failed to read CSV file", err.Error())
+	}
+	// Strip UTF-8 BOM written by ExportStoresCSV
+	content = bytes.TrimPrefix(content, []byte{0xEF, 0xBB, 0xBF})
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.NewValidationError("CSV file is empty", "")
+	}
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid CSV format", err.Error())
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range csvRequiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.NewValidationError("Missing required CSV column", fmt.Sprintf("Column: %s", name))
+		}
+	}
+
+	var records []storeCSVRecord
+	row := 1
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		row++
+		if err != nil {
+			records = append(records, storeCSVRecord{row: row, err: fmt.Errorf("invalid CSV row: %v", err)})
+			continue
+		}
+		if isEmptyCSVRecord(fields) {
+			continue
+		}
+		if len(records) >= constants.MaxImportRows {
+			return nil, errors.NewValidationError("Too many rows", fmt.Sprintf("Maximum %d rows allowed", constants.MaxImportRows))
+		}
+
+		req, err := parseStoreCSVRecord(columns, fields)
+		records = append(records, storeCSVRecord{row: row, req: req, err: err})
+	}
+
+	return records, nil
+}
+
+// parseStoreCSVRecord converts a single CSV row into a StoreRequest
+func parseStoreCSVRecord(columns map[string]int, fields []string) (StoreRequest, error) {
+	get := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+
+	req := StoreRequest{
+		Name:         get("店名"),
+		Address:      get("住所"),
+		Categories:   splitCSVList(get("カテゴリ")),
+		ParkingInfo:  get("駐車場情報"),
+		WebsiteURL:   get("ウェブサイト"),
+		GoogleMapURL: get("GoogleMap URL"),
+		SnsUrls:      splitCSVList(get("SNS URL")),
+		Tags:         splitCSVList(get("タグ")),
+		Photos:       []string{},
+	}
+
+	latitude, err := strconv.ParseFloat(get("緯度"), 64)
+	if err != nil {
+		return req, fmt.Errorf("invalid latitude: %q", get("緯度"))
+	}
+	longitude, err := strconv.ParseFloat(get("経度"), 64)
+	if err != nil {
+		return req, fmt.Errorf("invalid longitude: %q", get("経度"))
+	}
+	req.Latitude = latitude
+	req.Longitude = longitude
+
+	businessHours, err := parseBusinessHoursFromCSV(get("営業時間"))
+	if err != nil {
+		return req, err
+	}
+	req.BusinessHours = businessHours
+
+	return req, nil
+}
+
+// parseBusinessHoursFromCSV converts the text written by formatBusinessHoursForCSV back into BusinessHoursData
+func parseBusinessHoursFromCSV(text string) (models.BusinessHoursData, error) {
+	businessHours := models.GetDefaultBusinessHours()
+
+	text = strings.TrimSpace(text)
+	if text == "" || text == "営業時間未設定" {
+		return businessHours, nil
+	}
+
+	dayKeys := map[string]string{
+		"月": "monday", "火": "tuesday", "水": "wednesday", "木": "thursday",
+		"金": "friday", "土": "saturday", "日": "sunday",
+	}
+
+	var slot *models.TimeSlot
+	var lastOrderTime string
+	closedDays := map[string]bool{}
+
+	for _, part := range strings.Split(text, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return businessHours, fmt.Errorf("invalid business hours: %q", part)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "営業時間":
+			openTime, closeTime, ok := strings.Cut(value, "-")
+			if !ok {
+				return businessHours, fmt.Errorf("invalid business hours time range: %q", value)
+			}
+			slot = &models.TimeSlot{
+				OpenTime:  strings.TrimSpace(openTime),
+				CloseTime: strings.TrimSpace(closeTime),
+			}
+		case "ラストオーダー":
+			lastOrderTime = value
+		case "定休日":
+			if value == "年中無休" {
+				continue
+			}
+			for _, day := range strings.Split(value, "、") {
+				dayKey, ok := dayKeys[strings.TrimSpace(day)]
+				if !ok {
+					return businessHours, fmt.Errorf("invalid closed day: %q", day)
+				}
+				closedDays[dayKey] = true
+			}
+		default:
+			return businessHours, fmt.Errorf("unknown business hours field: %q", key)
+		}
+	}
+
+	if slot != nil {
+		slot.LastOrderTime = lastOrderTime
+	}
+
+	days := map[string]*models.DaySchedule{
+		"monday":    &businessHours.Monday,
+		"tuesday":   &businessHours.Tuesday,
+		"wednesday": &businessHours.Wednesday,
+		"thursday":  &businessHours.Thursday,
+		"friday":    &businessHours.Friday,
+		"saturday":  &businessHours.Saturday,
+		"sunday":    &businessHours.Sunday,
+	}
+	for key, schedule := range days {
+		if closedDays[key] {
+			schedule.IsClosed = true
+		} else if slot != nil {
+			schedule.TimeSlots = []models.TimeSlot{*slot}
+		}
+	}
+
+	return businessHours, nil
+}
+
+// splitCSVList splits a "; " joined CSV field into its items
+func splitCSVList(field string) []string {
+	items := []string{}
+	for _, item := range strings.Split(field, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// isEmptyCSVRecord reports whether every field of the record is blank
+func isEmptyCSVRecord(fields []string) bool {
+	for _, field := range fields {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// formatImportError renders an error including its details for the import report
+func formatImportError(err error) string {
+	if appErr, ok := err.(*errors.AppError); ok && appErr.Details != "" {
+		return fmt.Sprintf("%s: %s", appErr.Message, appErr.Details)
+	}
+	return err.Error()
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"strings"
+	"sukimise/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBusinessHoursFromCSV(t *testing.T) {
+	slot := models.TimeSlot{OpenTime: "11:00", CloseTime: "22:00", LastOrderTime: "21:30"}
+
+	tests := []struct {
+		name        string
+		input       string
+		expected    func() models.BusinessHoursData
+		expectError bool
+	}{
+		{
+			name:     "not set",
+			input:    "営業時間未設定",
+			expected: models.GetDefaultBusinessHours,
+		},
+		{
+			name:     "empty",
+			input:    "",
+			expected: models.GetDefaultBusinessHours,
+		},
+		{
+			name:  "open every day",
+			input: "営業時間: 11:00-22:00; ラストオーダー: 21:30; 定休日: 年中無休",
+			expected: func() models.BusinessHoursData {
+				bh := models.GetDefaultBusinessHours()
+				for _, day := range []*models.DaySchedule{&bh.Monday, &bh.Tuesday, &bh.Wednesday, &bh.Thursday, &bh.Friday, &bh.Saturday, &bh.Sunday} {
+					day.TimeSlots = []models.TimeSlot{slot}
+				}
+				return bh
+			},
+		},
+		{
+			name:  "with closed days",
+			input: "営業時間: 11:00-22:00; ラストオーダー: 21:30; 定休日: 月、日",
+			expected: func() models.BusinessHoursData {
+				bh := models.GetDefaultBusinessHours()
+				for _, day := range []*models.DaySchedule{&bh.Tuesday, &bh.Wednesday, &bh.Thursday, &bh.Friday, &bh.Saturday} {
+					day.TimeSlots = []models.TimeSlot{slot}
+				}
+				bh.Monday.IsClosed = true
+				bh.Sunday.IsClosed = true
+				return bh
+			},
+		},
+		{
+			name:        "invalid time range",
+			input:       "営業時間: 11:00",
+			expectError: true,
+		},
+		{
+			name:        "invalid closed day",
+			input:       "定休日: 祝",
+			expectError: true,
+		},
+		{
+			name:        "unknown field",
+			input:       "備考: なし",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseBusinessHoursFromCSV(tt.input)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected(), result)
+		})
+	}
+}
+
+func TestParseBusinessHoursFromCSV_RoundTrip(t *testing.T) {
+	bh := models.GetDefaultBusinessHours()
+	slot := models.TimeSlot{OpenTime: "18:00", CloseTime: "23:00", LastOrderTime: "22:30"}
+	for _, day := range []*models.DaySchedule{&bh.Monday, &bh.Tuesday, &bh.Thursday, &bh.Friday, &bh.Saturday, &bh.Sunday} {
+		day.TimeSlots = []models.TimeSlot{slot}
+	}
+	bh.Wednesday.IsClosed = true
+
+	parsed, err := parseBusinessHoursFromCSV(formatBusinessHoursForCSV(bh))
+	assert.NoError(t, err)
+	assert.Equal(t, bh, parsed)
+}
+
+func TestReadStoreCSV(t *testing.T) {
+	t.Run("exported layout with BOM", func(t *testing.T) {
+		input := "\xEF\xBB\xBFID,店名,住所,緯度,経度,カテゴリ,営業時間,駐車場情報,ウェブサイト,GoogleMap URL,SNS URL,タグ,作成者,作成日時,更新日時\n" +
+			"6f1c,テスト店,東京都渋谷区,35.658000,139.701600,ラーメン; 中華,営業時間: 11:00-22:00; 定休日: 年中無休,なし,https://example.com,,,人気; 深夜,u,2024-01-01 00:00:00,2024-01-01 00:00:00\n" +
+			",,,,,,,,,,,,,,\n" +
+			"7a2d,緯度不正,東京都,abc,139.0,,,,,,,,,,\n"
+
+		records, err := readStoreCSV(strings.NewReader(input))
+		assert.NoError(t, err)
+		assert.Len(t, records, 2)
+
+		assert.Equal(t, 2, records[0].row)
+		assert.NoError(t, records[0].err)
+		assert.Equal(t, "テスト店", records[0].req.Name)
+		assert.Equal(t, 35.658, records[0].req.Latitude)
+		assert.Equal(t, []string{"ラーメン", "中華"}, records[0].req.Categories)
+		assert.Equal(t, []string{"人気", "深夜"}, records[0].req.Tags)
+		assert.Equal(t, []string{}, records[0].req.SnsUrls)
+		assert.Equal(t, "11:00", records[0].req.BusinessHours.Monday.TimeSlots[0].OpenTime)
+
+		assert.Equal(t, 4, records[1].row)
+		assert.Error(t, records[1].err)
+	})
+
+	t.Run("missing required column", func(t *testing.T) {
+		_, err := readStoreCSV(strings.NewReader("店名,住所\nテスト店,東京都\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		_, err := readStoreCSV(strings.NewReader(""))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"sukimise/internal/constants"
+	"sukimise/internal/errors"
+	"sukimise/internal/models"
+	"sukimise/internal/repositories"
+	"sukimise/internal/services"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StoreListRequest represents the request body for creating or replacing a store list
+type StoreListRequest struct {
+	Title       string                  `json:"title"`
+	Description string                  `json:"description"`
+	Visibility  string                  `json:"visibility"` // private (default), editors or viewers
+	Entries     []StoreListEntryRequest `json:"entries"`    // In order. Omit on update to keep the entries.
+}
+
+// StoreListEntryRequest represents a store of a list with its note
+type StoreListEntryRequest struct {
+	StoreID uuid.UUID `json:"store_id"`
+	Note    string    `json:"note"`
+}
+
+// Validate checks the lengths of the texts, the visibility and the entries
+func (r *StoreListRequest) Validate() error {
+	title := strings.TrimSpace(r.Title)
+	if title == "" {
+		return errors.NewValidationError("List title is required", "")
+	}
+	if utf8.RuneCountInString(title) > constants.MaxListTitleLength {
+		return errors.NewValidationError("Invalid title", fmt.Sprintf("title must be at most %d characters", constants.MaxListTitleLength))
+	}
+	if utf8.RuneCountInString(r.Description) > constants.MaxListDescriptionLength {
+		return errors.NewValidationError("Invalid description", fmt.Sprintf("description must be at most %d characters", constants.MaxListDescriptionLength))
+	}
+	if r.Visibility != "" && !models.IsValidListVisibility(r.Visibility) {
+		return errors.NewValidationError("Invalid visibility", "visibility must be private, editors or viewers")
+	}
+	if len(r.Entries) > constants.MaxListEntries {
+		return errors.NewValidationError("Too many entries", fmt.Sprintf("Maximum %d stores allowed in a list", constants.MaxListEntries))
+	}
+
+	seen := make(map[uuid.UUID]bool, len(r.Entries))
+	for _, entry := range r.Entries {
+		if err := entry.Validate(); err != nil {
+			return err
+		}
+		if seen[entry.StoreID] {
+			return errors.NewValidationError("Invalid entries", fmt.Sprintf("store %s appears more than once", entry.StoreID))
+		}
+		seen[entry.StoreID] = true
+	}
+	return nil
+}
+
+// Validate checks that the entry has a store and a note that is not too long
+func (r *StoreListEntryRequest) Validate() error {
+	if r.StoreID == uuid.Nil {
+		return errors.NewValidationError("Invalid entries", "store_id is required")
+	}
+	if utf8.RuneCountInString(r.Note) > constants.MaxListEntryNoteLength {
+		return errors.NewValidationError("Invalid note", fmt.Sprintf("note must be at most %d characters", constants.MaxListEntryNoteLength))
+	}
+	return nil
+}
+
+// ToModel converts the request to a list owned by ownerID.
+// Entries stay nil when the request has none.
+func (r *StoreListRequest) ToModel(ownerID uuid.UUID) *models.StoreList {
+	list := &models.StoreList{
+		OwnerID:     ownerID,
+		Title:       strings.TrimSpace(r.Title),
+		Description: strings.TrimSpace(r.Description),
+		Visibility:  r.Visibility,
+	}
+	if list.Visibility == "" {
+		list.Visibility = models.ListVisibilityPrivate
+	}
+	if r.Entries != nil {
+		list.Entries = make([]*models.StoreListEntry, len(r.Entries))
+		for i, entry := range r.Entries {
+			list.Entries[i] = entry.ToModel()
+		}
+	}
+	return list
+}
+
+func (r *StoreListEntryRequest) ToModel() *models.StoreListEntry {
+	return &models.StoreListEntry{StoreID: r.StoreID, Note: strings.TrimSpace(r.Note)}
+}
+
+type StoreListHandler struct {
+	storeListService *services.StoreListService
+}
+
+func NewStoreListHandler(storeListService *services.StoreListService) *StoreListHandler {
+	return &StoreListHandler{storeListService: storeListService}
+}
+
+// currentUserID returns the ID of the logged-in user, or nil for anonymous requests
+func currentUserID(c *gin.Context) *uuid.UUID {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return nil
+	}
+	id := userID.(uuid.UUID)
+	return &id
+}
+
+// GetLists returns the lists the user may see, optionally only their own
+// (owner=me) or those containing a store (store_id)
+func (h *StoreListHandler) GetLists(c *gin.Context) {
+	filter := &repositories.StoreListFilter{ViewerID: currentUserID(c), ViewerRole: c.GetString("role")}
+
+	if owner := c.Query("owner"); owner != "" {
+		if owner != "me" {
+			errors.HandleError(c, errors.NewValidationError("Invalid owner", "owner only supports \"me\""))
+			return
+		}
+		if filter.ViewerID == nil {
+			errors.HandleError(c, errors.NewUnauthorizedError("Login is required for owner=me"))
+			return
+		}
+		filter.OwnerID = filter.ViewerID
+	}
+
+	if storeIDStr := c.Query("store_id"); storeIDStr != "" {
+		storeID, err := uuid.Parse(storeIDStr)
+		if err != nil {
+			errors.HandleError(c, errors.NewValidationError("Invalid store ID", err.Error()))
+			return
+		}
+		filter.StoreID = &storeID
+	}
+
+	lists, err := h.storeListService.GetLists(filter)
+	if err != nil {
+		log.Printf("Failed to get lists: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get lists"))
+		return
+	}
+
+	errors.SendSuccess(c, map[string]interface{}{"lists": lists})
+}
+
+// GetList returns a list with its stores in order
+func (h *StoreListHandler) GetList(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid list ID", err.Error()))
+		return
+	}
+
+	list, err := h.storeListService.GetList(id, currentUserID(c), c.GetString("role"))
+	if err != nil {
+		handleStoreListError(c, err, "get list")
+		return
+	}
+
+	errors.SendSuccess(c, list)
+}
+
+func (h *StoreListHandler) CreateList(c *gin.Context) {
+	var req StoreListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid request data", err.Error()))
+		return
+	}
+	if err := req.Validate(); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	userID := currentUserID(c)
+	if userID == nil {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	list := req.ToModel(*userID)
+	if list.Entries == nil {
+		list.Entries = []*models.StoreListEntry{}
+	}
+	if err := h.storeListService.CreateList(list); err != nil {
+		handleStoreListError(c, err, "create list")
+		return
+	}
+
+	if list, ok := h.getSavedList(c, list.ID, *userID); ok {
+		errors.SendCreated(c, list)
+	}
+}
+
+// UpdateList replaces the title, description and visibility of a list, and
+// its entries when given. Only the owner can change a list.
+func (h *StoreListHandler) UpdateList(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid list ID", err.Error()))
+		return
+	}
+
+	var req StoreListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid request data", err.Error()))
+		return
+	}
+	if err := req.Validate(); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	userID := currentUserID(c)
+	if userID == nil {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	list := req.ToModel(*userID)
+	list.ID = id
+	if err := h.storeListService.UpdateList(list, *userID, c.GetString("role")); err != nil {
+		handleStoreListError(c, err, "update list")
+		return
+	}
+
+	if list, ok := h.getSavedList(c, id, *userID); ok {
+		errors.SendSuccess(c, list)
+	}
+}
+
+func (h *StoreListHandler) DeleteList(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid list ID", err.Error()))
+		return
+	}
+
+	userID := currentUserID(c)
+	if userID == nil {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	if err := h.storeListService.DeleteList(id, *userID, c.GetString("role")); err != nil {
+		handleStoreListError(c, err, "delete list")
+		return
+	}
+
+	errors.SendSuccess(c, map[string]string{"message": "List deleted successfully"})
+}
+
+// AddListEntry adds a store to the end of a list, or updates its note when the list has it already
+func (h *StoreListHandler) AddListEntry(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid list ID", err.Error()))
+		return
+	}
+
+	var req StoreListEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid request data", err.Error()))
+		return
+	}
+	if err := req.Validate(); err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+
+	userID := currentUserID(c)
+	if userID == nil {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	entry := req.ToModel()
+	if err := h.storeListService.AddListEntry(id, entry, *userID, c.GetString("role")); err != nil {
+		handleStoreListError(c, err, "add store to list")
+		return
+	}
+
+	errors.SendSuccess(c, entry)
+}
+
+func (h *StoreListHandler) RemoveListEntry(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid list ID", err.Error()))
+		return
+	}
+	storeID, err := uuid.Parse(c.Param("store_id"))
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid store ID", err.Error()))
+		return
+	}
+
+	userID := currentUserID(c)
+	if userID == nil {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	if err := h.storeListService.RemoveListEntry(id, storeID, *userID, c.GetString("role")); err != nil {
+		if err == sql.ErrNoRows {
+			errors.HandleError(c, errors.NewNotFoundError("List entry"))
+			return
+		}
+		handleStoreListError(c, err, "remove store from list")
+		return
+	}
+
+	errors.SendSuccess(c, map[string]string{"message": "Store removed from list"})
+}
+
+// getSavedList reads back a list with its entries after a change, responding
+// with the error and returning false when that fails
+func (h *StoreListHandler) getSavedList(c *gin.Context, id, userID uuid.UUID) (*models.StoreList, bool) {
+	list, err := h.storeListService.GetList(id, &userID, c.GetString("role"))
+	if err != nil {
+		handleStoreListError(c, err, "get list")
+		return nil, false
+	}
+	return list, true
+}
+
+// handleStoreListError responds with the error of a list operation
+func handleStoreListError(c *gin.Context, err error, action string) {
+	switch err {
+	case sql.ErrNoRows:
+		errors.HandleError(c, errors.NewNotFoundError("List"))
+	case services.ErrNotListOwner:
+		errors.HandleError(c, errors.NewForbiddenError("You can only change lists you own"))
+	case repositories.ErrListStoreNotFound:
+		errors.HandleError(c, errors.NewValidationError("Invalid entries", "store not found or in the trash"))
+	default:
+		log.Printf("Failed to %s: %v", action, err)
+		errors.HandleError(c, errors.NewInternalError("Failed to "+action))
+	}
+}
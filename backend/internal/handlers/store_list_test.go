@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sukimise/internal/models"
+	"sukimise/internal/services"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreListRequest_Validate(t *testing.T) {
+	storeID := uuid.New()
+
+	tests := []struct {
+		name    string
+		req     StoreListRequest
+		wantErr bool
+	}{
+		{name: "title only", req: StoreListRequest{Title: "デート候補"}},
+		{name: "with entries", req: StoreListRequest{Title: "出張で行きたい", Visibility: models.ListVisibilityEditors,
+			Entries: []StoreListEntryRequest{{StoreID: storeID, Note: "駅から近い"}, {StoreID: uuid.New()}}}},
+		{name: "blank title", req: StoreListRequest{Title: "  "}, wantErr: true},
+		{name: "long title", req: StoreListRequest{Title: strings.Repeat("あ", 101)}, wantErr: true},
+		{name: "unknown visibility", req: StoreListRequest{Title: "子連れOK", Visibility: "public"}, wantErr: true},
+		{name: "missing store", req: StoreListRequest{Title: "子連れOK", Entries: []StoreListEntryRequest{{Note: "広い"}}}, wantErr: true},
+		{name: "duplicate store", req: StoreListRequest{Title: "子連れOK",
+			Entries: []StoreListEntryRequest{{StoreID: storeID}, {StoreID: storeID}}}, wantErr: true},
+		{name: "long note", req: StoreListRequest{Title: "子連れOK",
+			Entries: []StoreListEntryRequest{{StoreID: storeID, Note: strings.Repeat("あ", 501)}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestStoreListRequest_ToModel(t *testing.T) {
+	ownerID, storeID := uuid.New(), uuid.New()
+
+	list := (&StoreListRequest{Title: " デート候補 ", Description: "夜景"}).ToModel(ownerID)
+	assert.Equal(t, "デート候補", list.Title)
+	assert.Equal(t, ownerID, list.OwnerID)
+	assert.Equal(t, models.ListVisibilityPrivate, list.Visibility, "lists are private by default")
+	assert.Nil(t, list.Entries, "entries are kept when omitted")
+
+	list = (&StoreListRequest{Title: "デート候補", Entries: []StoreListEntryRequest{}}).ToModel(ownerID)
+	assert.NotNil(t, list.Entries, "an empty array clears the entries")
+
+	list = (&StoreListRequest{Title: "デート候補", Entries: []StoreListEntryRequest{{StoreID: storeID, Note: " 予約必須 "}}}).ToModel(ownerID)
+	require.Len(t, list.Entries, 1)
+	assert.Equal(t, storeID, list.Entries[0].StoreID)
+	assert.Equal(t, "予約必須", list.Entries[0].Note)
+}
+
+func TestGetLists_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewStoreListHandler(services.NewStoreListService(nil))
+
+	tests := []struct {
+		query  string
+		status int
+	}{
+		{query: "owner=me", status: http.StatusUnauthorized},
+		{query: "owner=someone", status: http.StatusBadRequest},
+		{query: "store_id=abc", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/lists?"+tt.query, nil)
+
+			h.GetLists(c)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"sukimise/internal/constants"
+	"sukimise/internal/errors"
+	"sukimise/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StoreMergeRequest represents the request body for merging a duplicate store into another
+type StoreMergeRequest struct {
+	TargetID uuid.UUID `json:"target_id" binding:"required"` // 残す店舗
+	SourceID uuid.UUID `json:"source_id" binding:"required"` // 統合してゴミ箱に移動する店舗
+}
+
+type StoreMergeHandler struct {
+	storeMergeService *services.StoreMergeService
+}
+
+func NewStoreMergeHandler(storeMergeService *services.StoreMergeService) *StoreMergeHandler {
+	return &StoreMergeHandler{storeMergeService: storeMergeService}
+}
+
+// GetDuplicateCandidates lists pairs of nearby stores with similar names that may be duplicates
+func (h *StoreMergeHandler) GetDuplicateCandidates(c *gin.Context) {
+	radius := constants.DefaultDuplicateRadius
+	if value := c.Query("radius"); value != "" {
+		r, err := strconv.ParseFloat(value, 64)
+		if err != nil || r <= 0 || r > constants.MaxDuplicateRadius {
+			errors.HandleError(c, errors.NewValidationError("Invalid radius",
+				fmt.Sprintf("radius must be between 0 and %.0f meters", constants.MaxDuplicateRadius)))
+			return
+		}
+		radius = r
+	}
+
+	minSimilarity := constants.DefaultDuplicateNameSimilarity
+	if value := c.Query("min_similarity"); value != "" {
+		s, err := strconv.ParseFloat(value, 64)
+		if err != nil || s < 0 || s > 1 {
+			errors.HandleError(c, errors.NewValidationError("Invalid min_similarity", "min_similarity must be between 0 and 1"))
+			return
+		}
+		minSimilarity = s
+	}
+
+	limit := constants.DefaultDuplicateCandidates
+	if value := c.Query("limit"); value != "" {
+		if l, err := strconv.Atoi(value); err == nil && l > 0 {
+			if l > constants.MaxDuplicateCandidates {
+				l = constants.MaxDuplicateCandidates
+			}
+			limit = l
+		}
+	}
+
+	candidates, err := h.storeMergeService.FindDuplicateCandidates(radius, minSimilarity, constants.DuplicateSameSpotDistance, limit)
+	if err != nil {
+		log.Printf("Failed to find duplicate stores: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to find duplicate stores"))
+		return
+	}
+
+	errors.SendSuccess(c, map[string]interface{}{
+		"candidates":     candidates,
+		"radius":         radius,
+		"min_similarity": minSimilarity,
+	})
+}
+
+// MergeStores merges a duplicate store into another. Tags, categories, photos
+// and SNS URLs are combined, reviews are moved and the duplicate is moved to
+// the trash. With dry_run=true only the merged store is returned.
+func (h *StoreMergeHandler) MergeStores(c *gin.Context) {
+	var req StoreMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid request data", err.Error()))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+	result, err := h.storeMergeService.MergeStores(req.TargetID, req.SourceID, userID.(uuid.UUID), dryRun)
+	if err != nil {
+		switch err {
+		case services.ErrMergeSameStore:
+			errors.HandleError(c, errors.NewValidationError("Invalid store merge", err.Error()))
+		case sql.ErrNoRows:
+			errors.HandleError(c, errors.NewNotFoundError("Store"))
+		default:
+			log.Printf("Failed to merge store %s into %s: %v", req.SourceID, req.TargetID, err)
+			errors.HandleError(c, errors.NewInternalError("Failed to merge stores"))
+		}
+		return
+	}
+
+	if !dryRun {
+		log.Printf("Merged store %s into %s (%d reviews moved, %d merged)",
+			req.SourceID, req.TargetID, result.MovedReviews, result.MergedReviews)
+	}
+	errors.SendSuccess(c, result)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sukimise/internal/services"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDuplicateCandidates_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewStoreMergeHandler(services.NewStoreMergeService(nil))
+
+	for _, query := range []string{"radius=0", "radius=5000", "radius=abc", "min_similarity=1.5", "min_similarity=-0.1"} {
+		t.Run(query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/v1/admin/stores/duplicates?"+query, nil)
+
+			h.GetDuplicateCandidates(c)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestMergeStores_Validation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewStoreMergeHandler(services.NewStoreMergeService(nil))
+	id := uuid.New().String()
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "missing source", body: `{"target_id": "` + id + `"}`},
+		{name: "invalid id", body: `{"target_id": "` + id + `", "source_id": "x"}`},
+		{name: "same store", body: `{"target_id": "` + id + `", "source_id": "` + id + `"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("POST", "/api/v1/admin/stores/merge", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user_id", uuid.New())
+
+			h.MergeStores(c)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
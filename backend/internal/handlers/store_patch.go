@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sukimise/internal/errors"
+	"sukimise/internal/models"
+	"sukimise/internal/utils"
+)
+
+// StorePatchRequest is a JSON Merge Patch (RFC 7396) document for a store.
+// Members that are absent are left unchanged, members that are null are
+// cleared and business_hours is merged per day.
+type StorePatchRequest map[string]json.RawMessage
+
+// storePatchableFields are the store fields a patch may contain.
+// The value reports whether the field may be cleared with null.
+var storePatchableFields = map[string]bool{
+	"name":                     false,
+	"address":                  false,
+	"latitude":                 false,
+	"longitude":                false,
+	"categories":               true,
+	"business_hours":           true,
+	"business_hour_exceptions": true,
+	"parking_info":             true,
+	"website_url":              true,
+	"google_map_url":           true,
+	"sns_urls":                 true,
+	"tags":                     true,
+	"photos":                   true,
+}
+
+// Validate checks that the patch only touches fields that can be changed
+func (p StorePatchRequest) Validate() error {
+	for name, value := range p {
+		clearable, ok := storePatchableFields[name]
+		if !ok {
+			return errors.NewValidationError("Invalid patch field", fmt.Sprintf("Field %s cannot be changed", name))
+		}
+		if !clearable && string(value) == "null" {
+			return errors.NewValidationError("Invalid patch field", fmt.Sprintf("Field %s cannot be cleared", name))
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of store with the patch merged into it.
+// The result is validated like a newly created store.
+func (p StorePatchRequest) Apply(store *models.Store) (*models.Store, error) {
+	current, err := json.Marshal(store)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := utils.MergePatch(current, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched models.Store
+	if err := json.Unmarshal(merged, &patched); err != nil {
+		return nil, errors.NewValidationError("Invalid request data", err.Error())
+	}
+
+	// Fields that are not patchable always come from the stored copy
+	patched.ID = store.ID
+	patched.CreatedBy = store.CreatedBy
+	patched.CreatedAt = store.CreatedAt
+	patched.UpdatedAt = store.UpdatedAt
+	patched.DeletedAt = store.DeletedAt
+
+	// Cleared values are stored the same way as for a new store
+	if string(p["business_hours"]) == "null" {
+		patched.BusinessHours = models.GetDefaultBusinessHours()
+	}
+	if patched.BusinessHourExceptions == nil {
+		patched.BusinessHourExceptions = models.BusinessHourExceptions{}
+	}
+	for _, array := range []*models.StringArray{&patched.Categories, &patched.SnsUrls, &patched.Tags, &patched.Photos} {
+		if *array == nil {
+			*array = models.StringArray{}
+		}
+	}
+
+	req := StoreRequest{
+		Name:                   patched.Name,
+		Address:                patched.Address,
+		Latitude:               patched.Latitude,
+		Longitude:              patched.Longitude,
+		Categories:             patched.Categories,
+		BusinessHours:          patched.BusinessHours,
+		BusinessHourExceptions: patched.BusinessHourExceptions,
+		ParkingInfo:            patched.ParkingInfo,
+		WebsiteURL:             patched.WebsiteURL,
+		GoogleMapURL:           patched.GoogleMapURL,
+		SnsUrls:                patched.SnsUrls,
+		Tags:                   patched.Tags,
+		Photos:                 patched.Photos,
+	}
+	if err := req.ValidateForCreate(); err != nil {
+		return nil, err
+	}
+
+	return &patched, nil
+}
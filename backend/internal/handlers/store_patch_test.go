@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"sukimise/internal/errors"
+	"sukimise/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPatchTestStore() *models.Store {
+	businessHours := models.GetDefaultBusinessHours()
+	businessHours.Monday = models.DaySchedule{
+		TimeSlots: []models.TimeSlot{{OpenTime: "11:00", CloseTime: "14:00"}},
+	}
+	return &models.Store{
+		ID:            uuid.New(),
+		Name:          "テスト店",
+		Address:       "東京都渋谷区",
+		Latitude:      35.658,
+		Longitude:     139.701,
+		Categories:    models.StringArray{"カフェ"},
+		BusinessHours: businessHours,
+		ParkingInfo:   "なし",
+		WebsiteURL:    "https://example.com",
+		Tags:          models.StringArray{"人気"},
+		CreatedBy:     uuid.New(),
+		CreatedAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt:     time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func parsePatch(t *testing.T, body string) StorePatchRequest {
+	var patch StorePatchRequest
+	require.NoError(t, json.Unmarshal([]byte(body), &patch))
+	return patch
+}
+
+func TestStorePatchRequest_Apply(t *testing.T) {
+	t.Run("absent fields are unchanged", func(t *testing.T) {
+		store := newPatchTestStore()
+		patched, err := parsePatch(t, `{"name":"新しい店名"}`).Apply(store)
+		require.NoError(t, err)
+
+		assert.Equal(t, "新しい店名", patched.Name)
+		assert.Equal(t, store.Latitude, patched.Latitude)
+		assert.Equal(t, store.Longitude, patched.Longitude)
+		assert.Equal(t, store.BusinessHours, patched.BusinessHours)
+		assert.Equal(t, store.WebsiteURL, patched.WebsiteURL)
+		assert.Equal(t, "テスト店", store.Name, "original store must not be modified")
+	})
+
+	t.Run("null clears fields", func(t *testing.T) {
+		patched, err := parsePatch(t, `{"parking_info":null,"website_url":null,"tags":null,"business_hours":null}`).Apply(newPatchTestStore())
+		require.NoError(t, err)
+
+		assert.Equal(t, "", patched.ParkingInfo)
+		assert.Equal(t, "", patched.WebsiteURL)
+		assert.Equal(t, models.StringArray{}, patched.Tags)
+		assert.Equal(t, models.GetDefaultBusinessHours(), patched.BusinessHours)
+	})
+
+	t.Run("business hours are merged per day", func(t *testing.T) {
+		store := newPatchTestStore()
+		patched, err := parsePatch(t, `{"business_hours":{"tuesday":{"is_closed":true}}}`).Apply(store)
+		require.NoError(t, err)
+
+		assert.True(t, patched.BusinessHours.Tuesday.IsClosed)
+		assert.Equal(t, store.BusinessHours.Monday, patched.BusinessHours.Monday)
+	})
+
+	t.Run("business hour exceptions are replaced as a whole", func(t *testing.T) {
+		store := newPatchTestStore()
+		store.BusinessHourExceptions = models.BusinessHourExceptions{{Type: models.BusinessHourExceptionHoliday, IsClosed: true}}
+
+		patched, err := parsePatch(t, `{"business_hour_exceptions":[{"type":"nth_weekday","weekday":"tuesday","nth":2,"is_closed":true}]}`).Apply(store)
+		require.NoError(t, err)
+		assert.Equal(t, models.BusinessHourExceptions{
+			{Type: models.BusinessHourExceptionNthWeekday, Weekday: "tuesday", Nth: 2, IsClosed: true},
+		}, patched.BusinessHourExceptions)
+
+		patched, err = parsePatch(t, `{"business_hour_exceptions":null}`).Apply(store)
+		require.NoError(t, err)
+		assert.Equal(t, models.BusinessHourExceptions{}, patched.BusinessHourExceptions)
+
+		_, err = parsePatch(t, `{"business_hour_exceptions":[{"type":"date","date":"2025-13-01","is_closed":true}]}`).Apply(store)
+		assert.Error(t, err)
+	})
+
+	t.Run("zero coordinates are applied when present", func(t *testing.T) {
+		patched, err := parsePatch(t, `{"latitude":0,"longitude":0}`).Apply(newPatchTestStore())
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, patched.Latitude)
+		assert.Equal(t, 0.0, patched.Longitude)
+	})
+
+	t.Run("immutable fields come from the stored copy", func(t *testing.T) {
+		store := newPatchTestStore()
+		patched, err := parsePatch(t, `{"name":"x"}`).Apply(store)
+		require.NoError(t, err)
+		assert.Equal(t, store.ID, patched.ID)
+		assert.Equal(t, store.CreatedBy, patched.CreatedBy)
+		assert.Equal(t, store.UpdatedAt, patched.UpdatedAt)
+	})
+
+	t.Run("invalid result", func(t *testing.T) {
+		_, err := parsePatch(t, `{"website_url":"not a url"}`).Apply(newPatchTestStore())
+		assert.Error(t, err)
+
+		_, err = parsePatch(t, `{"latitude":"north"}`).Apply(newPatchTestStore())
+		assert.Error(t, err)
+
+		_, err = parsePatch(t, `{"business_hours":{"monday":{"time_slots":[{"open_time":"25:99","close_time":"14:00"}]}}}`).Apply(newPatchTestStore())
+		var appErr *errors.AppError
+		require.ErrorAs(t, err, &appErr)
+		assert.Equal(t, "Invalid business hours", appErr.Message)
+		assert.Equal(t, `business_hours.monday.time_slots[0].open_time: must be HH:MM, got "25:99"`, appErr.Details)
+	})
+}
+
+func TestStorePatchRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "patchable fields", body: `{"name":"a","parking_info":null,"tags":["b"]}`},
+		{name: "empty patch", body: `{}`},
+		{name: "clear required field", body: `{"name":null}`, wantErr: true},
+		{name: "clear coordinates", body: `{"latitude":null}`, wantErr: true},
+		{name: "immutable field", body: `{"created_by":"11111111-1111-1111-1111-111111111111"}`, wantErr: true},
+		{name: "unknown field", body: `{"price_range":"$$"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parsePatch(t, tt.body).Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
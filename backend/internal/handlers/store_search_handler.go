@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sukimise/internal/constants"
+	"sukimise/internal/errors"
+	"sukimise/internal/repositories"
+	"sukimise/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StoreSearchRequest is the body of POST /stores/search. Geometry is a GeoJSON
+// Polygon or MultiPolygon, or a Feature holding one. The other filters are the
+// query parameters of GET /stores.
+type StoreSearchRequest struct {
+	Geometry json.RawMessage `json:"geometry" binding:"required"`
+}
+
+// SearchStores lists the stores inside a polygon, e.g. a ward or the area
+// along a train line, combined with the filters of GetStores
+func (h *Handler) SearchStores(c *gin.Context) {
+	var req StoreSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid request data", err.Error()))
+		return
+	}
+
+	polygon, err := parseSearchPolygon(req.Geometry)
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid geometry", err.Error()))
+		return
+	}
+
+	filter, err := h.storeFilterFromRequest(c)
+	if err != nil {
+		errors.HandleError(c, err)
+		return
+	}
+	filter.Polygon = polygon
+
+	h.sendStorePage(c, filter)
+}
+
+// parseBoundingBox parses minLng,minLat,maxLng,maxLat
+func parseBoundingBox(value string) (*repositories.BoundingBox, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("bbox must have 4 values, got %d", len(parts))
+	}
+
+	var values [4]float64
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bbox value %q", part)
+		}
+		values[i] = v
+	}
+
+	return &repositories.BoundingBox{
+		MinLongitude: values[0],
+		MinLatitude:  values[1],
+		MaxLongitude: values[2],
+		MaxLatitude:  values[3],
+	}, nil
+}
+
+// geoJSONObject holds the members of a GeoJSON geometry or Feature used by the search
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    json.RawMessage `json:"geometry"`
+}
+
+// parseSearchPolygon checks a GeoJSON polygon and returns it as a geometry
+// object without other members, ready for ST_GeomFromGeoJSON
+func parseSearchPolygon(raw json.RawMessage) (string, error) {
+	var object geoJSONObject
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return "", fmt.Errorf("geometry must be a GeoJSON object: %v", err)
+	}
+	if object.Type == "Feature" {
+		if err := json.Unmarshal(object.Geometry, &object); err != nil {
+			return "", fmt.Errorf("feature geometry must be a GeoJSON object: %v", err)
+		}
+	}
+
+	var polygons [][][][]float64
+	switch object.Type {
+	case "Polygon":
+		var polygon [][][]float64
+		if err := json.Unmarshal(object.Coordinates, &polygon); err != nil {
+			return "", fmt.Errorf("invalid Polygon coordinates: %v", err)
+		}
+		polygons = append(polygons, polygon)
+	case "MultiPolygon":
+		if err := json.Unmarshal(object.Coordinates, &polygons); err != nil {
+			return "", fmt.Errorf("invalid MultiPolygon coordinates: %v", err)
+		}
+	default:
+		return "", fmt.Errorf("geometry type must be Polygon or MultiPolygon, got %q", object.Type)
+	}
+
+	points := 0
+	for _, polygon := range polygons {
+		if len(polygon) == 0 {
+			return "", fmt.Errorf("a polygon needs at least one ring")
+		}
+		for _, ring := range polygon {
+			if len(ring) < 4 {
+				return "", fmt.Errorf("a ring needs at least 4 positions")
+			}
+			for _, position := range ring {
+				if len(position) < 2 {
+					return "", fmt.Errorf("a position needs a longitude and a latitude")
+				}
+				if err := utils.ValidateCoordinates(position[1], position[0]); err != nil {
+					return "", fmt.Errorf("position [%v, %v] is out of range", position[0], position[1])
+				}
+			}
+			first, last := ring[0], ring[len(ring)-1]
+			if first[0] != last[0] || first[1] != last[1] {
+				return "", fmt.Errorf("a ring must end at its first position")
+			}
+			points += len(ring)
+		}
+	}
+	if points > constants.MaxSearchPolygonPoints {
+		return "", fmt.Errorf("maximum %d positions allowed", constants.MaxSearchPolygonPoints)
+	}
+
+	geometry, err := json.Marshal(map[string]interface{}{
+		"type":        object.Type,
+		"coordinates": object.Coordinates,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(geometry), nil
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSearchPolygon(t *testing.T) {
+	const square = `[[[139.70,35.65],[139.75,35.65],[139.75,35.70],[139.70,35.70],[139.70,35.65]]]`
+
+	tests := []struct {
+		name        string
+		geometry    string
+		expectError bool
+	}{
+		{name: "polygon", geometry: `{"type":"Polygon","coordinates":` + square + `}`},
+		{name: "multi polygon", geometry: `{"type":"MultiPolygon","coordinates":[` + square + `]}`},
+		{name: "feature", geometry: `{"type":"Feature","properties":{"name":"渋谷区"},"geometry":{"type":"Polygon","coordinates":` + square + `}}`},
+		{name: "point", geometry: `{"type":"Point","coordinates":[139.70,35.65]}`, expectError: true},
+		{name: "feature without geometry", geometry: `{"type":"Feature","properties":{}}`, expectError: true},
+		{name: "open ring", geometry: `{"type":"Polygon","coordinates":[[[139.70,35.65],[139.75,35.65],[139.75,35.70],[139.70,35.70]]]}`, expectError: true},
+		{name: "too few positions", geometry: `{"type":"Polygon","coordinates":[[[139.70,35.65],[139.75,35.65],[139.70,35.65]]]}`, expectError: true},
+		{name: "latitude out of range", geometry: `{"type":"Polygon","coordinates":[[[35.65,139.70],[35.65,139.75],[35.70,139.75],[35.65,139.70]]]}`, expectError: true},
+		{name: "no rings", geometry: `{"type":"Polygon","coordinates":[]}`, expectError: true},
+		{name: "not an object", geometry: `"Polygon"`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			polygon, err := parseSearchPolygon(json.RawMessage(tt.geometry))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// Only the geometry members are passed to the database
+			var geometry map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal([]byte(polygon), &geometry))
+			assert.Len(t, geometry, 2)
+			assert.Contains(t, geometry, "type")
+			assert.Contains(t, geometry, "coordinates")
+		})
+	}
+}
+
+func TestParseSearchPolygon_TooManyPositions(t *testing.T) {
+	positions := make([]string, 0, 1001)
+	for i := 0; i < 1000; i++ {
+		positions = append(positions, fmt.Sprintf("[139.%03d,35.6]", i))
+	}
+	positions = append(positions, positions[0])
+
+	_, err := parseSearchPolygon(json.RawMessage(`{"type":"Polygon","coordinates":[[` + strings.Join(positions, ",") + `]]}`))
+	assert.EqualError(t, err, "maximum 1000 positions allowed")
+}
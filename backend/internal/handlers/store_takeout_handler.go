@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sukimise/internal/constants"
+	"sukimise/internal/errors"
+	"sukimise/internal/takeout"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TakeoutImportReport is the import report of a saved places list
+type TakeoutImportReport struct {
+	StoreImportReport
+	ListName string `json:"list_name"`
+}
+
+// ImportStoresTakeout imports a Google Maps saved places list exported by
+// Google Takeout (GeoJSON or CSV) or Google My Maps (KML). Each store is
+// tagged with the list name, which defaults to the file name.
+func (h *Handler) ImportStoresTakeout(c *gin.Context) {
+	dryRun := c.Query("dry_run") == "true"
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, constants.MaxImportFileSize)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		if err == http.ErrMissingFile {
+			errors.HandleError(c, errors.NewValidationError("No file uploaded", "Takeout file is required in the 'file' field"))
+		} else {
+			errors.HandleError(c, errors.NewValidationError("File too large or invalid", err.Error()))
+		}
+		return
+	}
+	defer file.Close()
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	places, err := takeout.Parse(header.Filename, file)
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid Takeout file", err.Error()))
+		return
+	}
+	if len(places) > constants.MaxImportRows {
+		errors.HandleError(c, errors.NewValidationError("Too many places", fmt.Sprintf("Maximum %d places allowed", constants.MaxImportRows)))
+		return
+	}
+
+	listName := strings.TrimSpace(c.PostForm("list"))
+	if listName == "" {
+		listName = takeout.ListName(header.Filename)
+	}
+
+	report := &TakeoutImportReport{
+		StoreImportReport: StoreImportReport{
+			DryRun: dryRun,
+			Rows:   make([]StoreImportRowResult, 0, len(places)),
+		},
+		ListName: listName,
+	}
+	for _, result := range takeout.Import(places, listName, userID.(uuid.UUID), dryRun, h.storeService) {
+		row := StoreImportRowResult{
+			Row:         result.Index,
+			Name:        result.Name,
+			StoreID:     result.StoreID,
+			DuplicateOf: result.DuplicateOf,
+		}
+		switch {
+		case result.Err != nil:
+			row.Status = ImportStatusFailed
+			row.Error = result.Err.Error()
+		case result.DuplicateOf != nil:
+			row.Status = ImportStatusDuplicate
+		default:
+			row.Status = ImportStatusCreated
+		}
+		report.addResult(row)
+	}
+
+	log.Printf("Takeout import of %q completed (dry_run=%t): %d created, %d skipped, %d failed",
+		listName, dryRun, report.Created, report.Skipped, report.Failed)
+	errors.SendSuccess(c, report)
+}
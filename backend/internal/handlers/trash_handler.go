@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"strconv"
+	"sukimise/internal/constants"
+	"sukimise/internal/errors"
+	"sukimise/internal/services"
+	"sukimise/internal/types"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TrashHandler struct {
+	storeService    *services.StoreService
+	retentionPeriod time.Duration
+}
+
+func NewTrashHandler(storeService *services.StoreService, retentionPeriod time.Duration) *TrashHandler {
+	return &TrashHandler{
+		storeService:    storeService,
+		retentionPeriod: retentionPeriod,
+	}
+}
+
+// GetTrash returns stores in the trash
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	limit := constants.DefaultLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			if l > constants.MaxLimit {
+				l = constants.MaxLimit
+			}
+			limit = l
+		}
+	}
+
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	stores, err := h.storeService.GetDeletedStores(limit, offset)
+	if err != nil {
+		log.Printf("Failed to get deleted stores: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get deleted stores"))
+		return
+	}
+
+	totalCount, err := h.storeService.GetDeletedStoresCount()
+	if err != nil {
+		log.Printf("Failed to get deleted stores count: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get deleted stores count"))
+		return
+	}
+
+	totalPages := (totalCount + limit - 1) / limit
+	currentPage := (offset / limit) + 1
+
+	meta := &types.MetaInfo{
+		Total:      totalCount,
+		Limit:      limit,
+		Offset:     offset,
+		Page:       &currentPage,
+		TotalPages: &totalPages,
+	}
+
+	errors.SendSuccess(c, map[string]interface{}{
+		"stores":         stores,
+		"retention_days": int(h.retentionPeriod.Hours() / 24),
+	}, meta)
+}
+
+// RestoreStore moves a store out of the trash
+func (h *TrashHandler) RestoreStore(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		errors.HandleError(c, errors.NewValidationError("Invalid store ID", err.Error()))
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errors.HandleError(c, errors.NewUnauthorizedError("User ID not found in token"))
+		return
+	}
+
+	if err := h.storeService.RestoreStore(id, userID.(uuid.UUID)); err != nil {
+		if err == sql.ErrNoRows {
+			errors.HandleError(c, errors.NewNotFoundError("Deleted store"))
+			return
+		}
+		log.Printf("Failed to restore store: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to restore store"))
+		return
+	}
+
+	store, err := h.storeService.GetStoreByID(id)
+	if err != nil {
+		log.Printf("Failed to get restored store: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to get restored store"))
+		return
+	}
+
+	errors.SendSuccess(c, store)
+}
+
+// PurgeTrash permanently removes stores that have been in the trash longer than the retention period
+func (h *TrashHandler) PurgeTrash(c *gin.Context) {
+	purged, err := h.storeService.PurgeDeletedStores(h.retentionPeriod)
+	if err != nil {
+		log.Printf("Failed to purge deleted stores: %v", err)
+		errors.HandleError(c, errors.NewInternalError("Failed to purge deleted stores"))
+		return
+	}
+
+	log.Printf("Purged %d deleted stores", purged)
+	errors.SendSuccess(c, map[string]interface{}{"purged": purged})
+}
@@ -0,0 +1,140 @@
+// Package holidays is a built-in calendar of Japanese public holidays
+// (国民の祝日). It is computed from the rules of the Act on National Holidays
+// as amended up to 2020, so it works offline and needs no data files.
+// Dates before 2007 or after 2099 are not covered reliably.
+package holidays
+
+import "time"
+
+// Name returns the name of the public holiday on the date of t, or "" when the
+// date is not a holiday. Only the year, month and day of t are used.
+func Name(t time.Time) string {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if name := nationalHoliday(date); name != "" {
+		return name
+	}
+
+	// 振替休日: the first non-holiday after a holiday falling on a Sunday
+	for previous := date.AddDate(0, 0, -1); nationalHoliday(previous) != ""; previous = previous.AddDate(0, 0, -1) {
+		if previous.Weekday() == time.Sunday {
+			return "振替休日"
+		}
+	}
+
+	// 国民の休日: a day between two holidays
+	if nationalHoliday(date.AddDate(0, 0, -1)) != "" && nationalHoliday(date.AddDate(0, 0, 1)) != "" {
+		return "国民の休日"
+	}
+	return ""
+}
+
+// IsHoliday reports whether the date of t is a public holiday
+func IsHoliday(t time.Time) bool {
+	return Name(t) != ""
+}
+
+// specialHolidays are the holidays of one year only: the imperial succession
+// in 2019 and the days moved for the Tokyo Olympics in 2020 and 2021
+var specialHolidays = map[string]string{
+	"2019-04-30": "国民の休日",
+	"2019-05-01": "天皇の即位の日",
+	"2019-05-02": "国民の休日",
+	"2019-10-22": "即位礼正殿の儀の行われる日",
+	"2020-07-23": "海の日",
+	"2020-07-24": "スポーツの日",
+	"2020-08-10": "山の日",
+	"2021-07-22": "海の日",
+	"2021-07-23": "スポーツの日",
+	"2021-08-08": "山の日",
+}
+
+// movedHolidayYears are the years in which 海の日, 山の日 and スポーツの日 were moved
+var movedHolidayYears = map[int]bool{2020: true, 2021: true}
+
+// nationalHoliday returns the name of the holiday defined for the date itself,
+// without substitute holidays and days between holidays
+func nationalHoliday(date time.Time) string {
+	if name, ok := specialHolidays[date.Format("2006-01-02")]; ok {
+		return name
+	}
+
+	year, day := date.Year(), date.Day()
+	switch date.Month() {
+	case time.January:
+		if day == 1 {
+			return "元日"
+		}
+		if isNthMonday(date, 2) {
+			return "成人の日"
+		}
+	case time.February:
+		if day == 11 {
+			return "建国記念の日"
+		}
+		if day == 23 && year >= 2020 {
+			return "天皇誕生日"
+		}
+	case time.March:
+		if day == equinoxDay(year, 20.8431) {
+			return "春分の日"
+		}
+	case time.April:
+		if day == 29 {
+			return "昭和の日"
+		}
+	case time.May:
+		switch day {
+		case 3:
+			return "憲法記念日"
+		case 4:
+			return "みどりの日"
+		case 5:
+			return "こどもの日"
+		}
+	case time.July:
+		if isNthMonday(date, 3) && !movedHolidayYears[year] {
+			return "海の日"
+		}
+	case time.August:
+		if day == 11 && year >= 2016 && !movedHolidayYears[year] {
+			return "山の日"
+		}
+	case time.September:
+		if isNthMonday(date, 3) {
+			return "敬老の日"
+		}
+		if day == equinoxDay(year, 23.2488) {
+			return "秋分の日"
+		}
+	case time.October:
+		if isNthMonday(date, 2) && !movedHolidayYears[year] {
+			if year >= 2020 {
+				return "スポーツの日"
+			}
+			return "体育の日"
+		}
+	case time.November:
+		switch day {
+		case 3:
+			return "文化の日"
+		case 23:
+			return "勤労感謝の日"
+		}
+	case time.December:
+		if day == 23 && year < 2019 {
+			return "天皇誕生日"
+		}
+	}
+	return ""
+}
+
+// isNthMonday reports whether date is the nth Monday of its month
+func isNthMonday(date time.Time, nth int) bool {
+	return date.Weekday() == time.Monday && (date.Day()-1)/7+1 == nth
+}
+
+// equinoxDay approximates the day of the vernal (base 20.8431) or autumnal
+// (base 23.2488) equinox in March or September, valid from 1980 to 2099
+func equinoxDay(year int, base float64) int {
+	return int(base + 0.242194*float64(year-1980) - float64((year-1980)/4))
+}
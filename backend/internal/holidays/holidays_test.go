@@ -0,0 +1,54 @@
+package holidays
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		date     string
+		expected string
+	}{
+		{"2025-01-01", "元日"},
+		{"2025-01-13", "成人の日"},
+		{"2024-02-12", "振替休日"}, // 建国記念の日 on a Sunday
+		{"2025-02-24", "振替休日"}, // 天皇誕生日 on a Sunday
+		{"2018-12-23", "天皇誕生日"},
+		{"2019-12-23", ""},
+		{"2024-03-20", "春分の日"},
+		{"2026-03-20", "春分の日"},
+		{"2025-05-06", "振替休日"}, // みどりの日 on a Sunday, then こどもの日
+		{"2019-05-01", "天皇の即位の日"},
+		{"2025-07-21", "海の日"},
+		{"2020-07-20", ""},
+		{"2021-08-09", "振替休日"}, // 山の日 moved to a Sunday
+		{"2024-09-16", "敬老の日"},
+		{"2024-09-22", "秋分の日"},
+		{"2024-09-23", "振替休日"},
+		{"2026-09-22", "国民の休日"}, // between 敬老の日 and 秋分の日
+		{"2019-10-14", "体育の日"},
+		{"2025-10-13", "スポーツの日"},
+		{"2025-11-24", "振替休日"},
+		{"2025-12-31", ""},
+		{"2025-06-10", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			date, err := time.Parse("2006-01-02", tt.date)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, Name(date))
+		})
+	}
+}
+
+func TestIsHoliday_UsesLocalDate(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+
+	// 2025-01-01 00:30 in Tokyo is still 2024-12-31 in UTC
+	assert.True(t, IsHoliday(time.Date(2025, 1, 1, 0, 30, 0, 0, tokyo)))
+	assert.False(t, IsHoliday(time.Date(2025, 1, 1, 0, 30, 0, 0, tokyo).UTC()))
+}
@@ -98,4 +98,29 @@ func NewAuthMiddleware(jwtSecret string) gin.HandlerFunc {
 
 		c.Next()
 	})
-}
\ No newline at end of file
+}
+
+// OptionalAuth sets the user of a valid Bearer token like Auth, but lets
+// requests without one through anonymously
+func OptionalAuth() gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		tokenParts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+			c.Next()
+			return
+		}
+
+		jwtSecret := os.Getenv("JWT_SECRET")
+		if jwtSecret == "" {
+			jwtSecret = "your-secret-key"
+		}
+		claims, err := auth.NewJWTService(jwtSecret).ValidateToken(tokenParts[1])
+		if err == nil {
+			c.Set("user_id", claims.UserID)
+			c.Set("username", claims.Username)
+			c.Set("role", claims.Role)
+		}
+
+		c.Next()
+	})
+}
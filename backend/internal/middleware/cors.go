@@ -13,8 +13,8 @@ func CORS() gin.HandlerFunc {
 	// 開発環境のデフォルト設定
 	defaultConfig := config.CORSConfig{
 		AllowedOrigins:   []string{"http://localhost:3000", "http://localhost:5173"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Origin", "Content-Type", "Accept", "Authorization", "X-CSRF-Token", "X-Viewer-Token"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Origin", "Content-Type", "Accept", "Authorization", "X-CSRF-Token", "X-Viewer-Token", "If-Match"},
 		AllowCredentials: true,
 		MaxAge:           86400,
 	}
@@ -42,6 +42,7 @@ func NewCORSMiddleware(corsConfig config.CORSConfig) gin.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", boolToString(corsConfig.AllowCredentials))
 		c.Writer.Header().Set("Access-Control-Allow-Headers", strings.Join(corsConfig.AllowedHeaders, ", "))
 		c.Writer.Header().Set("Access-Control-Allow-Methods", strings.Join(corsConfig.AllowedMethods, ", "))
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
 		
 		if corsConfig.MaxAge > 0 {
 			c.Writer.Header().Set("Access-Control-Max-Age", intToString(corsConfig.MaxAge))
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BackupFormatVersion is the version written to the backup meta record
+const BackupFormatVersion = 1
+
+// Backup record types, in the order they are written and restored
+const (
+	BackupTypeMeta                  = "meta"
+	BackupTypeUser                  = "user"
+	BackupTypeStore                 = "store"
+	BackupTypeReview                = "review"
+	BackupTypeMenuItem              = "menu_item"
+	BackupTypeCategoryCustomization = "category_customization"
+	BackupTypeStoreList             = "store_list"
+	BackupTypeStoreListEntry        = "store_list_entry"
+	BackupTypeShareLink             = "share_link"
+)
+
+// BackupRecord represents a single NDJSON line of a backup
+type BackupRecord struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// BackupMeta is the first record of every backup
+type BackupMeta struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BackupStoreListEntry is a store list entry together with its list, which
+// StoreListEntry leaves out as it is only returned inside its list
+type BackupStoreListEntry struct {
+	ListID    uuid.UUID `json:"list_id"`
+	StoreID   uuid.UUID `json:"store_id"`
+	Position  int       `json:"position"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BackupData holds all records of a parsed backup
+type BackupData struct {
+	Meta                   BackupMeta
+	Users                  []*User
+	Stores                 []*Store
+	Reviews                []*Review
+	MenuItems              []*MenuItem
+	CategoryCustomizations []*CategoryCustomization
+	StoreLists             []*StoreList
+	StoreListEntries       []*BackupStoreListEntry
+	ShareLinks             []*ShareLink
+}
+
+// RestoreResult reports how many records of each type were restored
+type RestoreResult struct {
+	Users                  int `json:"users"`
+	Stores                 int `json:"stores"`
+	Reviews                int `json:"reviews"`
+	MenuItems              int `json:"menu_items"`
+	CategoryCustomizations int `json:"category_customizations"`
+	StoreLists             int `json:"store_lists"`
+	StoreListEntries       int `json:"store_list_entries"`
+	ShareLinks             int `json:"share_links"`
+}
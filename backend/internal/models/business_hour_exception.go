@@ -0,0 +1,144 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Business hour exception types
+const (
+	BusinessHourExceptionDate       = "date"        // 特定の日付または期間（YYYY-MM-DD）
+	BusinessHourExceptionAnnual     = "annual"      // 毎年の日付または期間（MM-DD）、年末年始など
+	BusinessHourExceptionHoliday    = "holiday"     // 祝日
+	BusinessHourExceptionNthWeekday = "nth_weekday" // 毎月第N曜日、第2火曜など
+)
+
+// MaxBusinessHourExceptions is the maximum number of exceptions of a store
+const MaxBusinessHourExceptions = 50
+
+// BusinessHourException replaces the weekly business hours of a store on the
+// matching dates, either with a closure or with other time slots. When several
+// exceptions match a date, date comes first, then annual, holiday and nth_weekday.
+type BusinessHourException struct {
+	Type      string     `json:"type"`
+	Date      string     `json:"date,omitempty"`     // date: YYYY-MM-DD, annual: MM-DD
+	EndDate   string     `json:"end_date,omitempty"` // Last day of a period, same format as Date
+	Weekday   string     `json:"weekday,omitempty"`  // nth_weekday: monday-sunday
+	Nth       int        `json:"nth,omitempty"`      // nth_weekday: 1-5
+	IsClosed  bool       `json:"is_closed"`
+	TimeSlots []TimeSlot `json:"time_slots"`
+	Note      string     `json:"note,omitempty"`
+}
+
+// BusinessHourExceptions is the list of exceptions of a store
+type BusinessHourExceptions []BusinessHourException
+
+// Value implements driver.Valuer for BusinessHourExceptions
+func (e BusinessHourExceptions) Value() (driver.Value, error) {
+	if e == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan implements sql.Scanner for BusinessHourExceptions
+func (e *BusinessHourExceptions) Scan(value interface{}) error {
+	if value == nil {
+		*e = BusinessHourExceptions{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("cannot scan business_hour_exceptions: unsupported type")
+	}
+
+	return json.Unmarshal(bytes, e)
+}
+
+var businessHourWeekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// Validate checks the type specific fields of every exception.
+// Errors name the offending field, e.g. business_hour_exceptions[1].date.
+func (e BusinessHourExceptions) Validate() error {
+	if len(e) > MaxBusinessHourExceptions {
+		return fmt.Errorf("business_hour_exceptions: maximum %d exceptions allowed", MaxBusinessHourExceptions)
+	}
+	for i, exception := range e {
+		if err := exception.validate(); err != nil {
+			return fmt.Errorf("business_hour_exceptions[%d].%w", i, err)
+		}
+	}
+	return nil
+}
+
+func (e BusinessHourException) validate() error {
+	switch e.Type {
+	case BusinessHourExceptionDate:
+		if err := validateExceptionDates(e.Date, e.EndDate); err != nil {
+			return err
+		}
+	case BusinessHourExceptionAnnual:
+		// Periods may wrap around the new year, e.g. 12-29 to 01-03.
+		// 2000 is a leap year, so 02-29 is accepted.
+		if _, err := time.Parse("2006-01-02", "2000-"+e.Date); err != nil {
+			return errors.New("date: must be MM-DD")
+		}
+		if e.EndDate != "" {
+			if _, err := time.Parse("2006-01-02", "2000-"+e.EndDate); err != nil {
+				return errors.New("end_date: must be MM-DD")
+			}
+		}
+	case BusinessHourExceptionHoliday:
+	case BusinessHourExceptionNthWeekday:
+		valid := false
+		for _, day := range businessHourWeekdays {
+			if e.Weekday == day {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return errors.New("weekday: must be one of monday-sunday")
+		}
+		if e.Nth < 1 || e.Nth > 5 {
+			return errors.New("nth: must be between 1 and 5")
+		}
+	default:
+		return fmt.Errorf("type: must be one of %s, %s, %s, %s",
+			BusinessHourExceptionDate, BusinessHourExceptionAnnual,
+			BusinessHourExceptionHoliday, BusinessHourExceptionNthWeekday)
+	}
+
+	if !e.IsClosed && len(e.TimeSlots) == 0 {
+		return errors.New("time_slots: required unless is_closed is true")
+	}
+	return validateTimeSlots(e.TimeSlots)
+}
+
+// validateExceptionDates checks a date and an optional end date not before it
+func validateExceptionDates(date, endDate string) error {
+	start, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return errors.New("date: must be YYYY-MM-DD")
+	}
+	if endDate == "" {
+		return nil
+	}
+	end, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return errors.New("end_date: must be YYYY-MM-DD")
+	}
+	if end.Before(start) {
+		return errors.New("end_date: must not be before date")
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusinessHourExceptions_Validate(t *testing.T) {
+	slots := []TimeSlot{{OpenTime: "11:00", CloseTime: "15:00"}}
+
+	tests := []struct {
+		name        string
+		exception   BusinessHourException
+		expectError string
+	}{
+		{
+			name:      "closed on a date",
+			exception: BusinessHourException{Type: BusinessHourExceptionDate, Date: "2025-08-13", IsClosed: true},
+		},
+		{
+			name:      "special hours for a period",
+			exception: BusinessHourException{Type: BusinessHourExceptionDate, Date: "2025-08-13", EndDate: "2025-08-16", TimeSlots: slots},
+		},
+		{
+			name:        "period ending before it starts",
+			exception:   BusinessHourException{Type: BusinessHourExceptionDate, Date: "2025-08-16", EndDate: "2025-08-13", IsClosed: true},
+			expectError: "business_hour_exceptions[0].end_date: must not be before date",
+		},
+		{
+			name:        "invalid date",
+			exception:   BusinessHourException{Type: BusinessHourExceptionDate, Date: "2025/08/13", IsClosed: true},
+			expectError: "business_hour_exceptions[0].date: must be YYYY-MM-DD",
+		},
+		{
+			name:      "new year period",
+			exception: BusinessHourException{Type: BusinessHourExceptionAnnual, Date: "12-29", EndDate: "01-03", IsClosed: true},
+		},
+		{
+			name:      "leap day",
+			exception: BusinessHourException{Type: BusinessHourExceptionAnnual, Date: "02-29", IsClosed: true},
+		},
+		{
+			name:        "annual date with year",
+			exception:   BusinessHourException{Type: BusinessHourExceptionAnnual, Date: "2025-12-31", IsClosed: true},
+			expectError: "business_hour_exceptions[0].date: must be MM-DD",
+		},
+		{
+			name:      "holidays",
+			exception: BusinessHourException{Type: BusinessHourExceptionHoliday, TimeSlots: slots},
+		},
+		{
+			name:      "second tuesday",
+			exception: BusinessHourException{Type: BusinessHourExceptionNthWeekday, Weekday: "tuesday", Nth: 2, IsClosed: true},
+		},
+		{
+			name:        "sixth tuesday",
+			exception:   BusinessHourException{Type: BusinessHourExceptionNthWeekday, Weekday: "tuesday", Nth: 6, IsClosed: true},
+			expectError: "business_hour_exceptions[0].nth: must be between 1 and 5",
+		},
+		{
+			name:        "unknown weekday",
+			exception:   BusinessHourException{Type: BusinessHourExceptionNthWeekday, Weekday: "tue", Nth: 2, IsClosed: true},
+			expectError: "business_hour_exceptions[0].weekday: must be one of monday-sunday",
+		},
+		{
+			name:        "open without time slots",
+			exception:   BusinessHourException{Type: BusinessHourExceptionHoliday},
+			expectError: "business_hour_exceptions[0].time_slots: required unless is_closed is true",
+		},
+		{
+			name:        "unknown type",
+			exception:   BusinessHourException{Type: "weekly", IsClosed: true},
+			expectError: "business_hour_exceptions[0].type: must be one of date, annual, holiday, nth_weekday",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := BusinessHourExceptions{tt.exception}.Validate()
+			if tt.expectError != "" {
+				assert.EqualError(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBusinessHourExceptions_Value(t *testing.T) {
+	value, err := BusinessHourExceptions(nil).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("[]"), value)
+
+	var exceptions BusinessHourExceptions
+	assert.NoError(t, exceptions.Scan([]byte(`[{"type":"holiday","is_closed":true}]`)))
+	assert.Equal(t, BusinessHourExceptions{{Type: BusinessHourExceptionHoliday, IsClosed: true}}, exceptions)
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// MaxTimeSlots is the maximum number of time slots of a day
+const MaxTimeSlots = 3
+
+var timeOfDayPattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// Validate checks the time slots of every day.
+// Errors name the offending field, e.g. business_hours.monday.time_slots[0].open_time.
+func (b BusinessHoursData) Validate() error {
+	days := []struct {
+		name     string
+		schedule DaySchedule
+	}{
+		{"monday", b.Monday},
+		{"tuesday", b.Tuesday},
+		{"wednesday", b.Wednesday},
+		{"thursday", b.Thursday},
+		{"friday", b.Friday},
+		{"saturday", b.Saturday},
+		{"sunday", b.Sunday},
+	}
+	for _, day := range days {
+		if err := validateTimeSlots(day.schedule.TimeSlots); err != nil {
+			return fmt.Errorf("business_hours.%s.%w", day.name, err)
+		}
+	}
+	return nil
+}
+
+// timeSlotRange is a time slot in minutes from the start of its day. A slot
+// closing at or before its opening time runs past midnight, so its close
+// minute is beyond 1440, as in the business hours SQL functions.
+type timeSlotRange struct {
+	index       int
+	openMinute  int
+	closeMinute int
+}
+
+// validateTimeSlots checks the format of each slot, the order of its times,
+// and that the slots of a day do not overlap
+func validateTimeSlots(slots []TimeSlot) error {
+	if len(slots) > MaxTimeSlots {
+		return fmt.Errorf("time_slots: maximum %d time slots allowed", MaxTimeSlots)
+	}
+
+	ranges := make([]timeSlotRange, len(slots))
+	for i, slot := range slots {
+		r, err := slot.minuteRange()
+		if err != nil {
+			return fmt.Errorf("time_slots[%d].%w", i, err)
+		}
+		r.index = i
+		ranges[i] = r
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].openMinute < ranges[j].openMinute })
+	for i := 1; i < len(ranges); i++ {
+		if ranges[i].openMinute < ranges[i-1].closeMinute {
+			return fmt.Errorf("time_slots[%d]: overlaps time_slots[%d]", ranges[i].index, ranges[i-1].index)
+		}
+	}
+	// An overnight slot must not run into the first slot of the day either
+	if len(ranges) > 1 && ranges[len(ranges)-1].closeMinute-1440 > ranges[0].openMinute {
+		return fmt.Errorf("time_slots[%d]: overlaps time_slots[%d]", ranges[len(ranges)-1].index, ranges[0].index)
+	}
+	return nil
+}
+
+// minuteRange validates the slot and returns its open and close minutes
+func (s TimeSlot) minuteRange() (timeSlotRange, error) {
+	openMinute, err := minuteOfDay(s.OpenTime)
+	if err != nil {
+		return timeSlotRange{}, fmt.Errorf("open_time: %w", err)
+	}
+	closeMinute, err := minuteOfDay(s.CloseTime)
+	if err != nil {
+		return timeSlotRange{}, fmt.Errorf("close_time: %w", err)
+	}
+	if closeMinute == openMinute {
+		return timeSlotRange{}, errors.New("close_time: must differ from open_time")
+	}
+	if closeMinute < openMinute {
+		closeMinute += 1440
+	}
+
+	if s.LastOrderTime != "" {
+		lastOrder, err := minuteOfDay(s.LastOrderTime)
+		if err != nil {
+			return timeSlotRange{}, fmt.Errorf("last_order_time: %w", err)
+		}
+		if lastOrder <= openMinute {
+			lastOrder += 1440
+		}
+		if lastOrder > closeMinute {
+			return timeSlotRange{}, errors.New("last_order_time: must be between open_time and close_time")
+		}
+	}
+
+	return timeSlotRange{openMinute: openMinute, closeMinute: closeMinute}, nil
+}
+
+// minuteOfDay parses an HH:MM time into the minute of the day
+func minuteOfDay(value string) (int, error) {
+	if !timeOfDayPattern.MatchString(value) {
+		return 0, fmt.Errorf("must be HH:MM, got %q", value)
+	}
+	return int(value[0]-'0')*600 + int(value[1]-'0')*60 + int(value[3]-'0')*10 + int(value[4]-'0'), nil
+}
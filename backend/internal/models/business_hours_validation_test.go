@@ -0,0 +1,109 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusinessHoursData_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		slots       []TimeSlot
+		expectError string
+	}{
+		{
+			name: "no slots",
+		},
+		{
+			name:  "lunch and dinner",
+			slots: []TimeSlot{{OpenTime: "11:00", CloseTime: "14:00", LastOrderTime: "13:30"}, {OpenTime: "17:00", CloseTime: "22:00"}},
+		},
+		{
+			name:  "overnight with last order after midnight",
+			slots: []TimeSlot{{OpenTime: "18:00", CloseTime: "02:00", LastOrderTime: "01:30"}},
+		},
+		{
+			name:  "overnight with last order before midnight",
+			slots: []TimeSlot{{OpenTime: "18:00", CloseTime: "02:00", LastOrderTime: "23:30"}},
+		},
+		{
+			name:  "slots touching each other",
+			slots: []TimeSlot{{OpenTime: "17:00", CloseTime: "00:00"}, {OpenTime: "11:00", CloseTime: "17:00"}},
+		},
+		{
+			name:        "hour out of range",
+			slots:       []TimeSlot{{OpenTime: "25:99", CloseTime: "14:00"}},
+			expectError: `business_hours.wednesday.time_slots[0].open_time: must be HH:MM, got "25:99"`,
+		},
+		{
+			name:        "missing close time",
+			slots:       []TimeSlot{{OpenTime: "11:00"}},
+			expectError: `business_hours.wednesday.time_slots[0].close_time: must be HH:MM, got ""`,
+		},
+		{
+			name:        "single digit hour",
+			slots:       []TimeSlot{{OpenTime: "9:00", CloseTime: "14:00"}},
+			expectError: `business_hours.wednesday.time_slots[0].open_time: must be HH:MM, got "9:00"`,
+		},
+		{
+			name:        "same open and close time",
+			slots:       []TimeSlot{{OpenTime: "11:00", CloseTime: "11:00"}},
+			expectError: "business_hours.wednesday.time_slots[0].close_time: must differ from open_time",
+		},
+		{
+			name:        "last order after close",
+			slots:       []TimeSlot{{OpenTime: "11:00", CloseTime: "14:00", LastOrderTime: "14:30"}},
+			expectError: "business_hours.wednesday.time_slots[0].last_order_time: must be between open_time and close_time",
+		},
+		{
+			name:        "last order before open",
+			slots:       []TimeSlot{{OpenTime: "11:00", CloseTime: "14:00", LastOrderTime: "10:30"}},
+			expectError: "business_hours.wednesday.time_slots[0].last_order_time: must be between open_time and close_time",
+		},
+		{
+			name:        "invalid last order",
+			slots:       []TimeSlot{{OpenTime: "11:00", CloseTime: "14:00", LastOrderTime: "1330"}},
+			expectError: `business_hours.wednesday.time_slots[0].last_order_time: must be HH:MM, got "1330"`,
+		},
+		{
+			name:        "overlapping slots",
+			slots:       []TimeSlot{{OpenTime: "17:00", CloseTime: "22:00"}, {OpenTime: "11:00", CloseTime: "17:30"}},
+			expectError: "business_hours.wednesday.time_slots[0]: overlaps time_slots[1]",
+		},
+		{
+			name:        "overnight slot running into the first slot",
+			slots:       []TimeSlot{{OpenTime: "06:00", CloseTime: "10:00"}, {OpenTime: "18:00", CloseTime: "07:00"}},
+			expectError: "business_hours.wednesday.time_slots[1]: overlaps time_slots[0]",
+		},
+		{
+			name: "too many slots",
+			slots: []TimeSlot{
+				{OpenTime: "07:00", CloseTime: "09:00"}, {OpenTime: "11:00", CloseTime: "14:00"},
+				{OpenTime: "17:00", CloseTime: "20:00"}, {OpenTime: "21:00", CloseTime: "23:00"},
+			},
+			expectError: "business_hours.wednesday.time_slots: maximum 3 time slots allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hours := GetDefaultBusinessHours()
+			hours.Wednesday.TimeSlots = tt.slots
+			err := hours.Validate()
+			if tt.expectError != "" {
+				assert.EqualError(t, err, tt.expectError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBusinessHourExceptions_ValidateTimeSlots(t *testing.T) {
+	exceptions := BusinessHourExceptions{
+		{Type: BusinessHourExceptionHoliday, IsClosed: true},
+		{Type: BusinessHourExceptionDate, Date: "2025-12-31", TimeSlots: []TimeSlot{{OpenTime: "11:00", CloseTime: "24:00"}}},
+	}
+	assert.EqualError(t, exceptions.Validate(), `business_hour_exceptions[1].time_slots[0].close_time: must be HH:MM, got "24:00"`)
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Name matching modes of the duplicate store check
+const (
+	DuplicateNameExact      = "exact"      // 店名が完全一致
+	DuplicateNameNormalized = "normalized" // 全角半角・大文字小文字・カタカナひらがな・空白の違いを無視して一致
+	DuplicateNameFuzzy      = "fuzzy"      // 正規化した店名のトライグラム類似度がMinSimilarity以上
+)
+
+// DuplicatePolicy decides when a new store counts as a duplicate of an
+// existing one: within Radius meters and with a matching name
+type DuplicatePolicy struct {
+	Radius        float64 `json:"radius"`
+	NameMatch     string  `json:"name_match"`
+	MinSimilarity float64 `json:"min_similarity,omitempty"` // fuzzy only
+}
+
+// DefaultDuplicatePolicy is the original rule: the same name within 50m
+var DefaultDuplicatePolicy = DuplicatePolicy{Radius: 50, NameMatch: DuplicateNameExact}
+
+// Validate checks the radius, the name matching mode and, for fuzzy, the similarity
+func (p DuplicatePolicy) Validate() error {
+	if p.Radius <= 0 {
+		return fmt.Errorf("radius must be positive, got %v", p.Radius)
+	}
+	switch p.NameMatch {
+	case DuplicateNameExact, DuplicateNameNormalized:
+	case DuplicateNameFuzzy:
+		if p.MinSimilarity <= 0 || p.MinSimilarity > 1 {
+			return fmt.Errorf("min_similarity must be greater than 0 and at most 1, got %v", p.MinSimilarity)
+		}
+	default:
+		return fmt.Errorf("name_match must be one of %s, %s, %s, got %q",
+			DuplicateNameExact, DuplicateNameNormalized, DuplicateNameFuzzy, p.NameMatch)
+	}
+	return nil
+}
+
+// StoreDuplicateConflict is the body of the 409 returned when a new store
+// duplicates an existing one. Sending the request again with force=true
+// creates the store anyway.
+type StoreDuplicateConflict struct {
+	DuplicateID uuid.UUID       `json:"duplicate_id"`
+	Duplicate   *Store          `json:"duplicate"`
+	Policy      DuplicatePolicy `json:"policy"`
+}
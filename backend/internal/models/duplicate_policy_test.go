@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicatePolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  DuplicatePolicy
+		wantErr bool
+	}{
+		{name: "default", policy: DefaultDuplicatePolicy},
+		{name: "normalized", policy: DuplicatePolicy{Radius: 100, NameMatch: DuplicateNameNormalized}},
+		{name: "fuzzy", policy: DuplicatePolicy{Radius: 30, NameMatch: DuplicateNameFuzzy, MinSimilarity: 0.5}},
+		{name: "similarity is ignored unless fuzzy", policy: DuplicatePolicy{Radius: 50, NameMatch: DuplicateNameExact, MinSimilarity: 2}},
+		{name: "zero radius", policy: DuplicatePolicy{Radius: 0, NameMatch: DuplicateNameExact}, wantErr: true},
+		{name: "negative radius", policy: DuplicatePolicy{Radius: -10, NameMatch: DuplicateNameExact}, wantErr: true},
+		{name: "unknown mode", policy: DuplicatePolicy{Radius: 50, NameMatch: "prefix"}, wantErr: true},
+		{name: "empty mode", policy: DuplicatePolicy{Radius: 50}, wantErr: true},
+		{name: "fuzzy without similarity", policy: DuplicatePolicy{Radius: 50, NameMatch: DuplicateNameFuzzy}, wantErr: true},
+		{name: "fuzzy similarity above 1", policy: DuplicatePolicy{Radius: 50, NameMatch: DuplicateNameFuzzy, MinSimilarity: 1.5}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
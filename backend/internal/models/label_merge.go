@@ -0,0 +1,39 @@
+package models
+
+// Kinds of store labels that can be renamed and merged
+const (
+	LabelKindTag      = "tag"
+	LabelKindCategory = "category"
+)
+
+// LabelMergeResult is the outcome of renaming or merging tags or categories
+type LabelMergeResult struct {
+	Kind                  string   `json:"kind"`
+	From                  []string `json:"from"`
+	To                    string   `json:"to"`
+	AffectedStores        int      `json:"affected_stores"`
+	DryRun                bool     `json:"dry_run"`
+	CustomizationMoved    bool     `json:"customization_moved,omitempty"`    // カテゴリのアイコン・色を統合先に移動したか
+	CustomizationsRemoved []string `json:"customizations_removed,omitempty"` // 統合先に既に設定があったため削除したカテゴリ
+}
+
+// ReplaceLabels replaces every label in from with to, keeping the position of
+// the first replaced label and dropping duplicates. It reports whether the
+// labels changed.
+func ReplaceLabels(labels StringArray, from []string, to string) (StringArray, bool) {
+	replaced := StringArray{}
+	changed := false
+	for _, label := range labels {
+		if containsString(from, label) {
+			label = to
+			changed = true
+		}
+		if !containsString(replaced, label) {
+			replaced = append(replaced, label)
+		}
+	}
+	if !changed {
+		return labels, false
+	}
+	return replaced, true
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceLabels(t *testing.T) {
+	tests := []struct {
+		name            string
+		labels          StringArray
+		from            []string
+		to              string
+		expected        StringArray
+		expectedChanged bool
+	}{
+		{
+			name:            "rename keeps position",
+			labels:          StringArray{"カフェ", "らーめん", "中華"},
+			from:            []string{"らーめん"},
+			to:              "ラーメン",
+			expected:        StringArray{"カフェ", "ラーメン", "中華"},
+			expectedChanged: true,
+		},
+		{
+			name:            "merge drops duplicates",
+			labels:          StringArray{"らーめん", "カフェ", "ラーメン"},
+			from:            []string{"らーめん"},
+			to:              "ラーメン",
+			expected:        StringArray{"ラーメン", "カフェ"},
+			expectedChanged: true,
+		},
+		{
+			name:            "merge several sources",
+			labels:          StringArray{"らーめん", "拉麺"},
+			from:            []string{"らーめん", "拉麺"},
+			to:              "ラーメン",
+			expected:        StringArray{"ラーメン"},
+			expectedChanged: true,
+		},
+		{
+			name:            "no match",
+			labels:          StringArray{"カフェ"},
+			from:            []string{"らーめん"},
+			to:              "ラーメン",
+			expected:        StringArray{"カフェ"},
+			expectedChanged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, changed := ReplaceLabels(tt.labels, tt.from, tt.to)
+			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.expectedChanged, changed)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Resources a share link can give access to
+const (
+	ShareScopeStore  = "store"  // 1店舗
+	ShareScopeList   = "list"   // 1つのリスト
+	ShareScopeFilter = "filter" // 保存した検索条件に一致する店舗
+)
+
+// ShareLink gives read-only access to one store, list or store filter to
+// anyone with its token, until it expires or is revoked
+type ShareLink struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	Scope          string     `json:"scope" db:"scope"`
+	StoreID        *uuid.UUID `json:"store_id,omitempty" db:"store_id"`
+	ListID         *uuid.UUID `json:"list_id,omitempty" db:"list_id"`
+	FilterQuery    *string    `json:"filter_query,omitempty" db:"filter_query"` // GET /stores query parameters
+	CreatedBy      uuid.UUID  `json:"created_by" db:"created_by"`
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	AccessCount    int        `json:"access_count" db:"access_count"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty" db:"last_accessed_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	Token          string     `json:"token,omitempty"` // Signed token of the link, not stored
+}
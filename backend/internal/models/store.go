@@ -17,6 +17,7 @@ type Store struct {
 	Longitude     float64          `json:"longitude" db:"longitude"`
 	Categories    StringArray      `json:"categories" db:"categories"`
 	BusinessHours BusinessHoursData `json:"business_hours" db:"business_hours"`
+	BusinessHourExceptions BusinessHourExceptions `json:"business_hour_exceptions" db:"business_hour_exceptions"` // 祝日・臨時休業などの例外
 	ParkingInfo   string           `json:"parking_info" db:"parking_info"`
 	WebsiteURL    string           `json:"website_url" db:"website_url"`
 	GoogleMapURL  string           `json:"google_map_url" db:"google_map_url"`
@@ -26,6 +27,15 @@ type Store struct {
 	CreatedBy     uuid.UUID        `json:"created_by" db:"created_by"`
 	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time        `json:"updated_at" db:"updated_at"`
+	DeletedAt     *time.Time       `json:"deleted_at,omitempty" db:"deleted_at"` // 削除日時（ゴミ箱内の店舗のみ）
+	ReviewSummary *StoreReviewSummary `json:"review_summary,omitempty" db:"-"` // レビューの集計（店舗一覧のみ）
+}
+
+// StoreReviewSummary aggregates the reviews of a store
+type StoreReviewSummary struct {
+	AverageRating *float64   `json:"average_rating"`  // 平均評価（評価付きレビューが無ければnull）
+	ReviewCount   int        `json:"review_count"`
+	LastVisitDate *time.Time `json:"last_visit_date"` // 訪問済みレビューの最新の訪問日
 }
 
 // BusinessHoursData represents detailed business hours structure
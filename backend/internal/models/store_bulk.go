@@ -0,0 +1,87 @@
+package models
+
+import (
+	"github.com/google/uuid"
+)
+
+// Bulk store operations
+const (
+	BulkOperationAddTags          = "add_tags"
+	BulkOperationRemoveTags       = "remove_tags"
+	BulkOperationAddCategories    = "add_categories"
+	BulkOperationRemoveCategories = "remove_categories"
+	BulkOperationDelete           = "delete"
+)
+
+// Per-store results of a bulk operation
+const (
+	BulkStatusUpdated   = "updated"
+	BulkStatusUnchanged = "unchanged"
+	BulkStatusDeleted   = "deleted"
+	BulkStatusNotFound  = "not_found"
+	BulkStatusForbidden = "forbidden"
+	BulkStatusFailed    = "failed"
+)
+
+// StoreBulkOperation is one operation applied to many stores
+type StoreBulkOperation struct {
+	Operation string   `json:"operation"`
+	Values    []string `json:"values,omitempty"` // 追加・削除するタグまたはカテゴリ
+}
+
+// StoreBulkResult is the outcome of a bulk operation for one store
+type StoreBulkResult struct {
+	StoreID uuid.UUID `json:"store_id"`
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Apply changes the tags or categories of a store and reports whether anything changed.
+// It does nothing for BulkOperationDelete.
+func (o StoreBulkOperation) Apply(store *Store) bool {
+	switch o.Operation {
+	case BulkOperationAddTags:
+		return addStrings(&store.Tags, o.Values)
+	case BulkOperationRemoveTags:
+		return removeStrings(&store.Tags, o.Values)
+	case BulkOperationAddCategories:
+		return addStrings(&store.Categories, o.Values)
+	case BulkOperationRemoveCategories:
+		return removeStrings(&store.Categories, o.Values)
+	}
+	return false
+}
+
+func addStrings(array *StringArray, values []string) bool {
+	changed := false
+	for _, value := range values {
+		if !containsString(*array, value) {
+			*array = append(*array, value)
+			changed = true
+		}
+	}
+	return changed
+}
+
+func removeStrings(array *StringArray, values []string) bool {
+	kept := StringArray{}
+	for _, item := range *array {
+		if !containsString(values, item) {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) == len(*array) {
+		return false
+	}
+	*array = kept
+	return true
+}
+
+func containsString(array []string, value string) bool {
+	for _, item := range array {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreBulkOperation_Apply(t *testing.T) {
+	tests := []struct {
+		name               string
+		operation          StoreBulkOperation
+		expectedChanged    bool
+		expectedTags       StringArray
+		expectedCategories StringArray
+	}{
+		{
+			name:               "add new tags",
+			operation:          StoreBulkOperation{Operation: BulkOperationAddTags, Values: []string{"人気", "駅近"}},
+			expectedChanged:    true,
+			expectedTags:       StringArray{"人気", "安い", "駅近"},
+			expectedCategories: StringArray{"カフェ"},
+		},
+		{
+			name:               "add existing tag",
+			operation:          StoreBulkOperation{Operation: BulkOperationAddTags, Values: []string{"人気"}},
+			expectedChanged:    false,
+			expectedTags:       StringArray{"人気", "安い"},
+			expectedCategories: StringArray{"カフェ"},
+		},
+		{
+			name:               "remove tag",
+			operation:          StoreBulkOperation{Operation: BulkOperationRemoveTags, Values: []string{"安い", "駅近"}},
+			expectedChanged:    true,
+			expectedTags:       StringArray{"人気"},
+			expectedCategories: StringArray{"カフェ"},
+		},
+		{
+			name:               "remove missing tag",
+			operation:          StoreBulkOperation{Operation: BulkOperationRemoveTags, Values: []string{"駅近"}},
+			expectedChanged:    false,
+			expectedTags:       StringArray{"人気", "安い"},
+			expectedCategories: StringArray{"カフェ"},
+		},
+		{
+			name:               "add category",
+			operation:          StoreBulkOperation{Operation: BulkOperationAddCategories, Values: []string{"パン"}},
+			expectedChanged:    true,
+			expectedTags:       StringArray{"人気", "安い"},
+			expectedCategories: StringArray{"カフェ", "パン"},
+		},
+		{
+			name:               "remove category",
+			operation:          StoreBulkOperation{Operation: BulkOperationRemoveCategories, Values: []string{"カフェ"}},
+			expectedChanged:    true,
+			expectedTags:       StringArray{"人気", "安い"},
+			expectedCategories: StringArray{},
+		},
+		{
+			name:               "delete does not change fields",
+			operation:          StoreBulkOperation{Operation: BulkOperationDelete},
+			expectedChanged:    false,
+			expectedTags:       StringArray{"人気", "安い"},
+			expectedCategories: StringArray{"カフェ"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &Store{Tags: StringArray{"人気", "安い"}, Categories: StringArray{"カフェ"}}
+
+			changed := tt.operation.Apply(store)
+			assert.Equal(t, tt.expectedChanged, changed)
+			assert.Equal(t, tt.expectedTags, store.Tags)
+			assert.Equal(t, tt.expectedCategories, store.Categories)
+		})
+	}
+
+	t.Run("add to nil tags", func(t *testing.T) {
+		store := &Store{}
+		changed := StoreBulkOperation{Operation: BulkOperationAddTags, Values: []string{"人気"}}.Apply(store)
+		assert.True(t, changed)
+		assert.Equal(t, StringArray{"人気"}, store.Tags)
+	})
+}
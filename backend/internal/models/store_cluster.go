@@ -0,0 +1,21 @@
+package models
+
+import "github.com/google/uuid"
+
+// StoreCluster is a group of nearby stores shown as one marker on the map
+type StoreCluster struct {
+	Latitude   float64                `json:"latitude"` // 店舗の重心
+	Longitude  float64                `json:"longitude"`
+	Count      int                    `json:"count"`
+	StoreID    *uuid.UUID             `json:"store_id,omitempty"` // 1店舗だけのクラスタの店舗
+	Categories []StoreClusterCategory `json:"categories"`         // 店舗数の多い順
+}
+
+// StoreClusterCategory is the number of stores of a category in a cluster,
+// with the icon and color of its CategoryCustomization
+type StoreClusterCategory struct {
+	Name  string  `json:"name"`
+	Count int     `json:"count"`
+	Icon  *string `json:"icon"`
+	Color *string `json:"color"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Visibility of store lists
+const (
+	ListVisibilityPrivate = "private" // 作成者のみ
+	ListVisibilityEditors = "editors" // ログインしたユーザー（編集者・管理者）
+	ListVisibilityViewers = "viewers" // 閲覧者を含む全員
+)
+
+// StoreList is a personal, ordered list of stores such as "デート候補"
+type StoreList struct {
+	ID            uuid.UUID         `json:"id" db:"id"`
+	OwnerID       uuid.UUID         `json:"owner_id" db:"owner_id"`
+	OwnerUsername string            `json:"owner_username" db:"owner_username"`
+	Title         string            `json:"title" db:"title"`
+	Description   string            `json:"description" db:"description"`
+	Visibility    string            `json:"visibility" db:"visibility"`
+	EntryCount    int               `json:"entry_count"`
+	Entries       []*StoreListEntry `json:"entries,omitempty"` // Only returned for a single list
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// StoreListEntry is a store of a list with the owner's note on it
+type StoreListEntry struct {
+	StoreID   uuid.UUID `json:"store_id" db:"store_id"`
+	Position  int       `json:"position" db:"position"` // Entries are ordered by position, which may skip numbers
+	Note      string    `json:"note" db:"note"`
+	Store     *Store    `json:"store,omitempty"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// IsValidListVisibility reports whether visibility is one of the ListVisibility* values
+func IsValidListVisibility(visibility string) bool {
+	switch visibility {
+	case ListVisibilityPrivate, ListVisibilityEditors, ListVisibilityViewers:
+		return true
+	}
+	return false
+}
+
+// CanSeeEditorLists reports whether users with the role may see lists for
+// editors. Viewer accounts log in too, but only see lists for viewers.
+func CanSeeEditorLists(role string) bool {
+	return role == string(RoleEditor) || role == string(RoleAdmin)
+}
+
+// CanView reports whether the user with the role may see the list. userID is
+// nil for anonymous readers, such as viewers logged in with the viewer password.
+func (l *StoreList) CanView(userID *uuid.UUID, role string) bool {
+	if userID != nil && *userID == l.OwnerID {
+		return true
+	}
+	switch l.Visibility {
+	case ListVisibilityViewers:
+		return true
+	case ListVisibilityEditors:
+		return userID != nil && CanSeeEditorLists(role)
+	default:
+		return false
+	}
+}
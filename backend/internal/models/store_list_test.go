@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStoreList_CanView(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+
+	tests := []struct {
+		visibility  string
+		owner       bool
+		other       bool
+		otherViewer bool
+		anonymous   bool
+	}{
+		{visibility: ListVisibilityPrivate, owner: true},
+		{visibility: ListVisibilityEditors, owner: true, other: true},
+		{visibility: ListVisibilityViewers, owner: true, other: true, otherViewer: true, anonymous: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.visibility, func(t *testing.T) {
+			list := &StoreList{OwnerID: owner, Visibility: tt.visibility}
+			assert.Equal(t, tt.owner, list.CanView(&owner, string(RoleEditor)), "owner")
+			assert.Equal(t, tt.owner, list.CanView(&owner, string(RoleViewer)), "owner with viewer role")
+			assert.Equal(t, tt.other, list.CanView(&other, string(RoleEditor)), "other editor")
+			assert.Equal(t, tt.other, list.CanView(&other, string(RoleAdmin)), "other admin")
+			assert.Equal(t, tt.otherViewer, list.CanView(&other, string(RoleViewer)), "other user with viewer role")
+			assert.Equal(t, tt.anonymous, list.CanView(nil, ""), "anonymous")
+		})
+	}
+}
+
+func TestIsValidListVisibility(t *testing.T) {
+	assert.True(t, IsValidListVisibility(ListVisibilityPrivate))
+	assert.True(t, IsValidListVisibility(ListVisibilityEditors))
+	assert.True(t, IsValidListVisibility(ListVisibilityViewers))
+	assert.False(t, IsValidListVisibility(""))
+	assert.False(t, IsValidListVisibility("public"))
+}
@@ -0,0 +1,90 @@
+package models
+
+import "github.com/google/uuid"
+
+// StoreDuplicateCandidate is a pair of stores that may be the same place.
+// Store is the older of the two and the natural target of a merge.
+type StoreDuplicateCandidate struct {
+	Store          *Store  `json:"store"`
+	Duplicate      *Store  `json:"duplicate"`
+	Distance       float64 `json:"distance"`        // メートル
+	NameSimilarity float64 `json:"name_similarity"` // 正規化した店名のトライグラム類似度（0〜1）
+}
+
+// StoreMergeResult is the outcome of merging a store into another
+type StoreMergeResult struct {
+	Store         *Store    `json:"store"`           // 統合後の店舗
+	MergedStoreID uuid.UUID `json:"merged_store_id"` // 統合されゴミ箱に移動した店舗
+	MovedReviews  int       `json:"moved_reviews"`   // 付け替えたレビュー
+	MergedReviews int       `json:"merged_reviews"`  // 同じユーザーのレビューが両方にあったため1件にまとめたレビュー
+	ListEntries   int       `json:"list_entries"`    // 統合後の店舗に付け替えたリストの項目
+	DryRun        bool      `json:"dry_run"`
+}
+
+// MergeStoreFields adds the labels, photos and SNS URLs of source to target
+// and fills the optional fields target leaves empty. Name, address, location
+// and business hours of target are kept.
+func MergeStoreFields(target, source *Store) {
+	target.Categories = appendMissing(target.Categories, source.Categories)
+	target.Tags = appendMissing(target.Tags, source.Tags)
+	target.Photos = appendMissing(target.Photos, source.Photos)
+	target.SnsUrls = appendMissing(target.SnsUrls, source.SnsUrls)
+	if target.ParkingInfo == "" {
+		target.ParkingInfo = source.ParkingInfo
+	}
+	if target.WebsiteURL == "" {
+		target.WebsiteURL = source.WebsiteURL
+	}
+	if target.GoogleMapURL == "" {
+		target.GoogleMapURL = source.GoogleMapURL
+	}
+}
+
+// MergeReviews combines two reviews of the same user into kept. The rating
+// and visit of the more recently updated review win, comments and food notes
+// of both are kept, and photos are combined.
+func MergeReviews(kept, other *Review) {
+	older, newer := other, kept
+	if other.UpdatedAt.After(kept.UpdatedAt) {
+		older, newer = kept, other
+	}
+
+	kept.Rating = newer.Rating
+	kept.VisitDate = newer.VisitDate
+	if kept.VisitDate == nil {
+		kept.VisitDate = older.VisitDate
+	}
+	kept.IsVisited = kept.IsVisited || other.IsVisited
+	kept.PaymentAmount = newer.PaymentAmount
+	if kept.PaymentAmount == nil {
+		kept.PaymentAmount = older.PaymentAmount
+	}
+	kept.Comment = joinNotes(older.Comment, newer.Comment)
+	kept.FoodNotes = joinNotes(older.FoodNotes, newer.FoodNotes)
+	kept.Photos = appendMissing(appendMissing(StringArray{}, older.Photos), newer.Photos)
+}
+
+// appendMissing appends the values not yet in array
+func appendMissing(array StringArray, values StringArray) StringArray {
+	if array == nil {
+		array = StringArray{}
+	}
+	for _, value := range values {
+		if !containsString(array, value) {
+			array = append(array, value)
+		}
+	}
+	return array
+}
+
+// joinNotes joins two optional texts with a blank line, dropping empty and identical ones
+func joinNotes(first, second *string) *string {
+	switch {
+	case first == nil || *first == "":
+		return second
+	case second == nil || *second == "" || *second == *first:
+		return first
+	}
+	joined := *first + "\n\n" + *second
+	return &joined
+}
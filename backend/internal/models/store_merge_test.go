@@ -0,0 +1,87 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeStoreFields(t *testing.T) {
+	target := &Store{
+		Name:       "スターバックス 渋谷店",
+		Categories: StringArray{"カフェ"},
+		Tags:       StringArray{"電源"},
+		Photos:     StringArray{"/uploads/a.jpg"},
+		SnsUrls:    StringArray{},
+		WebsiteURL: "https://www.starbucks.co.jp/",
+	}
+	source := &Store{
+		Name:         "Starbucks Shibuya",
+		Categories:   StringArray{"カフェ", "喫茶"},
+		Tags:         StringArray{"Wi-Fi", "電源"},
+		Photos:       StringArray{"/uploads/b.jpg", "/uploads/a.jpg"},
+		SnsUrls:      StringArray{"https://x.com/starbucks"},
+		ParkingInfo:  "なし",
+		WebsiteURL:   "https://example.com/",
+		GoogleMapURL: "https://maps.google.com/?cid=1",
+	}
+
+	MergeStoreFields(target, source)
+
+	assert.Equal(t, "スターバックス 渋谷店", target.Name)
+	assert.Equal(t, StringArray{"カフェ", "喫茶"}, target.Categories)
+	assert.Equal(t, StringArray{"電源", "Wi-Fi"}, target.Tags)
+	assert.Equal(t, StringArray{"/uploads/a.jpg", "/uploads/b.jpg"}, target.Photos)
+	assert.Equal(t, StringArray{"https://x.com/starbucks"}, target.SnsUrls)
+	assert.Equal(t, "なし", target.ParkingInfo)
+	assert.Equal(t, "https://www.starbucks.co.jp/", target.WebsiteURL)
+	assert.Equal(t, "https://maps.google.com/?cid=1", target.GoogleMapURL)
+}
+
+func TestMergeReviews(t *testing.T) {
+	oldComment, newComment := "静かで良い", "混んでいた"
+	notes := "ラテ"
+	amount := 600
+	visit := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	kept := &Review{
+		Rating:    3,
+		Comment:   &oldComment,
+		Photos:    StringArray{"/uploads/a.jpg"},
+		UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	other := &Review{
+		Rating:        5,
+		Comment:       &newComment,
+		FoodNotes:     &notes,
+		Photos:        StringArray{"/uploads/b.jpg", "/uploads/a.jpg"},
+		VisitDate:     &visit,
+		IsVisited:     true,
+		PaymentAmount: &amount,
+		UpdatedAt:     time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	MergeReviews(kept, other)
+
+	assert.Equal(t, 5, kept.Rating, "the newer rating wins")
+	assert.Equal(t, "静かで良い\n\n混んでいた", *kept.Comment)
+	assert.Equal(t, "ラテ", *kept.FoodNotes)
+	assert.Equal(t, StringArray{"/uploads/a.jpg", "/uploads/b.jpg"}, kept.Photos)
+	assert.Equal(t, &visit, kept.VisitDate)
+	assert.True(t, kept.IsVisited)
+	assert.Equal(t, &amount, kept.PaymentAmount)
+}
+
+func TestMergeReviews_KeptIsNewer(t *testing.T) {
+	comment := "同じ"
+	kept := &Review{Rating: 4, Comment: &comment, UpdatedAt: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+	other := &Review{Rating: 2, Comment: &comment, IsVisited: true, UpdatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	MergeReviews(kept, other)
+
+	assert.Equal(t, 4, kept.Rating)
+	assert.Equal(t, "同じ", *kept.Comment, "identical comments are not repeated")
+	assert.True(t, kept.IsVisited)
+	assert.Equal(t, StringArray{}, kept.Photos)
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Store revision actions
+const (
+	StoreRevisionActionCreate   = "create"
+	StoreRevisionActionUpdate   = "update"
+	StoreRevisionActionDelete   = "delete"
+	StoreRevisionActionRestore  = "restore"
+	StoreRevisionActionRollback = "rollback"
+)
+
+// StoreRevision is one entry of a store's change history
+type StoreRevision struct {
+	ID               uuid.UUID    `json:"id" db:"id"`
+	StoreID          uuid.UUID    `json:"store_id" db:"store_id"`
+	UserID           *uuid.UUID   `json:"user_id" db:"user_id"` // 変更したユーザー（削除済みユーザーの場合はnull）
+	Username         string       `json:"username,omitempty"`
+	Action           string       `json:"action" db:"action"`
+	Changes          StoreChanges `json:"changes" db:"changes"`
+	Snapshot         *Store       `json:"snapshot,omitempty" db:"snapshot"` // 変更後の店舗の状態
+	SourceRevisionID *uuid.UUID   `json:"source_revision_id,omitempty" db:"source_revision_id"`
+	CreatedAt        time.Time    `json:"created_at" db:"created_at"`
+}
+
+// FieldChange holds the JSON values of a field before and after a change
+type FieldChange struct {
+	Old json.RawMessage `json:"old"`
+	New json.RawMessage `json:"new"`
+}
+
+// StoreChanges maps JSON field names of Store to their changes
+type StoreChanges map[string]FieldChange
+
+// Value implements driver.Valuer for StoreChanges
+func (c StoreChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner for StoreChanges
+func (c *StoreChanges) Scan(value interface{}) error {
+	if value == nil {
+		*c = StoreChanges{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(bytes, c)
+}
+
+// untrackedStoreFields are not recorded in store revisions because they
+// never change, are maintained by the database or come from reviews
+var untrackedStoreFields = map[string]bool{
+	"id":             true,
+	"created_by":     true,
+	"created_at":     true,
+	"updated_at":     true,
+	"review_summary": true,
+}
+
+var jsonNull = json.RawMessage("null")
+
+// DiffStores returns the fields that differ between two states of a store.
+// A nil before or after is treated as a store with every field unset.
+// Null, empty strings and empty arrays are considered equal.
+func DiffStores(before, after *Store) (StoreChanges, error) {
+	beforeFields, err := storeFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := storeFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := StoreChanges{}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			beforeFields[name] = jsonNull
+		}
+	}
+	for name, oldValue := range beforeFields {
+		if untrackedStoreFields[name] {
+			continue
+		}
+		newValue, ok := afterFields[name]
+		if !ok {
+			newValue = jsonNull
+		}
+		if equalJSONValues(oldValue, newValue) {
+			continue
+		}
+		changes[name] = FieldChange{Old: oldValue, New: newValue}
+	}
+
+	return changes, nil
+}
+
+func storeFields(store *Store) (map[string]json.RawMessage, error) {
+	fields := map[string]json.RawMessage{}
+	if store == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(store)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func equalJSONValues(a, b json.RawMessage) bool {
+	if isEmptyJSONValue(a) && isEmptyJSONValue(b) {
+		return true
+	}
+	return bytes.Equal(a, b)
+}
+
+func isEmptyJSONValue(v json.RawMessage) bool {
+	return bytes.Equal(v, jsonNull) || bytes.Equal(v, []byte(`""`)) || bytes.Equal(v, []byte("[]"))
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffStores(t *testing.T) {
+	storeID := uuid.New()
+	base := func() *Store {
+		return &Store{
+			ID:            storeID,
+			Name:          "テスト店",
+			Address:       "東京都",
+			Latitude:      35.6,
+			Longitude:     139.7,
+			Categories:    StringArray{"カフェ"},
+			BusinessHours: GetDefaultBusinessHours(),
+			Tags:          StringArray{},
+			CreatedBy:     uuid.New(),
+			CreatedAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			UpdatedAt:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+
+	t.Run("changed fields only", func(t *testing.T) {
+		before := base()
+		after := base()
+		after.Name = "新しい店名"
+		after.Tags = StringArray{"人気"}
+		after.UpdatedAt = time.Now()
+
+		changes, err := DiffStores(before, after)
+		assert.NoError(t, err)
+		assert.Len(t, changes, 2)
+		assert.JSONEq(t, `"テスト店"`, string(changes["name"].Old))
+		assert.JSONEq(t, `"新しい店名"`, string(changes["name"].New))
+		assert.JSONEq(t, `[]`, string(changes["tags"].Old))
+		assert.JSONEq(t, `["人気"]`, string(changes["tags"].New))
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		after := base()
+		after.Tags = nil
+
+		changes, err := DiffStores(base(), after)
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		changes, err := DiffStores(nil, base())
+		assert.NoError(t, err)
+		assert.Contains(t, changes, "name")
+		assert.Contains(t, changes, "business_hours")
+		assert.NotContains(t, changes, "id")
+		assert.NotContains(t, changes, "created_by")
+		assert.NotContains(t, changes, "tags")
+		assert.NotContains(t, changes, "website_url")
+		assert.Equal(t, json.RawMessage("null"), changes["name"].Old)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		before := base()
+		after := base()
+		deletedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+		after.DeletedAt = &deletedAt
+
+		changes, err := DiffStores(before, after)
+		assert.NoError(t, err)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, json.RawMessage("null"), changes["deleted_at"].Old)
+		assert.JSONEq(t, `"2024-02-01T00:00:00Z"`, string(changes["deleted_at"].New))
+	})
+}
+
+func TestStoreChanges_Scan(t *testing.T) {
+	var changes StoreChanges
+	err := changes.Scan([]byte(`{"name":{"old":"a","new":"b"}}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `"b"`, string(changes["name"].New))
+
+	err = changes.Scan(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	err = changes.Scan("not bytes")
+	assert.Error(t, err)
+}
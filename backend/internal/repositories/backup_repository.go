@@ -0,0 +1,445 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sukimise/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// restoredUserPassword is stored for users created by a restore.
+// It is not a valid bcrypt hash, so such users cannot log in until
+// their password is set again via ADMIN_USERS / EDITOR_USERS.
+const restoredUserPassword = "!"
+
+type BackupRepository struct {
+	db *sql.DB
+}
+
+func NewBackupRepository(db *sql.DB) *BackupRepository {
+	return &BackupRepository{db: db}
+}
+
+// Export reads every backed up table from a single consistent snapshot and
+// passes each row to emit, in dependency order
+func (r *BackupRepository) Export(emit func(recordType string, data interface{}) error) error {
+	tx, err := r.db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := exportUsers(tx, emit); err != nil {
+		return fmt.Errorf("failed to export users: %w", err)
+	}
+	if err := exportStores(tx, emit); err != nil {
+		return fmt.Errorf("failed to export stores: %w", err)
+	}
+	if err := exportReviews(tx, emit); err != nil {
+		return fmt.Errorf("failed to export reviews: %w", err)
+	}
+	if err := exportMenuItems(tx, emit); err != nil {
+		return fmt.Errorf("failed to export menu items: %w", err)
+	}
+	if err := exportCategoryCustomizations(tx, emit); err != nil {
+		return fmt.Errorf("failed to export category customizations: %w", err)
+	}
+	if err := exportStoreLists(tx, emit); err != nil {
+		return fmt.Errorf("failed to export store lists: %w", err)
+	}
+	if err := exportStoreListEntries(tx, emit); err != nil {
+		return fmt.Errorf("failed to export store list entries: %w", err)
+	}
+	if err := exportShareLinks(tx, emit); err != nil {
+		return fmt.Errorf("failed to export share links: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func exportUsers(tx *sql.Tx, emit func(string, interface{}) error) error {
+	rows, err := tx.Query(`SELECT id, username, email, role, created_at, updated_at FROM users ORDER BY created_at, id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Username, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return err
+		}
+		if err := emit(models.BackupTypeUser, &user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func exportStores(tx *sql.Tx, emit func(string, interface{}) error) error {
+	rows, err := tx.Query(`
+		SELECT id, name, address, latitude, longitude, categories, business_hours, business_hour_exceptions,
+			   parking_info, website_url, google_map_url, sns_urls,
+			   tags, photos, created_by, created_at, updated_at, deleted_at
+		FROM stores ORDER BY created_at, id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var store models.Store
+		err := rows.Scan(
+			&store.ID, &store.Name, &store.Address, &store.Latitude, &store.Longitude,
+			&store.Categories, &store.BusinessHours, &store.BusinessHourExceptions, &store.ParkingInfo,
+			&store.WebsiteURL, &store.GoogleMapURL, &store.SnsUrls, &store.Tags,
+			&store.Photos, &store.CreatedBy, &store.CreatedAt, &store.UpdatedAt, &store.DeletedAt,
+		)
+		if err != nil {
+			return err
+		}
+		if err := emit(models.BackupTypeStore, &store); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func exportReviews(tx *sql.Tx, emit func(string, interface{}) error) error {
+	rows, err := tx.Query(`
+		SELECT id, store_id, user_id, rating, comment, photos, visit_date, is_visited, payment_amount, food_notes, created_at, updated_at
+		FROM reviews ORDER BY created_at, id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var review models.Review
+		err := rows.Scan(
+			&review.ID, &review.StoreID, &review.UserID, &review.Rating, &review.Comment,
+			&review.Photos, &review.VisitDate, &review.IsVisited, &review.PaymentAmount, &review.FoodNotes, &review.CreatedAt, &review.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		if err := emit(models.BackupTypeReview, &review); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func exportMenuItems(tx *sql.Tx, emit func(string, interface{}) error) error {
+	rows, err := tx.Query(`SELECT id, review_id, name, COALESCE(comment, '') FROM menu_items ORDER BY review_id, id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var menuItem models.MenuItem
+		if err := rows.Scan(&menuItem.ID, &menuItem.ReviewID, &menuItem.Name, &menuItem.Comment); err != nil {
+			return err
+		}
+		if err := emit(models.BackupTypeMenuItem, &menuItem); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func exportCategoryCustomizations(tx *sql.Tx, emit func(string, interface{}) error) error {
+	rows, err := tx.Query(`
+		SELECT id, category_name, icon, color, created_at, updated_at
+		FROM category_customizations ORDER BY category_name
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var customization models.CategoryCustomization
+		err := rows.Scan(
+			&customization.ID, &customization.CategoryName, &customization.Icon,
+			&customization.Color, &customization.CreatedAt, &customization.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		if err := emit(models.BackupTypeCategoryCustomization, &customization); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func exportStoreLists(tx *sql.Tx, emit func(string, interface{}) error) error {
+	rows, err := tx.Query(`
+		SELECT id, owner_id, title, description, visibility, created_at, updated_at
+		FROM store_lists ORDER BY created_at, id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var list models.StoreList
+		err := rows.Scan(&list.ID, &list.OwnerID, &list.Title, &list.Description, &list.Visibility, &list.CreatedAt, &list.UpdatedAt)
+		if err != nil {
+			return err
+		}
+		if err := emit(models.BackupTypeStoreList, &list); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func exportStoreListEntries(tx *sql.Tx, emit func(string, interface{}) error) error {
+	rows, err := tx.Query(`SELECT list_id, store_id, position, note, created_at FROM store_list_entries ORDER BY list_id, position, store_id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry models.BackupStoreListEntry
+		if err := rows.Scan(&entry.ListID, &entry.StoreID, &entry.Position, &entry.Note, &entry.CreatedAt); err != nil {
+			return err
+		}
+		if err := emit(models.BackupTypeStoreListEntry, &entry); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func exportShareLinks(tx *sql.Tx, emit func(string, interface{}) error) error {
+	rows, err := tx.Query(`
+		SELECT id, scope, store_id, list_id, filter_query, created_by, expires_at,
+			   revoked_at, access_count, last_accessed_at, created_at
+		FROM viewer_share_links ORDER BY created_at, id
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var link models.ShareLink
+		err := rows.Scan(
+			&link.ID, &link.Scope, &link.StoreID, &link.ListID, &link.FilterQuery, &link.CreatedBy, &link.ExpiresAt,
+			&link.RevokedAt, &link.AccessCount, &link.LastAccessedAt, &link.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		if err := emit(models.BackupTypeShareLink, &link); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Restore upserts all records of a backup by UUID inside one transaction.
+// Users that already exist under the same username keep their current ID,
+// and references from stores, reviews, store lists and share links are
+// remapped to it.
+func (r *BackupRepository) Restore(data *models.BackupData) (*models.RestoreResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result := &models.RestoreResult{}
+
+	userIDs, err := restoreUsers(tx, data.Users)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore users: %w", err)
+	}
+	result.Users = len(data.Users)
+
+	mapUserID := func(id uuid.UUID) uuid.UUID {
+		if mapped, ok := userIDs[id]; ok {
+			return mapped
+		}
+		return id
+	}
+
+	for _, store := range data.Stores {
+		_, err := tx.Exec(`
+			INSERT INTO stores (id, name, address, latitude, longitude, categories, business_hours, business_hour_exceptions,
+							  parking_info, website_url, google_map_url, sns_urls,
+							  tags, photos, created_by, created_at, updated_at, deleted_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+			ON CONFLICT (id) DO UPDATE SET
+				name = EXCLUDED.name, address = EXCLUDED.address,
+				latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude,
+				categories = EXCLUDED.categories, business_hours = EXCLUDED.business_hours,
+				business_hour_exceptions = EXCLUDED.business_hour_exceptions,
+				parking_info = EXCLUDED.parking_info, website_url = EXCLUDED.website_url,
+				google_map_url = EXCLUDED.google_map_url, sns_urls = EXCLUDED.sns_urls,
+				tags = EXCLUDED.tags, photos = EXCLUDED.photos, created_by = EXCLUDED.created_by,
+				created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at,
+				deleted_at = EXCLUDED.deleted_at
+		`,
+			store.ID, store.Name, store.Address, store.Latitude, store.Longitude,
+			store.Categories, store.BusinessHours, store.BusinessHourExceptions, store.ParkingInfo,
+			store.WebsiteURL, store.GoogleMapURL, store.SnsUrls, store.Tags,
+			store.Photos, mapUserID(store.CreatedBy), store.CreatedAt, store.UpdatedAt, store.DeletedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore store %s: %w", store.ID, err)
+		}
+	}
+	result.Stores = len(data.Stores)
+
+	for _, review := range data.Reviews {
+		_, err := tx.Exec(`
+			INSERT INTO reviews (id, store_id, user_id, rating, comment, photos, visit_date, is_visited,
+								 payment_amount, food_notes, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			ON CONFLICT (id) DO UPDATE SET
+				store_id = EXCLUDED.store_id, user_id = EXCLUDED.user_id, rating = EXCLUDED.rating,
+				comment = EXCLUDED.comment, photos = EXCLUDED.photos, visit_date = EXCLUDED.visit_date,
+				is_visited = EXCLUDED.is_visited, payment_amount = EXCLUDED.payment_amount,
+				food_notes = EXCLUDED.food_notes, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at
+		`,
+			review.ID, review.StoreID, mapUserID(review.UserID), review.Rating, review.Comment,
+			review.Photos, review.VisitDate, review.IsVisited, review.PaymentAmount, review.FoodNotes,
+			review.CreatedAt, review.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore review %s: %w", review.ID, err)
+		}
+	}
+	result.Reviews = len(data.Reviews)
+
+	for _, menuItem := range data.MenuItems {
+		_, err := tx.Exec(`
+			INSERT INTO menu_items (id, review_id, name, comment)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (id) DO UPDATE SET
+				review_id = EXCLUDED.review_id, name = EXCLUDED.name, comment = EXCLUDED.comment
+		`, menuItem.ID, menuItem.ReviewID, menuItem.Name, menuItem.Comment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore menu item %s: %w", menuItem.ID, err)
+		}
+	}
+	result.MenuItems = len(data.MenuItems)
+
+	// Category customizations are keyed by category name, which is unique
+	// and also seeded by migrations with different IDs
+	for _, customization := range data.CategoryCustomizations {
+		_, err := tx.Exec(`
+			INSERT INTO category_customizations (id, category_name, icon, color, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (category_name) DO UPDATE SET
+				icon = EXCLUDED.icon, color = EXCLUDED.color, updated_at = EXCLUDED.updated_at
+		`,
+			customization.ID, customization.CategoryName, customization.Icon,
+			customization.Color, customization.CreatedAt, customization.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore category customization %s: %w", customization.CategoryName, err)
+		}
+	}
+	result.CategoryCustomizations = len(data.CategoryCustomizations)
+
+	for _, list := range data.StoreLists {
+		_, err := tx.Exec(`
+			INSERT INTO store_lists (id, owner_id, title, description, visibility, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (id) DO UPDATE SET
+				owner_id = EXCLUDED.owner_id, title = EXCLUDED.title, description = EXCLUDED.description,
+				visibility = EXCLUDED.visibility, created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at
+		`, list.ID, mapUserID(list.OwnerID), list.Title, list.Description, list.Visibility, list.CreatedAt, list.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore store list %s: %w", list.ID, err)
+		}
+	}
+	result.StoreLists = len(data.StoreLists)
+
+	for _, entry := range data.StoreListEntries {
+		_, err := tx.Exec(`
+			INSERT INTO store_list_entries (list_id, store_id, position, note, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (list_id, store_id) DO UPDATE SET
+				position = EXCLUDED.position, note = EXCLUDED.note, created_at = EXCLUDED.created_at
+		`, entry.ListID, entry.StoreID, entry.Position, entry.Note, entry.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore store list entry %s/%s: %w", entry.ListID, entry.StoreID, err)
+		}
+	}
+	result.StoreListEntries = len(data.StoreListEntries)
+
+	// Share links keep their IDs, so tokens signed with the same server
+	// secret stay valid after a restore. A link revoked or opened since the
+	// backup stays revoked and keeps its access count.
+	for _, link := range data.ShareLinks {
+		_, err := tx.Exec(`
+			INSERT INTO viewer_share_links (id, scope, store_id, list_id, filter_query, created_by, expires_at,
+											revoked_at, access_count, last_accessed_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (id) DO UPDATE SET
+				scope = EXCLUDED.scope, store_id = EXCLUDED.store_id, list_id = EXCLUDED.list_id,
+				filter_query = EXCLUDED.filter_query, created_by = EXCLUDED.created_by,
+				expires_at = EXCLUDED.expires_at,
+				revoked_at = COALESCE(viewer_share_links.revoked_at, EXCLUDED.revoked_at),
+				access_count = GREATEST(viewer_share_links.access_count, EXCLUDED.access_count),
+				last_accessed_at = GREATEST(viewer_share_links.last_accessed_at, EXCLUDED.last_accessed_at),
+				created_at = EXCLUDED.created_at
+		`,
+			link.ID, link.Scope, link.StoreID, link.ListID, link.FilterQuery, mapUserID(link.CreatedBy), link.ExpiresAt,
+			link.RevokedAt, link.AccessCount, link.LastAccessedAt, link.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore share link %s: %w", link.ID, err)
+		}
+	}
+	result.ShareLinks = len(data.ShareLinks)
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// restoreUsers inserts missing users and returns a mapping from backed up user IDs
+// to the IDs of existing users with the same username, where they differ
+func restoreUsers(tx *sql.Tx, users []*models.User) (map[uuid.UUID]uuid.UUID, error) {
+	userIDs := make(map[uuid.UUID]uuid.UUID)
+
+	for _, user := range users {
+		_, err := tx.Exec(`
+			INSERT INTO users (id, username, email, password, role, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT DO NOTHING
+		`, user.ID, user.Username, user.Email, restoredUserPassword, user.Role, user.CreatedAt, user.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		var existingID uuid.UUID
+		err = tx.QueryRow(`SELECT id FROM users WHERE username = $1`, user.Username).Scan(&existingID)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user %s conflicts with an existing user", user.Username)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if existingID != user.ID {
+			userIDs[user.ID] = existingID
+		}
+	}
+
+	return userIDs, nil
+}
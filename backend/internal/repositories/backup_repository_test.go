@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"database/sql"
+	"os"
+	"sukimise/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBackupRepository_Restore_RevokedShareLink restores a backup over share
+// links revoked and opened since it was taken. It needs a migrated database in
+// TEST_DATABASE_URL and removes its records afterwards:
+//
+//	TEST_DATABASE_URL=postgres://... go test -run Restore ./internal/repositories
+func TestBackupRepository_Restore_RevokedShareLink(t *testing.T) {
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	now := time.Now().UTC().Truncate(time.Second)
+	user := &models.User{
+		ID:        uuid.New(),
+		Role:      string(models.RoleEditor),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	user.Username = "restore-" + user.ID.String()
+	user.Email = user.Username + "@example.com"
+	t.Cleanup(func() {
+		// Deleting the user cascades to its share links
+		if _, err := db.Exec(`DELETE FROM users WHERE id = $1`, user.ID); err != nil {
+			t.Error(err)
+		}
+	})
+
+	filterQuery := "categories=カフェ"
+	revokedAt := now.Add(-time.Hour)
+	newShareLink := func(revokedAt *time.Time, accessCount int) *models.ShareLink {
+		return &models.ShareLink{
+			ID:          uuid.New(),
+			Scope:       models.ShareScopeFilter,
+			FilterQuery: &filterQuery,
+			CreatedBy:   user.ID,
+			ExpiresAt:   now.Add(7 * 24 * time.Hour),
+			RevokedAt:   revokedAt,
+			AccessCount: accessCount,
+			CreatedAt:   now,
+		}
+	}
+	// Revoked and opened after the backup
+	revokedSince := newShareLink(nil, 2)
+	// Revoked in the backup only
+	revokedBefore := newShareLink(&revokedAt, 1)
+
+	repo := NewBackupRepository(db)
+	data := &models.BackupData{Users: []*models.User{user}, ShareLinks: []*models.ShareLink{revokedSince, revokedBefore}}
+	_, err = repo.Restore(data)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`UPDATE viewer_share_links SET revoked_at = NOW(), access_count = 5 WHERE id = $1`, revokedSince.ID)
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE viewer_share_links SET revoked_at = NULL WHERE id = $1`, revokedBefore.ID)
+	require.NoError(t, err)
+
+	result, err := repo.Restore(data)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.ShareLinks)
+
+	var restoredRevokedAt *time.Time
+	var accessCount int
+	err = db.QueryRow(`SELECT revoked_at, access_count FROM viewer_share_links WHERE id = $1`, revokedSince.ID).
+		Scan(&restoredRevokedAt, &accessCount)
+	require.NoError(t, err)
+	assert.NotNil(t, restoredRevokedAt, "a revocation since the backup is kept")
+	assert.Equal(t, 5, accessCount, "the larger access count is kept")
+
+	err = db.QueryRow(`SELECT revoked_at, access_count FROM viewer_share_links WHERE id = $1`, revokedBefore.ID).
+		Scan(&restoredRevokedAt, &accessCount)
+	require.NoError(t, err)
+	require.NotNil(t, restoredRevokedAt, "a revocation in the backup is restored")
+	assert.True(t, revokedAt.Equal(*restoredRevokedAt))
+	assert.Equal(t, 1, accessCount)
+}
@@ -1,23 +1,40 @@
 package repositories
 
 import (
+	"errors"
 	"sukimise/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 //go:generate mockgen -source=interfaces.go -destination=mocks/mock_repositories.go
 
+// ErrVersionConflict is returned by updates when the row was modified
+// after the caller read it (its updated_at no longer matches)
+var ErrVersionConflict = errors.New("version conflict")
+
 type StoreRepositoryInterface interface {
 	Create(store *models.Store) error
 	GetByID(id uuid.UUID) (*models.Store, error)
 	GetAll(filter *StoreFilter) ([]*models.Store, error)
+	GetPage(filter *StoreFilter) ([]*models.Store, *StoreCursor, error)
 	GetCount(filter *StoreFilter) (int, error)
-	Update(store *models.Store) error
-	Delete(id uuid.UUID) error
+	GetClusters(filter *StoreFilter, cellSize float64) ([]*models.StoreCluster, error)
+	Update(store *models.Store, actorID uuid.UUID) error
+	Rollback(store *models.Store, revisionID, actorID uuid.UUID) error
+	Delete(id, actorID uuid.UUID) error
+	BulkApply(ids []uuid.UUID, operation models.StoreBulkOperation, actorID uuid.UUID, check func(before, after *models.Store) *models.StoreBulkResult) ([]*models.StoreBulkResult, error)
+	GetDeleted(limit, offset int) ([]*models.Store, error)
+	GetDeletedCount() (int, error)
+	Restore(id, actorID uuid.UUID) error
+	PurgeDeleted(before time.Time) (int64, error)
 	GetAllCategories() ([]string, error)
 	GetAllTags() ([]string, error)
-	FindDuplicateByLocationAndName(name string, latitude, longitude float64) (*models.Store, error)
+	FindDuplicateByLocationAndName(name string, latitude, longitude float64, policy models.DuplicatePolicy) (*models.Store, error)
+	GetRevisions(storeID uuid.UUID, limit, offset int) ([]*models.StoreRevision, error)
+	GetRevisionCount(storeID uuid.UUID) (int, error)
+	GetRevision(id uuid.UUID) (*models.StoreRevision, error)
 }
 
 type UserRepositoryInterface interface {
@@ -55,4 +72,25 @@ type CategoryCustomizationRepositoryInterface interface {
 	GetAll() ([]*models.CategoryCustomization, error)
 	Update(categoryCustomization *models.CategoryCustomization) error
 	Delete(categoryName string) error
-}
\ No newline at end of file
+}
+
+type StoreMergeRepositoryInterface interface {
+	FindCandidates(radius, minSimilarity, sameSpotDistance float64, limit int) ([]*models.StoreDuplicateCandidate, error)
+	BeginMerge(targetID, sourceID uuid.UUID) (StoreMergeTxInterface, error)
+}
+
+// StoreMergeTxInterface is a merge of two stores in progress. Nothing is
+// saved until Commit; Rollback discards the merge, e.g. after a dry run.
+type StoreMergeTxInterface interface {
+	Stores() (target, source *models.Store)
+	UpdateTarget(merged *models.Store, actorID uuid.UUID) error
+	GetReviewConflicts() ([][2]*models.Review, error)
+	UpdateReview(review *models.Review) error
+	MoveMenuItems(fromReviewID, toReviewID uuid.UUID) error
+	DeleteReview(id uuid.UUID) error
+	MoveReviews() (int, error)
+	MergeListEntries() (int, error)
+	TrashSource(actorID uuid.UUID) error
+	Commit() error
+	Rollback() error
+}
@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"sukimise/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// labelColumns maps label kinds to their JSONB array column in stores
+var labelColumns = map[string]string{
+	models.LabelKindTag:      "tags",
+	models.LabelKindCategory: "categories",
+}
+
+type LabelRepository struct {
+	db *sql.DB
+}
+
+func NewLabelRepository(db *sql.DB) *LabelRepository {
+	return &LabelRepository{db: db}
+}
+
+// Merge replaces the labels in from with to on every store, including stores
+// in the trash, in a single transaction. Each changed store gets a revision.
+// Merging categories also moves the category customization to the new name.
+// With dryRun the transaction is rolled back and only the result is returned.
+func (r *LabelRepository) Merge(kind string, from []string, to string, actorID uuid.UUID, dryRun bool) (*models.LabelMergeResult, error) {
+	column, ok := labelColumns[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown label kind: %s", kind)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	stores, err := lockStoresWithLabels(tx, column, from)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.LabelMergeResult{
+		Kind:           kind,
+		From:           from,
+		To:             to,
+		AffectedStores: len(stores),
+		DryRun:         dryRun,
+	}
+
+	for _, before := range stores {
+		after := *before
+		labels := &after.Tags
+		if kind == models.LabelKindCategory {
+			labels = &after.Categories
+		}
+		*labels, _ = models.ReplaceLabels(*labels, from, to)
+
+		query := fmt.Sprintf(`UPDATE stores SET %s = $2, updated_at = NOW() WHERE id = $1 RETURNING updated_at`, column)
+		if err := tx.QueryRow(query, after.ID, *labels).Scan(&after.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to update store %s: %w", after.ID, err)
+		}
+		if err := insertStoreRevision(tx, actorID, models.StoreRevisionActionUpdate, before, &after, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if kind == models.LabelKindCategory {
+		result.CustomizationMoved, result.CustomizationsRemoved, err = mergeCategoryCustomizations(tx, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge category customizations: %w", err)
+		}
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// lockStoresWithLabels returns every store having any of labels in column, locked for update
+func lockStoresWithLabels(tx *sql.Tx, column string, labels []string) ([]*models.Store, error) {
+	placeholders := make([]string, len(labels))
+	args := make([]interface{}, len(labels))
+	for i, label := range labels {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = label
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, name, address, latitude, longitude, categories, business_hours, business_hour_exceptions,
+			   parking_info, website_url, google_map_url, sns_urls,
+			   tags, photos, created_by, created_at, updated_at, deleted_at
+		FROM stores
+		WHERE %s ?| ARRAY[%s]::text[]
+		ORDER BY id
+		FOR UPDATE
+	`, column, strings.Join(placeholders, ","))
+
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stores []*models.Store
+	for rows.Next() {
+		var store models.Store
+		err := rows.Scan(
+			&store.ID, &store.Name, &store.Address, &store.Latitude, &store.Longitude,
+			&store.Categories, &store.BusinessHours, &store.BusinessHourExceptions, &store.ParkingInfo,
+			&store.WebsiteURL, &store.GoogleMapURL, &store.SnsUrls, &store.Tags,
+			&store.Photos, &store.CreatedBy, &store.CreatedAt, &store.UpdatedAt, &store.DeletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, &store)
+	}
+
+	return stores, rows.Err()
+}
+
+// mergeCategoryCustomizations gives category to the icon and color of the first
+// category in from that has one, unless category already has its own.
+// The customizations of the other categories in from are removed.
+func mergeCategoryCustomizations(tx *sql.Tx, from []string, category string) (bool, []string, error) {
+	var exists bool
+	err := tx.QueryRow(`SELECT EXISTS(SELECT 1 FROM category_customizations WHERE category_name = $1)`, category).Scan(&exists)
+	if err != nil {
+		return false, nil, err
+	}
+
+	moved := false
+	var removed []string
+	for _, name := range from {
+		if !exists {
+			result, err := tx.Exec(`UPDATE category_customizations SET category_name = $2, updated_at = NOW() WHERE category_name = $1`, name, category)
+			if err != nil {
+				return false, nil, err
+			}
+			if affected, err := result.RowsAffected(); err != nil {
+				return false, nil, err
+			} else if affected > 0 {
+				exists = true
+				moved = true
+			}
+			continue
+		}
+
+		result, err := tx.Exec(`DELETE FROM category_customizations WHERE category_name = $1`, name)
+		if err != nil {
+			return false, nil, err
+		}
+		if affected, err := result.RowsAffected(); err != nil {
+			return false, nil, err
+		} else if affected > 0 {
+			removed = append(removed, name)
+		}
+	}
+
+	return moved, removed, nil
+}
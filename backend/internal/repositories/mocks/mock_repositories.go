@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 	models "sukimise/internal/models"
 	repositories "sukimise/internal/repositories"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -42,6 +43,21 @@ func (m *MockStoreRepositoryInterface) EXPECT() *MockStoreRepositoryInterfaceMoc
 	return m.recorder
 }
 
+// BulkApply mocks base method.
+func (m *MockStoreRepositoryInterface) BulkApply(ids []uuid.UUID, operation models.StoreBulkOperation, actorID uuid.UUID, check func(*models.Store, *models.Store) *models.StoreBulkResult) ([]*models.StoreBulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkApply", ids, operation, actorID, check)
+	ret0, _ := ret[0].([]*models.StoreBulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkApply indicates an expected call of BulkApply.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) BulkApply(ids, operation, actorID, check any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkApply", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).BulkApply), ids, operation, actorID, check)
+}
+
 // Create mocks base method.
 func (m *MockStoreRepositoryInterface) Create(store *models.Store) error {
 	m.ctrl.T.Helper()
@@ -57,17 +73,32 @@ func (mr *MockStoreRepositoryInterfaceMockRecorder) Create(store any) *gomock.Ca
 }
 
 // Delete mocks base method.
-func (m *MockStoreRepositoryInterface) Delete(id uuid.UUID) error {
+func (m *MockStoreRepositoryInterface) Delete(id, actorID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", id)
+	ret := m.ctrl.Call(m, "Delete", id, actorID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockStoreRepositoryInterfaceMockRecorder) Delete(id any) *gomock.Call {
+func (mr *MockStoreRepositoryInterfaceMockRecorder) Delete(id, actorID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).Delete), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).Delete), id, actorID)
+}
+
+// FindDuplicateByLocationAndName mocks base method.
+func (m *MockStoreRepositoryInterface) FindDuplicateByLocationAndName(name string, latitude, longitude float64, policy models.DuplicatePolicy) (*models.Store, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDuplicateByLocationAndName", name, latitude, longitude, policy)
+	ret0, _ := ret[0].(*models.Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDuplicateByLocationAndName indicates an expected call of FindDuplicateByLocationAndName.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) FindDuplicateByLocationAndName(name, latitude, longitude, policy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDuplicateByLocationAndName", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).FindDuplicateByLocationAndName), name, latitude, longitude, policy)
 }
 
 // GetAll mocks base method.
@@ -130,18 +161,182 @@ func (mr *MockStoreRepositoryInterfaceMockRecorder) GetByID(id any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).GetByID), id)
 }
 
+// GetClusters mocks base method.
+func (m *MockStoreRepositoryInterface) GetClusters(filter *repositories.StoreFilter, cellSize float64) ([]*models.StoreCluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusters", filter, cellSize)
+	ret0, _ := ret[0].([]*models.StoreCluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusters indicates an expected call of GetClusters.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) GetClusters(filter, cellSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusters", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).GetClusters), filter, cellSize)
+}
+
+// GetCount mocks base method.
+func (m *MockStoreRepositoryInterface) GetCount(filter *repositories.StoreFilter) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCount", filter)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCount indicates an expected call of GetCount.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) GetCount(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCount", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).GetCount), filter)
+}
+
+// GetDeleted mocks base method.
+func (m *MockStoreRepositoryInterface) GetDeleted(limit, offset int) ([]*models.Store, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleted", limit, offset)
+	ret0, _ := ret[0].([]*models.Store)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleted indicates an expected call of GetDeleted.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) GetDeleted(limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleted", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).GetDeleted), limit, offset)
+}
+
+// GetDeletedCount mocks base method.
+func (m *MockStoreRepositoryInterface) GetDeletedCount() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedCount")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedCount indicates an expected call of GetDeletedCount.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) GetDeletedCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedCount", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).GetDeletedCount))
+}
+
+// GetPage mocks base method.
+func (m *MockStoreRepositoryInterface) GetPage(filter *repositories.StoreFilter) ([]*models.Store, *repositories.StoreCursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPage", filter)
+	ret0, _ := ret[0].([]*models.Store)
+	ret1, _ := ret[1].(*repositories.StoreCursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPage indicates an expected call of GetPage.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) GetPage(filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPage", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).GetPage), filter)
+}
+
+// GetRevision mocks base method.
+func (m *MockStoreRepositoryInterface) GetRevision(id uuid.UUID) (*models.StoreRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevision", id)
+	ret0, _ := ret[0].(*models.StoreRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevision indicates an expected call of GetRevision.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) GetRevision(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevision", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).GetRevision), id)
+}
+
+// GetRevisionCount mocks base method.
+func (m *MockStoreRepositoryInterface) GetRevisionCount(storeID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisionCount", storeID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisionCount indicates an expected call of GetRevisionCount.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) GetRevisionCount(storeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisionCount", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).GetRevisionCount), storeID)
+}
+
+// GetRevisions mocks base method.
+func (m *MockStoreRepositoryInterface) GetRevisions(storeID uuid.UUID, limit, offset int) ([]*models.StoreRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", storeID, limit, offset)
+	ret0, _ := ret[0].([]*models.StoreRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) GetRevisions(storeID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).GetRevisions), storeID, limit, offset)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockStoreRepositoryInterface) PurgeDeleted(before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) PurgeDeleted(before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).PurgeDeleted), before)
+}
+
+// Restore mocks base method.
+func (m *MockStoreRepositoryInterface) Restore(id, actorID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", id, actorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) Restore(id, actorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).Restore), id, actorID)
+}
+
+// Rollback mocks base method.
+func (m *MockStoreRepositoryInterface) Rollback(store *models.Store, revisionID, actorID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback", store, revisionID, actorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rollback indicates an expected call of Rollback.
+func (mr *MockStoreRepositoryInterfaceMockRecorder) Rollback(store, revisionID, actorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).Rollback), store, revisionID, actorID)
+}
+
 // Update mocks base method.
-func (m *MockStoreRepositoryInterface) Update(store *models.Store) error {
+func (m *MockStoreRepositoryInterface) Update(store *models.Store, actorID uuid.UUID) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", store)
+	ret := m.ctrl.Call(m, "Update", store, actorID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockStoreRepositoryInterfaceMockRecorder) Update(store any) *gomock.Call {
+func (mr *MockStoreRepositoryInterfaceMockRecorder) Update(store, actorID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).Update), store)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockStoreRepositoryInterface)(nil).Update), store, actorID)
 }
 
 // MockUserRepositoryInterface is a mock of UserRepositoryInterface interface.
@@ -506,3 +701,335 @@ func (mr *MockViewerAuthRepositoryInterfaceMockRecorder) UpdateViewerSettings(se
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateViewerSettings", reflect.TypeOf((*MockViewerAuthRepositoryInterface)(nil).UpdateViewerSettings), settings)
 }
+
+// MockCategoryCustomizationRepositoryInterface is a mock of CategoryCustomizationRepositoryInterface interface.
+type MockCategoryCustomizationRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryCustomizationRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockCategoryCustomizationRepositoryInterfaceMockRecorder is the mock recorder for MockCategoryCustomizationRepositoryInterface.
+type MockCategoryCustomizationRepositoryInterfaceMockRecorder struct {
+	mock *MockCategoryCustomizationRepositoryInterface
+}
+
+// NewMockCategoryCustomizationRepositoryInterface creates a new mock instance.
+func NewMockCategoryCustomizationRepositoryInterface(ctrl *gomock.Controller) *MockCategoryCustomizationRepositoryInterface {
+	mock := &MockCategoryCustomizationRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockCategoryCustomizationRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryCustomizationRepositoryInterface) EXPECT() *MockCategoryCustomizationRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCategoryCustomizationRepositoryInterface) Create(categoryCustomization *models.CategoryCustomization) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", categoryCustomization)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCategoryCustomizationRepositoryInterfaceMockRecorder) Create(categoryCustomization any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCategoryCustomizationRepositoryInterface)(nil).Create), categoryCustomization)
+}
+
+// Delete mocks base method.
+func (m *MockCategoryCustomizationRepositoryInterface) Delete(categoryName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", categoryName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockCategoryCustomizationRepositoryInterfaceMockRecorder) Delete(categoryName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockCategoryCustomizationRepositoryInterface)(nil).Delete), categoryName)
+}
+
+// GetAll mocks base method.
+func (m *MockCategoryCustomizationRepositoryInterface) GetAll() ([]*models.CategoryCustomization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]*models.CategoryCustomization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCategoryCustomizationRepositoryInterfaceMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCategoryCustomizationRepositoryInterface)(nil).GetAll))
+}
+
+// GetByCategoryName mocks base method.
+func (m *MockCategoryCustomizationRepositoryInterface) GetByCategoryName(categoryName string) (*models.CategoryCustomization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCategoryName", categoryName)
+	ret0, _ := ret[0].(*models.CategoryCustomization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCategoryName indicates an expected call of GetByCategoryName.
+func (mr *MockCategoryCustomizationRepositoryInterfaceMockRecorder) GetByCategoryName(categoryName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCategoryName", reflect.TypeOf((*MockCategoryCustomizationRepositoryInterface)(nil).GetByCategoryName), categoryName)
+}
+
+// Update mocks base method.
+func (m *MockCategoryCustomizationRepositoryInterface) Update(categoryCustomization *models.CategoryCustomization) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", categoryCustomization)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockCategoryCustomizationRepositoryInterfaceMockRecorder) Update(categoryCustomization any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCategoryCustomizationRepositoryInterface)(nil).Update), categoryCustomization)
+}
+
+// MockStoreMergeRepositoryInterface is a mock of StoreMergeRepositoryInterface interface.
+type MockStoreMergeRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMergeRepositoryInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockStoreMergeRepositoryInterfaceMockRecorder is the mock recorder for MockStoreMergeRepositoryInterface.
+type MockStoreMergeRepositoryInterfaceMockRecorder struct {
+	mock *MockStoreMergeRepositoryInterface
+}
+
+// NewMockStoreMergeRepositoryInterface creates a new mock instance.
+func NewMockStoreMergeRepositoryInterface(ctrl *gomock.Controller) *MockStoreMergeRepositoryInterface {
+	mock := &MockStoreMergeRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockStoreMergeRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStoreMergeRepositoryInterface) EXPECT() *MockStoreMergeRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// BeginMerge mocks base method.
+func (m *MockStoreMergeRepositoryInterface) BeginMerge(targetID, sourceID uuid.UUID) (repositories.StoreMergeTxInterface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginMerge", targetID, sourceID)
+	ret0, _ := ret[0].(repositories.StoreMergeTxInterface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginMerge indicates an expected call of BeginMerge.
+func (mr *MockStoreMergeRepositoryInterfaceMockRecorder) BeginMerge(targetID, sourceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginMerge", reflect.TypeOf((*MockStoreMergeRepositoryInterface)(nil).BeginMerge), targetID, sourceID)
+}
+
+// FindCandidates mocks base method.
+func (m *MockStoreMergeRepositoryInterface) FindCandidates(radius, minSimilarity, sameSpotDistance float64, limit int) ([]*models.StoreDuplicateCandidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCandidates", radius, minSimilarity, sameSpotDistance, limit)
+	ret0, _ := ret[0].([]*models.StoreDuplicateCandidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCandidates indicates an expected call of FindCandidates.
+func (mr *MockStoreMergeRepositoryInterfaceMockRecorder) FindCandidates(radius, minSimilarity, sameSpotDistance, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCandidates", reflect.TypeOf((*MockStoreMergeRepositoryInterface)(nil).FindCandidates), radius, minSimilarity, sameSpotDistance, limit)
+}
+
+// MockStoreMergeTxInterface is a mock of StoreMergeTxInterface interface.
+type MockStoreMergeTxInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockStoreMergeTxInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockStoreMergeTxInterfaceMockRecorder is the mock recorder for MockStoreMergeTxInterface.
+type MockStoreMergeTxInterfaceMockRecorder struct {
+	mock *MockStoreMergeTxInterface
+}
+
+// NewMockStoreMergeTxInterface creates a new mock instance.
+func NewMockStoreMergeTxInterface(ctrl *gomock.Controller) *MockStoreMergeTxInterface {
+	mock := &MockStoreMergeTxInterface{ctrl: ctrl}
+	mock.recorder = &MockStoreMergeTxInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStoreMergeTxInterface) EXPECT() *MockStoreMergeTxInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Commit mocks base method.
+func (m *MockStoreMergeTxInterface) Commit() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Commit")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Commit indicates an expected call of Commit.
+func (mr *MockStoreMergeTxInterfaceMockRecorder) Commit() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockStoreMergeTxInterface)(nil).Commit))
+}
+
+// DeleteReview mocks base method.
+func (m *MockStoreMergeTxInterface) DeleteReview(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockStoreMergeTxInterfaceMockRecorder) DeleteReview(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockStoreMergeTxInterface)(nil).DeleteReview), id)
+}
+
+// GetReviewConflicts mocks base method.
+func (m *MockStoreMergeTxInterface) GetReviewConflicts() ([][2]*models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewConflicts")
+	ret0, _ := ret[0].([][2]*models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewConflicts indicates an expected call of GetReviewConflicts.
+func (mr *MockStoreMergeTxInterfaceMockRecorder) GetReviewConflicts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewConflicts", reflect.TypeOf((*MockStoreMergeTxInterface)(nil).GetReviewConflicts))
+}
+
+// MergeListEntries mocks base method.
+func (m *MockStoreMergeTxInterface) MergeListEntries() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeListEntries")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeListEntries indicates an expected call of MergeListEntries.
+func (mr *MockStoreMergeTxInterfaceMockRecorder) MergeListEntries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeListEntries", reflect.TypeOf((*MockStoreMergeTxInterface)(nil).MergeListEntries))
+}
+
+// MoveMenuItems mocks base method.
+func (m *MockStoreMergeTxInterface) MoveMenuItems(fromReviewID, toReviewID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveMenuItems", fromReviewID, toReviewID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveMenuItems indicates an expected call of MoveMenuItems.
+func (mr *MockStoreMergeTxInterfaceMockRecorder) MoveMenuItems(fromReviewID, toReviewID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveMenuItems", reflect.TypeOf((*MockStoreMergeTxInterface)(nil).MoveMenuItems), fromReviewID, toReviewID)
+}
+
+// MoveReviews mocks base method.
+func (m *MockStoreMergeTxInterface) MoveReviews() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveReviews")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveReviews indicates an expected call of MoveReviews.
+func (mr *MockStoreMergeTxInterfaceMockRecorder) MoveReviews() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveReviews", reflect.TypeOf((*MockStoreMergeTxInterface)(nil).MoveReviews))
+}
+
+// Rollback mocks base method.
+func (m *MockStoreMergeTxInterface) Rollback() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rollback indicates an expected call of Rollback.
+func (mr *MockStoreMergeTxInterfaceMockRecorder) Rollback() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockStoreMergeTxInterface)(nil).Rollback))
+}
+
+// Stores mocks base method.
+func (m *MockStoreMergeTxInterface) Stores() (target, source *models.Store) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stores")
+	ret0, _ := ret[0].(*models.Store)
+	ret1, _ := ret[1].(*models.Store)
+	return ret0, ret1
+}
+
+// Stores indicates an expected call of Stores.
+func (mr *MockStoreMergeTxInterfaceMockRecorder) Stores() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stores", reflect.TypeOf((*MockStoreMergeTxInterface)(nil).Stores))
+}
+
+// TrashSource mocks base method.
+func (m *MockStoreMergeTxInterface) TrashSource(actorID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrashSource", actorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TrashSource indicates an expected call of TrashSource.
+func (mr *MockStoreMergeTxInterfaceMockRecorder) TrashSource(actorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrashSource", reflect.TypeOf((*MockStoreMergeTxInterface)(nil).TrashSource), actorID)
+}
+
+// UpdateReview mocks base method.
+func (m *MockStoreMergeTxInterface) UpdateReview(review *models.Review) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReview", review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReview indicates an expected call of UpdateReview.
+func (mr *MockStoreMergeTxInterfaceMockRecorder) UpdateReview(review any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReview", reflect.TypeOf((*MockStoreMergeTxInterface)(nil).UpdateReview), review)
+}
+
+// UpdateTarget mocks base method.
+func (m *MockStoreMergeTxInterface) UpdateTarget(merged *models.Store, actorID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTarget", merged, actorID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTarget indicates an expected call of UpdateTarget.
+func (mr *MockStoreMergeTxInterfaceMockRecorder) UpdateTarget(merged, actorID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTarget", reflect.TypeOf((*MockStoreMergeTxInterface)(nil).UpdateTarget), merged, actorID)
+}
@@ -19,13 +19,13 @@ func (r *ReviewRepository) Create(review *models.Review) error {
 	query := `
 		INSERT INTO reviews (id, store_id, user_id, rating, comment, photos, visit_date, is_visited, payment_amount, food_notes, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+		RETURNING created_at, updated_at
 	`
 	review.ID = uuid.New()
-	_, err := r.db.Exec(query,
+	return r.db.QueryRow(query,
 		review.ID, review.StoreID, review.UserID, review.Rating, review.Comment,
 		review.Photos, review.VisitDate, review.IsVisited, review.PaymentAmount, review.FoodNotes,
-	)
-	return err
+	).Scan(&review.CreatedAt, &review.UpdatedAt)
 }
 
 func (r *ReviewRepository) GetByID(id uuid.UUID) (*models.Review, error) {
@@ -150,15 +150,22 @@ func (r *ReviewRepository) GetByStoreAndUser(storeID, userID uuid.UUID) (*models
 	return &review, nil
 }
 
+// Update saves a review. review.UpdatedAt must be the value the changes were based on;
+// if the review has been updated since, ErrVersionConflict is returned.
 func (r *ReviewRepository) Update(review *models.Review) error {
 	query := `
 		UPDATE reviews SET 
 			rating = $2, comment = $3, photos = $4, visit_date = $5, is_visited = $6, payment_amount = $7, food_notes = $8, updated_at = NOW()
-		WHERE id = $1
+		WHERE id = $1 AND updated_at = $9
+		RETURNING updated_at
 	`
-	_, err := r.db.Exec(query,
+	err := r.db.QueryRow(query,
 		review.ID, review.Rating, review.Comment, review.Photos, review.VisitDate, review.IsVisited, review.PaymentAmount, review.FoodNotes,
-	)
+		review.UpdatedAt,
+	).Scan(&review.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrVersionConflict
+	}
 	return err
 }
 
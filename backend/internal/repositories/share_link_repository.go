@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"database/sql"
+	"sukimise/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type ShareLinkRepository struct {
+	db *sql.DB
+}
+
+func NewShareLinkRepository(db *sql.DB) *ShareLinkRepository {
+	return &ShareLinkRepository{db: db}
+}
+
+// shareLinkColumns are the columns scanned by scanShareLink
+const shareLinkColumns = `id, scope, store_id, list_id, filter_query, created_by,
+	expires_at, revoked_at, access_count, last_accessed_at, created_at`
+
+func scanShareLink(row interface{ Scan(...interface{}) error }) (*models.ShareLink, error) {
+	var link models.ShareLink
+	err := row.Scan(&link.ID, &link.Scope, &link.StoreID, &link.ListID, &link.FilterQuery, &link.CreatedBy,
+		&link.ExpiresAt, &link.RevokedAt, &link.AccessCount, &link.LastAccessedAt, &link.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *ShareLinkRepository) Create(link *models.ShareLink) error {
+	query := `
+		INSERT INTO viewer_share_links (scope, store_id, list_id, filter_query, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRow(query, link.Scope, link.StoreID, link.ListID, link.FilterQuery, link.CreatedBy, link.ExpiresAt).
+		Scan(&link.ID, &link.CreatedAt)
+}
+
+func (r *ShareLinkRepository) GetByID(id uuid.UUID) (*models.ShareLink, error) {
+	return scanShareLink(r.db.QueryRow(`SELECT `+shareLinkColumns+` FROM viewer_share_links WHERE id = $1`, id))
+}
+
+// GetAll returns the links created by createdBy, or by anyone when createdBy is nil, newest first
+func (r *ShareLinkRepository) GetAll(createdBy *uuid.UUID, limit, offset int) ([]*models.ShareLink, error) {
+	rows, err := r.db.Query(`
+		SELECT `+shareLinkColumns+` FROM viewer_share_links
+		WHERE $1::uuid IS NULL OR created_by = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3
+	`, createdBy, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := []*models.ShareLink{}
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+func (r *ShareLinkRepository) GetCount(createdBy *uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM viewer_share_links WHERE $1::uuid IS NULL OR created_by = $1`, createdBy).Scan(&count)
+	return count, err
+}
+
+// RecordAccess counts an access to the link and returns it, or sql.ErrNoRows
+// when the link does not exist, has expired or was revoked
+func (r *ShareLinkRepository) RecordAccess(id uuid.UUID) (*models.ShareLink, error) {
+	return scanShareLink(r.db.QueryRow(`
+		UPDATE viewer_share_links
+		SET access_count = access_count + 1, last_accessed_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		RETURNING `+shareLinkColumns, id))
+}
+
+// Revoke ends access through the link. Revoking a revoked link keeps the first revocation time.
+func (r *ShareLinkRepository) Revoke(id uuid.UUID) (*models.ShareLink, error) {
+	return scanShareLink(r.db.QueryRow(`
+		UPDATE viewer_share_links
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1
+		RETURNING `+shareLinkColumns, id))
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Orderings of store listings. A cursor is only valid for the ordering it was issued for.
+const (
+	StoreOrderCreatedAt = "created_at"
+	StoreOrderDistance  = "distance"
+	StoreOrderRelevance = "relevance"
+)
+
+// Sort keys of StoreFilter.Sort
+const (
+	StoreSortAvgRating   = "avg_rating"
+	StoreSortReviewCount = "review_count"
+	StoreSortLastVisit   = "last_visit"
+	StoreSortName        = "name"
+	StoreSortUpdatedAt   = "updated_at"
+)
+
+// IsValidStoreSort reports whether sort is one of the StoreSort* keys
+func IsValidStoreSort(sort string) bool {
+	switch sort {
+	case StoreSortAvgRating, StoreSortReviewCount, StoreSortLastVisit, StoreSortName, StoreSortUpdatedAt:
+		return true
+	}
+	return false
+}
+
+// StoreCursor identifies the last store of a page for keyset pagination.
+// SortKey is the numeric sort key of that store (distance, relevance, rating...)
+// and SortText its name when sorting by name.
+type StoreCursor struct {
+	Order     string    `json:"o"`
+	SortKey   float64   `json:"k,omitempty"`
+	SortText  string    `json:"t,omitempty"`
+	CreatedAt time.Time `json:"c"`
+	ID        uuid.UUID `json:"i"`
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c *StoreCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeStoreCursor parses a cursor returned by Encode
+func DecodeStoreCursor(s string) (*StoreCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	var cursor StoreCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	if !isValidStoreOrder(cursor.Order) {
+		return nil, fmt.Errorf("unknown cursor order: %s", cursor.Order)
+	}
+	if cursor.ID == uuid.Nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	return &cursor, nil
+}
+
+// Order returns the ordering of stores listed with the filter: an explicit
+// sort such as "avg_rating:desc", else distance, relevance or newest first
+func (f *StoreFilter) Order() string {
+	if f.Sort != "" {
+		if f.SortAscending {
+			return f.Sort + ":asc"
+		}
+		return f.Sort + ":desc"
+	}
+	if f.OrderByProximity && f.Latitude != nil && f.Longitude != nil {
+		return StoreOrderDistance
+	}
+	if f.Query != "" {
+		return StoreOrderRelevance
+	}
+	return StoreOrderCreatedAt
+}
+
+func isValidStoreOrder(order string) bool {
+	switch order {
+	case StoreOrderCreatedAt, StoreOrderDistance, StoreOrderRelevance:
+		return true
+	}
+	sort, direction, ok := strings.Cut(order, ":")
+	return ok && IsValidStoreSort(sort) && (direction == "asc" || direction == "desc")
+}
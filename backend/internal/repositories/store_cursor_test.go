@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreCursor_EncodeDecode(t *testing.T) {
+	cursor := &StoreCursor{
+		Order:     StoreOrderDistance,
+		SortKey:   1234.5678901234,
+		CreatedAt: time.Date(2024, 5, 1, 12, 30, 0, 123456000, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	decoded, err := DecodeStoreCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, cursor.Order, decoded.Order)
+	assert.Equal(t, cursor.SortKey, decoded.SortKey)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+}
+
+func TestDecodeStoreCursor_Invalid(t *testing.T) {
+	unknownOrder := (&StoreCursor{Order: "price:asc", ID: uuid.New()}).Encode()
+	missingDirection := (&StoreCursor{Order: StoreSortName, ID: uuid.New()}).Encode()
+	missingID := (&StoreCursor{Order: StoreOrderCreatedAt}).Encode()
+
+	for _, s := range []string{"not a cursor", "e30", unknownOrder, missingDirection, missingID} {
+		_, err := DecodeStoreCursor(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestStoreFilter_Order(t *testing.T) {
+	lat, lng := 35.68, 139.76
+
+	assert.Equal(t, StoreOrderCreatedAt, (&StoreFilter{}).Order())
+	assert.Equal(t, StoreOrderRelevance, (&StoreFilter{Query: "ラーメン"}).Order())
+	assert.Equal(t, StoreOrderDistance, (&StoreFilter{Query: "ラーメン", Latitude: &lat, Longitude: &lng, OrderByProximity: true}).Order())
+	assert.Equal(t, StoreOrderCreatedAt, (&StoreFilter{OrderByProximity: true}).Order())
+	assert.Equal(t, "avg_rating:desc", (&StoreFilter{Sort: StoreSortAvgRating, Latitude: &lat, Longitude: &lng, OrderByProximity: true}).Order())
+	assert.Equal(t, "name:asc", (&StoreFilter{Sort: StoreSortName, SortAscending: true}).Order())
+}
@@ -0,0 +1,309 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sukimise/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrListStoreNotFound is returned when adding a store that does not exist or is in the trash to a list
+var ErrListStoreNotFound = errors.New("store not found")
+
+// StoreListFilter selects the lists returned by StoreListRepository.GetAll
+type StoreListFilter struct {
+	ViewerID   *uuid.UUID // Only lists this user may see; nil for anonymous readers
+	ViewerRole string     // Role of ViewerID
+	OwnerID    *uuid.UUID // Only lists of this owner
+	StoreID    *uuid.UUID // Only lists containing this store
+}
+
+type StoreListRepository struct {
+	db *sql.DB
+}
+
+func NewStoreListRepository(db *sql.DB) *StoreListRepository {
+	return &StoreListRepository{db: db}
+}
+
+// storeListColumns are the columns scanned by scanStoreList. Entries of stores
+// in the trash are not counted, as they are not listed either.
+const storeListColumns = `l.id, l.owner_id, u.username, l.title, l.description, l.visibility, l.created_at, l.updated_at,
+	(SELECT COUNT(*) FROM store_list_entries e JOIN stores s ON s.id = e.store_id
+	 WHERE e.list_id = l.id AND s.deleted_at IS NULL)`
+
+func scanStoreList(row interface{ Scan(...interface{}) error }) (*models.StoreList, error) {
+	var list models.StoreList
+	err := row.Scan(&list.ID, &list.OwnerID, &list.OwnerUsername, &list.Title, &list.Description,
+		&list.Visibility, &list.CreatedAt, &list.UpdatedAt, &list.EntryCount)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetAll returns the lists matching the filter, most recently updated first
+func (r *StoreListRepository) GetAll(filter *StoreListFilter) ([]*models.StoreList, error) {
+	q := &storeQuery{}
+	listVisibilityCondition(q, filter)
+	if filter.OwnerID != nil {
+		q.where("l.owner_id = " + q.arg(*filter.OwnerID))
+	}
+	if filter.StoreID != nil {
+		q.where("EXISTS (SELECT 1 FROM store_list_entries e WHERE e.list_id = l.id AND e.store_id = " + q.arg(*filter.StoreID) + ")")
+	}
+
+	query := "SELECT " + storeListColumns + " FROM store_lists l JOIN users u ON u.id = l.owner_id" +
+		q.whereClause() + " ORDER BY l.updated_at DESC, l.id"
+
+	rows, err := r.db.Query(query, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lists := []*models.StoreList{}
+	for rows.Next() {
+		list, err := scanStoreList(rows)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	return lists, rows.Err()
+}
+
+// listVisibilityCondition matches the lists filter.ViewerID may see, with the
+// same rules as models.StoreList.CanView
+func listVisibilityCondition(q *storeQuery, filter *StoreListFilter) {
+	switch {
+	case filter.ViewerID == nil:
+		q.where("l.visibility = " + q.arg(models.ListVisibilityViewers))
+	case models.CanSeeEditorLists(filter.ViewerRole):
+		q.where(fmt.Sprintf("(l.visibility IN (%s, %s) OR l.owner_id = %s)",
+			q.arg(models.ListVisibilityViewers), q.arg(models.ListVisibilityEditors), q.arg(*filter.ViewerID)))
+	default:
+		q.where(fmt.Sprintf("(l.visibility = %s OR l.owner_id = %s)",
+			q.arg(models.ListVisibilityViewers), q.arg(*filter.ViewerID)))
+	}
+}
+
+// GetByID returns a list without its entries
+func (r *StoreListRepository) GetByID(id uuid.UUID) (*models.StoreList, error) {
+	query := "SELECT " + storeListColumns + " FROM store_lists l JOIN users u ON u.id = l.owner_id WHERE l.id = $1"
+	return scanStoreList(r.db.QueryRow(query, id))
+}
+
+// GetEntries returns the entries of a list in order, with their stores and
+// review summaries. Stores in the trash are left out.
+func (r *StoreListRepository) GetEntries(listID uuid.UUID) ([]*models.StoreListEntry, error) {
+	query := `
+		SELECT e.store_id, e.position, e.note, e.created_at, s.*
+		FROM store_list_entries e
+		JOIN (
+		  SELECT ` + storeColumns + `, rs.avg_rating, rs.review_count, rs.last_visit
+		  FROM stores` + storeReviewSummaryJoin + `
+		  WHERE deleted_at IS NULL
+		) s ON s.id = e.store_id
+		WHERE e.list_id = $1
+		ORDER BY e.position, e.created_at
+	`
+	rows, err := r.db.Query(query, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.StoreListEntry{}
+	for rows.Next() {
+		var entry models.StoreListEntry
+		var store models.Store
+		var summary models.StoreReviewSummary
+		err := rows.Scan(
+			&entry.StoreID, &entry.Position, &entry.Note, &entry.CreatedAt,
+			&store.ID, &store.Name, &store.Address, &store.Latitude, &store.Longitude,
+			&store.Categories, &store.BusinessHours, &store.BusinessHourExceptions, &store.ParkingInfo,
+			&store.WebsiteURL, &store.GoogleMapURL, &store.SnsUrls, &store.Tags,
+			&store.Photos, &store.CreatedBy, &store.CreatedAt, &store.UpdatedAt,
+			&summary.AverageRating, &summary.ReviewCount, &summary.LastVisitDate,
+		)
+		if err != nil {
+			return nil, err
+		}
+		store.ReviewSummary = &summary
+		entry.Store = &store
+		entries = append(entries, &entry)
+	}
+	return entries, rows.Err()
+}
+
+// Create inserts a list together with its entries, numbered in order
+func (r *StoreListRepository) Create(list *models.StoreList) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO store_lists (owner_id, title, description, visibility)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRow(query, list.OwnerID, list.Title, list.Description, list.Visibility).
+		Scan(&list.ID, &list.CreatedAt, &list.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := putStoreListEntries(tx, list.ID, list.Entries); err != nil {
+		return err
+	}
+	list.EntryCount = len(list.Entries)
+
+	return tx.Commit()
+}
+
+// Update saves the title, description and visibility of a list. Unless
+// list.Entries is nil, the entries are replaced by list.Entries in order.
+// Entries of stores in the trash are kept, so that restoring a store
+// brings it back to its lists.
+func (r *StoreListRepository) Update(list *models.StoreList) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE store_lists
+		SET title = $2, description = $3, visibility = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	err = tx.QueryRow(query, list.ID, list.Title, list.Description, list.Visibility).Scan(&list.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if list.Entries != nil {
+		args := []interface{}{list.ID}
+		keep := ""
+		if len(list.Entries) > 0 {
+			placeholders := make([]string, len(list.Entries))
+			for i, entry := range list.Entries {
+				args = append(args, entry.StoreID)
+				placeholders[i] = fmt.Sprintf("$%d", len(args))
+			}
+			keep = " AND store_id NOT IN (" + strings.Join(placeholders, ",") + ")"
+		}
+		_, err := tx.Exec(`
+			DELETE FROM store_list_entries
+			WHERE list_id = $1`+keep+`
+			  AND store_id IN (SELECT id FROM stores WHERE deleted_at IS NULL)
+		`, args...)
+		if err != nil {
+			return err
+		}
+
+		if err := putStoreListEntries(tx, list.ID, list.Entries); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// putStoreListEntries inserts or updates the entries of a list, numbering them from 1
+func putStoreListEntries(tx *sql.Tx, listID uuid.UUID, entries []*models.StoreListEntry) error {
+	query := `
+		INSERT INTO store_list_entries (list_id, store_id, position, note)
+		SELECT $1, id, $3, $4 FROM stores WHERE id = $2 AND deleted_at IS NULL
+		ON CONFLICT (list_id, store_id) DO UPDATE SET position = EXCLUDED.position, note = EXCLUDED.note
+		RETURNING created_at
+	`
+	for i, entry := range entries {
+		entry.Position = i + 1
+		err := tx.QueryRow(query, listID, entry.StoreID, entry.Position, entry.Note).Scan(&entry.CreatedAt)
+		if err == sql.ErrNoRows {
+			return ErrListStoreNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete removes a list and its entries
+func (r *StoreListRepository) Delete(id uuid.UUID) error {
+	result, err := r.db.Exec(`DELETE FROM store_lists WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// AddEntry adds a store to the end of a list. When the list already has the
+// store, only its note is updated.
+func (r *StoreListRepository) AddEntry(listID uuid.UUID, entry *models.StoreListEntry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO store_list_entries (list_id, store_id, position, note)
+		SELECT $1, id, COALESCE((SELECT MAX(position) FROM store_list_entries WHERE list_id = $1), 0) + 1, $3
+		FROM stores WHERE id = $2 AND deleted_at IS NULL
+		ON CONFLICT (list_id, store_id) DO UPDATE SET note = EXCLUDED.note
+		RETURNING position, created_at
+	`
+	err = tx.QueryRow(query, listID, entry.StoreID, entry.Note).Scan(&entry.Position, &entry.CreatedAt)
+	if err == sql.ErrNoRows {
+		return ErrListStoreNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := touchStoreList(tx, listID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RemoveEntry removes a store from a list. sql.ErrNoRows is returned when
+// the list does not have the store.
+func (r *StoreListRepository) RemoveEntry(listID, storeID uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM store_list_entries WHERE list_id = $1 AND store_id = $2`, listID, storeID)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := touchStoreList(tx, listID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func touchStoreList(tx *sql.Tx, listID uuid.UUID) error {
+	_, err := tx.Exec(`UPDATE store_lists SET updated_at = NOW() WHERE id = $1`, listID)
+	return err
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"sukimise/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListVisibilityCondition(t *testing.T) {
+	userID := uuid.New()
+
+	tests := []struct {
+		name          string
+		filter        StoreListFilter
+		expectedWhere string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "anonymous",
+			filter:        StoreListFilter{},
+			expectedWhere: " WHERE l.visibility = $1",
+			expectedArgs:  []interface{}{models.ListVisibilityViewers},
+		},
+		{
+			name:          "editor",
+			filter:        StoreListFilter{ViewerID: &userID, ViewerRole: string(models.RoleEditor)},
+			expectedWhere: " WHERE (l.visibility IN ($1, $2) OR l.owner_id = $3)",
+			expectedArgs:  []interface{}{models.ListVisibilityViewers, models.ListVisibilityEditors, userID},
+		},
+		{
+			name:          "viewer account",
+			filter:        StoreListFilter{ViewerID: &userID, ViewerRole: string(models.RoleViewer)},
+			expectedWhere: " WHERE (l.visibility = $1 OR l.owner_id = $2)",
+			expectedArgs:  []interface{}{models.ListVisibilityViewers, userID},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &storeQuery{}
+			listVisibilityCondition(q, &tt.filter)
+			assert.Equal(t, tt.expectedWhere, q.whereClause())
+			assert.Equal(t, tt.expectedArgs, q.args)
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package repositories
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"sukimise/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type StoreMergeRepository struct {
+	db *sql.DB
+}
+
+func NewStoreMergeRepository(db *sql.DB) *StoreMergeRepository {
+	return &StoreMergeRepository{db: db}
+}
+
+// FindCandidates returns pairs of stores within radius meters of each other
+// whose normalized names have at least minSimilarity trigram similarity, or
+// that are within sameSpotDistance meters whatever their names, so that
+// "スターバックス 渋谷店" and "Starbucks Shibuya" at one spot are found too.
+// The most similar and closest pairs come first. Stores in the trash are ignored.
+func (r *StoreMergeRepository) FindCandidates(radius, minSimilarity, sameSpotDistance float64, limit int) ([]*models.StoreDuplicateCandidate, error) {
+	query := `
+		SELECT a.id, b.id, pair.distance, pair.similarity
+		FROM stores a
+		JOIN stores b
+		  ON (a.created_at, a.id) < (b.created_at, b.id)
+		 AND b.deleted_at IS NULL
+		 AND ST_DWithin(a.location, b.location, $1)
+		CROSS JOIN LATERAL (
+			SELECT ST_Distance(a.location, b.location) AS distance,
+			       similarity(normalize_search_text(a.name), normalize_search_text(b.name))::float8 AS similarity
+		) pair
+		WHERE a.deleted_at IS NULL
+		  AND (pair.similarity >= $2 OR pair.distance <= $3)
+		ORDER BY pair.similarity DESC, pair.distance, a.id, b.id
+		LIMIT $4
+	`
+	rows, err := r.db.Query(query, radius, minSimilarity, sameSpotDistance, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type pair struct {
+		storeID, duplicateID uuid.UUID
+		distance, similarity float64
+	}
+	var pairs []pair
+	var ids []uuid.UUID
+	for rows.Next() {
+		var p pair
+		if err := rows.Scan(&p.storeID, &p.duplicateID, &p.distance, &p.similarity); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, p)
+		ids = append(ids, p.storeID, p.duplicateID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	stores, err := r.getStores(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*models.StoreDuplicateCandidate, 0, len(pairs))
+	for _, p := range pairs {
+		candidates = append(candidates, &models.StoreDuplicateCandidate{
+			Store:          stores[p.storeID],
+			Duplicate:      stores[p.duplicateID],
+			Distance:       p.distance,
+			NameSimilarity: p.similarity,
+		})
+	}
+	return candidates, nil
+}
+
+// getStores returns the stores with the given IDs by ID
+func (r *StoreMergeRepository) getStores(ids []uuid.UUID) (map[uuid.UUID]*models.Store, error) {
+	stores := make(map[uuid.UUID]*models.Store, len(ids))
+	if len(ids) == 0 {
+		return stores, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+	query := fmt.Sprintf(`SELECT %s FROM stores WHERE id IN (%s)`, storeColumns, strings.Join(placeholders, ","))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var store models.Store
+		err := rows.Scan(
+			&store.ID, &store.Name, &store.Address, &store.Latitude, &store.Longitude,
+			&store.Categories, &store.BusinessHours, &store.BusinessHourExceptions, &store.ParkingInfo,
+			&store.WebsiteURL, &store.GoogleMapURL, &store.SnsUrls, &store.Tags,
+			&store.Photos, &store.CreatedBy, &store.CreatedAt, &store.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		stores[store.ID] = &store
+	}
+	return stores, rows.Err()
+}
+
+// storeMergeTx is a merge of two stores in progress, see BeginMerge
+type storeMergeTx struct {
+	tx                 *sql.Tx
+	target, source     *models.Store
+	targetID, sourceID uuid.UUID
+}
+
+// BeginMerge starts merging the source store into the target store in a
+// transaction that holds both store rows locked until Commit or Rollback.
+// sql.ErrNoRows is returned when either store does not exist or is in the trash.
+func (r *StoreMergeRepository) BeginMerge(targetID, sourceID uuid.UUID) (StoreMergeTxInterface, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	// Lock both rows in a fixed order so that concurrent merges cannot deadlock
+	ids := []uuid.UUID{targetID, sourceID}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+	locked := make(map[uuid.UUID]*models.Store, len(ids))
+	for _, id := range ids {
+		store, err := getStoreForUpdate(tx, id, false)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		locked[id] = store
+	}
+
+	return &storeMergeTx{
+		tx:       tx,
+		target:   locked[targetID],
+		source:   locked[sourceID],
+		targetID: targetID,
+		sourceID: sourceID,
+	}, nil
+}
+
+// Stores returns the locked target and source stores
+func (m *storeMergeTx) Stores() (target, source *models.Store) {
+	return m.target, m.source
+}
+
+// UpdateTarget saves the merged labels, photos, SNS URLs and optional fields
+// of the target store, sets its UpdatedAt and records a revision
+func (m *storeMergeTx) UpdateTarget(merged *models.Store, actorID uuid.UUID) error {
+	query := `
+		UPDATE stores
+		SET categories = $2, tags = $3, photos = $4, sns_urls = $5,
+		    parking_info = $6, website_url = $7, google_map_url = $8, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`
+	err := m.tx.QueryRow(query, m.targetID, merged.Categories, merged.Tags, merged.Photos, merged.SnsUrls,
+		merged.ParkingInfo, merged.WebsiteURL, merged.GoogleMapURL).Scan(&merged.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update store %s: %w", m.targetID, err)
+	}
+	return insertStoreRevision(m.tx, actorID, models.StoreRevisionActionUpdate, m.target, merged, nil)
+}
+
+// GetReviewConflicts locks and returns the pairs of reviews by the same user
+// on both stores, target review first. idx_reviews_unique_store_user allows
+// one review per user and store, so these cannot simply be moved.
+func (m *storeMergeTx) GetReviewConflicts() ([][2]*models.Review, error) {
+	rows, err := m.tx.Query(`
+		SELECT t.id, s.id
+		FROM reviews s
+		JOIN reviews t ON t.store_id = $1 AND t.user_id = s.user_id
+		WHERE s.store_id = $2
+		ORDER BY t.id
+	`, m.targetID, m.sourceID)
+	if err != nil {
+		return nil, err
+	}
+	var ids [][2]uuid.UUID
+	for rows.Next() {
+		var pair [2]uuid.UUID
+		if err := rows.Scan(&pair[0], &pair[1]); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, pair)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	conflicts := make([][2]*models.Review, 0, len(ids))
+	for _, pair := range ids {
+		kept, err := getReviewForUpdate(m.tx, pair[0])
+		if err != nil {
+			return nil, err
+		}
+		other, err := getReviewForUpdate(m.tx, pair[1])
+		if err != nil {
+			return nil, err
+		}
+		conflicts = append(conflicts, [2]*models.Review{kept, other})
+	}
+	return conflicts, nil
+}
+
+// UpdateReview saves the contents of a review merged with another
+func (m *storeMergeTx) UpdateReview(review *models.Review) error {
+	_, err := m.tx.Exec(`
+		UPDATE reviews
+		SET rating = $2, comment = $3, photos = $4, visit_date = $5, is_visited = $6,
+		    payment_amount = $7, food_notes = $8, updated_at = NOW()
+		WHERE id = $1
+	`, review.ID, review.Rating, review.Comment, review.Photos, review.VisitDate, review.IsVisited, review.PaymentAmount, review.FoodNotes)
+	return err
+}
+
+// MoveMenuItems moves the menu items of a review to another review
+func (m *storeMergeTx) MoveMenuItems(fromReviewID, toReviewID uuid.UUID) error {
+	_, err := m.tx.Exec(`UPDATE menu_items SET review_id = $1 WHERE review_id = $2`, toReviewID, fromReviewID)
+	return err
+}
+
+// DeleteReview deletes a review merged into another
+func (m *storeMergeTx) DeleteReview(id uuid.UUID) error {
+	_, err := m.tx.Exec(`DELETE FROM reviews WHERE id = $1`, id)
+	return err
+}
+
+// MoveReviews moves the remaining reviews of the source store to the target
+// store and returns how many were moved
+func (m *storeMergeTx) MoveReviews() (int, error) {
+	// Moved reviews get a new updated_at so that ETags taken before the merge no longer match
+	result, err := m.tx.Exec(`UPDATE reviews SET store_id = $1, updated_at = NOW() WHERE store_id = $2`, m.targetID, m.sourceID)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
+
+// MergeListEntries replaces the source store with the target store in every
+// list, see mergeStoreListEntries
+func (m *storeMergeTx) MergeListEntries() (int, error) {
+	return mergeStoreListEntries(m.tx, m.targetID, m.sourceID)
+}
+
+// TrashSource moves the source store to the trash and records a revision
+func (m *storeMergeTx) TrashSource(actorID uuid.UUID) error {
+	deleted := *m.source
+	if err := m.tx.QueryRow(`UPDATE stores SET deleted_at = NOW() WHERE id = $1 RETURNING deleted_at`, m.sourceID).Scan(&deleted.DeletedAt); err != nil {
+		return fmt.Errorf("failed to delete store %s: %w", m.sourceID, err)
+	}
+	return insertStoreRevision(m.tx, actorID, models.StoreRevisionActionDelete, m.source, &deleted, nil)
+}
+
+func (m *storeMergeTx) Commit() error {
+	return m.tx.Commit()
+}
+
+// Rollback discards the merge. It does nothing after Commit.
+func (m *storeMergeTx) Rollback() error {
+	if err := m.tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		return err
+	}
+	return nil
+}
+
+// mergeStoreListEntries replaces the source store with the target store in
+// every list and returns the number of lists changed. Lists that have both
+// stores keep the target at its position, with the notes combined.
+func mergeStoreListEntries(tx *sql.Tx, targetID, sourceID uuid.UUID) (int, error) {
+	combined, err := tx.Exec(`
+		UPDATE store_list_entries t
+		SET note = CASE
+		      WHEN t.note = '' THEN s.note
+		      WHEN s.note = '' OR s.note = t.note THEN t.note
+		      ELSE t.note || E'\n\n' || s.note
+		    END
+		FROM store_list_entries s
+		WHERE t.store_id = $1 AND s.store_id = $2 AND s.list_id = t.list_id
+	`, targetID, sourceID)
+	if err != nil {
+		return 0, err
+	}
+	combinedCount, err := combined.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	moved, err := tx.Exec(`
+		UPDATE store_list_entries SET store_id = $1
+		WHERE store_id = $2
+		  AND list_id NOT IN (SELECT list_id FROM store_list_entries WHERE store_id = $1)
+	`, targetID, sourceID)
+	if err != nil {
+		return 0, err
+	}
+	movedCount, err := moved.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM store_list_entries WHERE store_id = $1`, sourceID); err != nil {
+		return 0, err
+	}
+	return int(combinedCount + movedCount), nil
+}
+
+// getReviewForUpdate locks a review row for the rest of the transaction and returns it
+func getReviewForUpdate(tx *sql.Tx, id uuid.UUID) (*models.Review, error) {
+	query := `
+		SELECT id, store_id, user_id, rating, comment, photos, visit_date, is_visited, payment_amount, food_notes, created_at, updated_at
+		FROM reviews WHERE id = $1
+		FOR UPDATE
+	`
+	var review models.Review
+	err := tx.QueryRow(query, id).Scan(
+		&review.ID, &review.StoreID, &review.UserID, &review.Rating, &review.Comment,
+		&review.Photos, &review.VisitDate, &review.IsVisited, &review.PaymentAmount, &review.FoodNotes, &review.CreatedAt, &review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
@@ -0,0 +1,408 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"sukimise/internal/holidays"
+	"time"
+)
+
+// searchCondition matches stores whose own text or review text contains the
+// query, or resembles it (pg_trgm word similarity), after normalize_search_text
+// has folded full-width/half-width, case and katakana/hiragana differences.
+const searchCondition = `
+	(search_text LIKE search_like_pattern(%[1]s)
+	 OR normalize_search_text(%[1]s) <%% search_text
+	 OR EXISTS (
+	   SELECT 1 FROM reviews rv
+	   WHERE rv.store_id = stores.id
+	     AND (rv.search_text LIKE search_like_pattern(%[1]s)
+	          OR normalize_search_text(%[1]s) <%% rv.search_text)
+	 ))`
+
+// searchRank scores stores matching searchCondition: a match in the name
+// comes first, then similarity of the store text, then of its reviews.
+const searchRank = `
+	(CASE WHEN normalize_search_text(name) LIKE search_like_pattern(%[1]s) THEN 2 ELSE 0 END
+	 + word_similarity(normalize_search_text(%[1]s), search_text)
+	 + 0.5 * COALESCE((
+	     SELECT MAX(word_similarity(normalize_search_text(%[1]s), rv.search_text))
+	     FROM reviews rv WHERE rv.store_id = stores.id
+	   ), 0))`
+
+// storeColumns are the columns scanned into models.Store by store listings
+const storeColumns = `id, name, address, latitude, longitude, categories, business_hours, business_hour_exceptions,
+	parking_info, website_url, google_map_url, sns_urls,
+	tags, photos, created_by, created_at, updated_at`
+
+// storeReviewSummaryJoin aggregates the reviews of each listed store as rs
+const storeReviewSummaryJoin = `
+	LEFT JOIN LATERAL (
+	  SELECT AVG(r.rating)::float8 AS avg_rating, COUNT(*) AS review_count,
+	         MAX(r.visit_date) FILTER (WHERE r.is_visited) AS last_visit
+	  FROM reviews r WHERE r.store_id = stores.id
+	) rs ON true`
+
+// storeSortKeys are the descending and ascending sort key expressions of
+// StoreFilter.Sort. Stores without reviews come last in both directions.
+// Sorting by name uses the name column instead.
+var storeSortKeys = map[string][2]string{
+	StoreSortAvgRating:   {"COALESCE(rs.avg_rating, 0)", "COALESCE(rs.avg_rating, 6)"},
+	StoreSortReviewCount: {"rs.review_count::float8", "COALESCE(NULLIF(rs.review_count, 0)::float8, 1e15)"},
+	StoreSortLastVisit:   {"COALESCE(EXTRACT(EPOCH FROM rs.last_visit)::float8, -1e15)", "COALESCE(EXTRACT(EPOCH FROM rs.last_visit)::float8, 1e15)"},
+	StoreSortUpdatedAt:   {"EXTRACT(EPOCH FROM updated_at)::float8", "EXTRACT(EPOCH FROM updated_at)::float8"},
+}
+
+var weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// storeQuery collects the WHERE conditions of a store query and their
+// arguments, numbering placeholders in the order arguments are added.
+type storeQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg adds a query argument and returns its placeholder
+func (q *storeQuery) arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// argList adds each value as an argument and returns the comma-separated placeholders
+func (q *storeQuery) argList(values []string) string {
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		placeholders[i] = q.arg(value)
+	}
+	return strings.Join(placeholders, ",")
+}
+
+func (q *storeQuery) where(condition string) {
+	q.conditions = append(q.conditions, condition)
+}
+
+func (q *storeQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// storeFilterConditions add the conditions for each StoreFilter criterion.
+// Listing and counting stores share them, so new filters belong here.
+var storeFilterConditions = []func(q *storeQuery, filter *StoreFilter){
+	nameCondition,
+	searchQueryCondition,
+	categoriesCondition,
+	tagsCondition,
+	radiusCondition,
+	areaCondition,
+	businessHoursCondition,
+	openNowCondition,
+	ratingCondition,
+	visitedCondition,
+	priceCondition,
+	listCondition,
+}
+
+// newStoreFilterQuery returns the conditions selecting the stores, not in the trash, matching filter
+func newStoreFilterQuery(filter *StoreFilter) *storeQuery {
+	q := &storeQuery{conditions: []string{"deleted_at IS NULL"}}
+	for _, condition := range storeFilterConditions {
+		condition(q, filter)
+	}
+	return q
+}
+
+func nameCondition(q *storeQuery, filter *StoreFilter) {
+	if filter.Name != "" {
+		q.where("name ILIKE " + q.arg("%"+filter.Name+"%"))
+	}
+}
+
+func searchQueryCondition(q *storeQuery, filter *StoreFilter) {
+	if filter.Query != "" {
+		q.where(fmt.Sprintf(searchCondition, q.arg(filter.Query)))
+	}
+}
+
+func categoriesCondition(q *storeQuery, filter *StoreFilter) {
+	if len(filter.Categories) == 0 {
+		return
+	}
+	// デフォルトはOR、ANDが指定された場合は?&演算子を使用
+	operator := "?|"
+	if filter.CategoriesOperator == "AND" {
+		operator = "?&"
+	}
+	q.where(fmt.Sprintf("categories %s ARRAY[%s]::text[]", operator, q.argList(filter.Categories)))
+}
+
+func tagsCondition(q *storeQuery, filter *StoreFilter) {
+	if len(filter.Tags) == 0 {
+		return
+	}
+	// デフォルトはAND、ORが指定された場合は?|演算子を使用
+	operator := "?&"
+	if filter.TagsOperator == "OR" {
+		operator = "?|"
+	}
+	q.where(fmt.Sprintf("tags %s ARRAY[%s]::text[]", operator, q.argList(filter.Tags)))
+}
+
+// radiusCondition matches stores within Radius meters of Latitude/Longitude,
+// using the spatial index on the location column (see migration 016)
+func radiusCondition(q *storeQuery, filter *StoreFilter) {
+	if filter.Latitude == nil || filter.Longitude == nil || filter.Radius == nil {
+		return
+	}
+	q.where(fmt.Sprintf("ST_DWithin(location, ST_Point(%s, %s)::geography, %s)",
+		q.arg(*filter.Longitude), q.arg(*filter.Latitude), q.arg(*filter.Radius)))
+}
+
+// storeGeometry is the point of a store as indexed by idx_stores_geometry (see migration 015).
+// Bounding boxes and polygons are planar in longitude/latitude, so they use
+// this geometry rather than the geography location column.
+const storeGeometry = "ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)"
+
+// areaCondition matches stores inside BoundingBox and Polygon, using the spatial index
+func areaCondition(q *storeQuery, filter *StoreFilter) {
+	if box := filter.BoundingBox; box != nil {
+		q.where(fmt.Sprintf("%s && ST_MakeEnvelope(%s, %s, %s, %s, 4326)", storeGeometry,
+			q.arg(box.MinLongitude), q.arg(box.MinLatitude), q.arg(box.MaxLongitude), q.arg(box.MaxLatitude)))
+	}
+	if filter.Polygon != "" {
+		q.where(fmt.Sprintf("ST_Intersects(%s, ST_SetSRID(ST_GeomFromGeoJSON(%s), 4326))", storeGeometry, q.arg(filter.Polygon)))
+	}
+}
+
+// 営業時間検索（JSON形式対応）
+// BusinessDateが指定されていればその日付の祝日・臨時休業などの例外を考慮する
+func businessHoursCondition(q *storeQuery, filter *StoreFilter) {
+	switch {
+	case filter.BusinessDay != "" && filter.BusinessTime != "" && filter.BusinessDate != nil:
+		q.where(openOnCondition(q, *filter.BusinessDate, minuteOf(q.arg(filter.BusinessTime)), "0"))
+	case filter.BusinessDay != "" && filter.BusinessTime != "":
+		// 両方指定: 指定曜日の指定時間に営業している店（前日から日付をまたぐ営業を含む）
+		q.where(openAtCondition(q.arg(filter.BusinessDay), q.arg(previousWeekday(filter.BusinessDay)), minuteOf(q.arg(filter.BusinessTime)), "0"))
+	case filter.BusinessDay != "" && filter.BusinessDate != nil:
+		// 営業日のみ指定: その日付が例外を含めて休業日ではない店
+		date := *filter.BusinessDate
+		q.where(fmt.Sprintf("COALESCE(day_schedule_on(business_hours, business_hour_exceptions, %s::date, %s)->>'is_closed', 'false') != 'true'",
+			q.arg(date.Format("2006-01-02")), q.arg(holidays.IsHoliday(date))))
+	case filter.BusinessDay != "":
+		// 営業日のみ指定: その日が休業日ではない店
+		q.where(fmt.Sprintf("(business_hours IS NULL OR business_hours->%s->>'is_closed' != 'true')", q.arg(filter.BusinessDay)))
+	case filter.BusinessTime != "":
+		// 営業時間のみ指定: 1週間のうちどこか1日でもその時間に営業している店
+		minute := minuteOf(q.arg(filter.BusinessTime))
+		dayConditions := make([]string, len(weekdays))
+		for i, day := range weekdays {
+			dayConditions[i] = openAtCondition("'"+day+"'", "'"+previousWeekday(day)+"'", minute, "0")
+		}
+		q.where("(" + strings.Join(dayConditions, " OR ") + ")")
+	}
+}
+
+// openNowCondition matches stores open at OpenAt in its location, that still
+// take orders for at least LastOrderMargin minutes
+func openNowCondition(q *storeQuery, filter *StoreFilter) {
+	if filter.OpenAt == nil {
+		return
+	}
+	at := *filter.OpenAt
+	q.where(openOnCondition(q, at, q.arg(at.Hour()*60+at.Minute()), q.arg(filter.LastOrderMargin)))
+}
+
+// openOnCondition matches stores open on the date at minute of the day, applying
+// their business hour exceptions and public holidays (see migration 014)
+func openOnCondition(q *storeQuery, date time.Time, minute, margin string) string {
+	return fmt.Sprintf("business_hours_open_on(business_hours, business_hour_exceptions, %s::date, %s, %s, %s, %s)",
+		q.arg(date.Format("2006-01-02")), minute, margin,
+		q.arg(holidays.IsHoliday(date)), q.arg(holidays.IsHoliday(date.AddDate(0, 0, -1))))
+}
+
+// openAtCondition matches stores open on day at minute of the day, including
+// slots of the previous day that run past midnight (see migration 013)
+func openAtCondition(day, previousDay, minute, margin string) string {
+	return fmt.Sprintf("business_hours_open_at(business_hours, %s, %s, %s, %s)", day, previousDay, minute, margin)
+}
+
+// minuteOf converts the placeholder of an HH:MM time to the minute of the day
+func minuteOf(at string) string {
+	return fmt.Sprintf("(EXTRACT(EPOCH FROM %s::time)::integer / 60)", at)
+}
+
+// previousWeekday returns the day before day (monday...sunday)
+func previousWeekday(day string) string {
+	for i, weekday := range weekdays {
+		if weekday == day {
+			return weekdays[(i+6)%7]
+		}
+	}
+	return day
+}
+
+func ratingCondition(q *storeQuery, filter *StoreFilter) {
+	if filter.MinRating != nil {
+		q.where("EXISTS (SELECT 1 FROM reviews r WHERE r.store_id = stores.id AND r.rating >= " + q.arg(*filter.MinRating) + ")")
+	}
+}
+
+// visitedCondition matches stores that have (or lack) a visited review, by
+// anyone or by VisitedBy. VisitedBy alone means visited by that user.
+func visitedCondition(q *storeQuery, filter *StoreFilter) {
+	if filter.Visited == nil && filter.VisitedBy == nil {
+		return
+	}
+
+	visited := "SELECT 1 FROM reviews r WHERE r.store_id = stores.id AND r.is_visited"
+	if filter.VisitedBy != nil {
+		visited += " AND r.user_id = " + q.arg(*filter.VisitedBy)
+	}
+
+	if filter.Visited != nil && !*filter.Visited {
+		q.where("NOT EXISTS (" + visited + ")")
+	} else {
+		q.where("EXISTS (" + visited + ")")
+	}
+}
+
+// priceCondition compares the average payment amount of the latest 3 reviews
+// with a payment amount. Stores without payment amounts never match.
+func priceCondition(q *storeQuery, filter *StoreFilter) {
+	const averagePrice = `(SELECT AVG(recent.payment_amount) FROM (
+		SELECT r.payment_amount FROM reviews r
+		WHERE r.store_id = stores.id AND r.payment_amount IS NOT NULL
+		ORDER BY r.created_at DESC LIMIT 3) recent)`
+
+	if filter.MinPrice != nil {
+		q.where(averagePrice + " >= " + q.arg(*filter.MinPrice))
+	}
+	if filter.MaxPrice != nil {
+		q.where(averagePrice + " <= " + q.arg(*filter.MaxPrice))
+	}
+}
+
+// listCondition matches the stores of the list ListID
+func listCondition(q *storeQuery, filter *StoreFilter) {
+	if filter.ListID != nil {
+		q.where("EXISTS (SELECT 1 FROM store_list_entries e WHERE e.store_id = stores.id AND e.list_id = " + q.arg(*filter.ListID) + ")")
+	}
+}
+
+// buildStoreListQuery returns the query listing the stores selected by filter in
+// its order, with their review summary and the sort key of each store as the
+// last columns. It fetches one store more than filter.Limit to tell whether
+// there is a next page.
+func buildStoreListQuery(filter *StoreFilter) (string, []interface{}) {
+	q := newStoreFilterQuery(filter)
+	order := filter.Order()
+
+	sortKey := "0::float8"
+	switch order {
+	case StoreOrderCreatedAt:
+	case StoreOrderDistance:
+		// The KNN operator (distance on the sphere) lets the spatial index on
+		// location find the nearest stores even without a radius. Ordering,
+		// cursors and the sort key must all use this same expression.
+		sortKey = fmt.Sprintf("location <-> ST_Point(%s, %s)::geography", q.arg(*filter.Longitude), q.arg(*filter.Latitude))
+	case StoreOrderRelevance:
+		sortKey = "(" + fmt.Sprintf(searchRank, q.arg(filter.Query)) + ")::float8"
+	default:
+		if keys, ok := storeSortKeys[filter.Sort]; ok {
+			sortKey = keys[0]
+			if filter.SortAscending {
+				sortKey = keys[1]
+			}
+		}
+	}
+
+	direction, comparison := "DESC", "<"
+	if filter.SortAscending {
+		direction, comparison = "ASC", ">"
+	}
+
+	// Keyset pagination: continue after the last store of the previous page
+	if cursor := filter.Cursor; cursor != nil {
+		switch order {
+		case StoreOrderDistance:
+			q.where(fmt.Sprintf("(%s, id) > (%s, %s)", sortKey, q.arg(cursor.SortKey), q.arg(cursor.ID)))
+		case StoreOrderRelevance:
+			q.where(fmt.Sprintf("(%s, created_at, id) < (%s, %s, %s)", sortKey, q.arg(cursor.SortKey), q.arg(cursor.CreatedAt), q.arg(cursor.ID)))
+		case StoreOrderCreatedAt:
+			q.where(fmt.Sprintf("(created_at, id) < (%s, %s)", q.arg(cursor.CreatedAt), q.arg(cursor.ID)))
+		default:
+			if filter.Sort == StoreSortName {
+				q.where(fmt.Sprintf("(name, id) %s (%s, %s)", comparison, q.arg(cursor.SortText), q.arg(cursor.ID)))
+			} else {
+				q.where(fmt.Sprintf("(%s, id) %s (%s, %s)", sortKey, comparison, q.arg(cursor.SortKey), q.arg(cursor.ID)))
+			}
+		}
+	}
+
+	query := "SELECT " + storeColumns + ", rs.avg_rating, rs.review_count, rs.last_visit, " + sortKey + " AS sort_key" +
+		" FROM stores" + storeReviewSummaryJoin + q.whereClause()
+
+	// id breaks ties so that every ordering is total and cursors are stable
+	switch order {
+	case StoreOrderDistance:
+		query += " ORDER BY " + sortKey + " ASC, id ASC"
+	case StoreOrderRelevance:
+		query += " ORDER BY sort_key DESC, created_at DESC, id DESC"
+	case StoreOrderCreatedAt:
+		query += " ORDER BY created_at DESC, id DESC"
+	default:
+		key := "sort_key"
+		if filter.Sort == StoreSortName {
+			key = "name"
+		}
+		query += fmt.Sprintf(" ORDER BY %s %s, id %s", key, direction, direction)
+	}
+
+	if filter.Limit > 0 {
+		query += " LIMIT " + q.arg(filter.Limit+1)
+	}
+	if filter.Offset > 0 && filter.Cursor == nil {
+		query += " OFFSET " + q.arg(filter.Offset)
+	}
+
+	return query, q.args
+}
+
+// buildStoreCountQuery returns the query counting the stores selected by filter
+func buildStoreCountQuery(filter *StoreFilter) (string, []interface{}) {
+	q := newStoreFilterQuery(filter)
+	return "SELECT COUNT(*) FROM stores" + q.whereClause(), q.args
+}
+
+// buildStoreClusterQuery returns the query grouping the stores selected by
+// filter into grid cells of cellSize degrees, aligned to the origin so that
+// clusters stay put while the map is panned. Each row has the store count,
+// centroid, the store ID of single-store cells and the stores per category.
+func buildStoreClusterQuery(filter *StoreFilter, cellSize float64) (string, []interface{}) {
+	q := newStoreFilterQuery(filter)
+	query := fmt.Sprintf(`
+		WITH cells AS (
+		  SELECT id, categories, latitude, longitude, ST_SnapToGrid(%s, %s) AS cell
+		  FROM stores%s
+		),
+		cell_categories AS (
+		  SELECT cell, jsonb_object_agg(category, category_count) AS categories
+		  FROM (
+		    SELECT cell, category, COUNT(*) AS category_count
+		    FROM cells, jsonb_array_elements_text(categories) AS category
+		    GROUP BY cell, category
+		  ) counted
+		  GROUP BY cell
+		)
+		SELECT c.store_count, c.latitude, c.longitude, c.store_id, COALESCE(cc.categories, '{}')
+		FROM (
+		  SELECT cell, COUNT(*) AS store_count, AVG(latitude)::float8 AS latitude, AVG(longitude)::float8 AS longitude,
+		         CASE WHEN COUNT(*) = 1 THEN MIN(id::text)::uuid END AS store_id
+		  FROM cells GROUP BY cell
+		) c
+		LEFT JOIN cell_categories cc ON cc.cell = c.cell
+		ORDER BY c.store_count DESC`, storeGeometry, q.arg(cellSize), q.whereClause())
+	return query, q.args
+}
@@ -0,0 +1,389 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// normalizeSQL collapses whitespace so that queries compare independent of layout
+func normalizeSQL(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	query = strings.ReplaceAll(query, "( ", "(")
+	return strings.ReplaceAll(query, " )", ")")
+}
+
+func expectedOpenAt(day, previousDay, minute, margin string) string {
+	return fmt.Sprintf("business_hours_open_at(business_hours, %s, %s, %s, %s)", day, previousDay, minute, margin)
+}
+
+func expectedOpenOn(date, minute, margin, holiday, previousHoliday string) string {
+	return fmt.Sprintf("business_hours_open_on(business_hours, business_hour_exceptions, %s::date, %s, %s, %s, %s)",
+		date, minute, margin, holiday, previousHoliday)
+}
+
+func expectedSearch(p string) string {
+	return fmt.Sprintf("(search_text LIKE search_like_pattern(%[1]s) "+
+		"OR normalize_search_text(%[1]s) <%% search_text "+
+		"OR EXISTS (SELECT 1 FROM reviews rv WHERE rv.store_id = stores.id "+
+		"AND (rv.search_text LIKE search_like_pattern(%[1]s) OR normalize_search_text(%[1]s) <%% rv.search_text)))", p)
+}
+
+func expectedRank(p string) string {
+	return fmt.Sprintf("(CASE WHEN normalize_search_text(name) LIKE search_like_pattern(%[1]s) THEN 2 ELSE 0 END "+
+		"+ word_similarity(normalize_search_text(%[1]s), search_text) "+
+		"+ 0.5 * COALESCE((SELECT MAX(word_similarity(normalize_search_text(%[1]s), rv.search_text)) "+
+		"FROM reviews rv WHERE rv.store_id = stores.id), 0))", p)
+}
+
+func TestBuildStoreCountQuery(t *testing.T) {
+	lat, lng, radius := 35.68, 139.76, 500.0
+	minRating, minPrice, maxPrice := 4.0, 1000, 1500
+	visited, notVisited := true, false
+	userID, listID := uuid.New(), uuid.New()
+	averagePrice := "(SELECT AVG(recent.payment_amount) FROM (SELECT r.payment_amount FROM reviews r" +
+		" WHERE r.store_id = stores.id AND r.payment_amount IS NOT NULL ORDER BY r.created_at DESC LIMIT 3) recent)"
+
+	weekly := make([]string, len(weekdays))
+	previous := []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+	for i, day := range weekdays {
+		weekly[i] = expectedOpenAt("'"+day+"'", "'"+previous[i]+"'", "(EXTRACT(EPOCH FROM $1::time)::integer / 60)", "0")
+	}
+	openAt := time.Date(2024, 5, 6, 1, 30, 0, 0, time.FixedZone("JST", 9*60*60))      // Monday, 振替休日 after こどもの日
+	businessDate := time.Date(2024, 5, 7, 0, 0, 0, 0, time.FixedZone("JST", 9*60*60)) // Tuesday
+
+	tests := []struct {
+		name          string
+		filter        StoreFilter
+		expectedWhere string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "no filter",
+			filter:        StoreFilter{},
+			expectedWhere: "deleted_at IS NULL",
+		},
+		{
+			name:          "name",
+			filter:        StoreFilter{Name: "カフェ"},
+			expectedWhere: "deleted_at IS NULL AND name ILIKE $1",
+			expectedArgs:  []interface{}{"%カフェ%"},
+		},
+		{
+			name:          "search query",
+			filter:        StoreFilter{Query: "ラーメン"},
+			expectedWhere: "deleted_at IS NULL AND " + expectedSearch("$1"),
+			expectedArgs:  []interface{}{"ラーメン"},
+		},
+		{
+			name:          "categories default to OR",
+			filter:        StoreFilter{Categories: []string{"カフェ", "和食"}},
+			expectedWhere: "deleted_at IS NULL AND categories ?| ARRAY[$1,$2]::text[]",
+			expectedArgs:  []interface{}{"カフェ", "和食"},
+		},
+		{
+			name:          "categories with AND",
+			filter:        StoreFilter{Categories: []string{"カフェ"}, CategoriesOperator: "AND"},
+			expectedWhere: "deleted_at IS NULL AND categories ?& ARRAY[$1]::text[]",
+			expectedArgs:  []interface{}{"カフェ"},
+		},
+		{
+			name:          "tags default to AND",
+			filter:        StoreFilter{Tags: []string{"人気", "駅近"}},
+			expectedWhere: "deleted_at IS NULL AND tags ?& ARRAY[$1,$2]::text[]",
+			expectedArgs:  []interface{}{"人気", "駅近"},
+		},
+		{
+			name:          "tags with OR",
+			filter:        StoreFilter{Tags: []string{"人気"}, TagsOperator: "OR"},
+			expectedWhere: "deleted_at IS NULL AND tags ?| ARRAY[$1]::text[]",
+			expectedArgs:  []interface{}{"人気"},
+		},
+		{
+			name:          "radius",
+			filter:        StoreFilter{Latitude: &lat, Longitude: &lng, Radius: &radius},
+			expectedWhere: "deleted_at IS NULL AND ST_DWithin(location, ST_Point($1, $2)::geography, $3)",
+			expectedArgs:  []interface{}{lng, lat, radius},
+		},
+		{
+			name:          "bounding box",
+			filter:        StoreFilter{BoundingBox: &BoundingBox{MinLongitude: 139.7, MinLatitude: 35.6, MaxLongitude: 139.8, MaxLatitude: 35.7}},
+			expectedWhere: "deleted_at IS NULL AND ST_SetSRID(ST_MakePoint(longitude, latitude), 4326) && ST_MakeEnvelope($1, $2, $3, $4, 4326)",
+			expectedArgs:  []interface{}{139.7, 35.6, 139.8, 35.7},
+		},
+		{
+			name:          "polygon",
+			filter:        StoreFilter{Polygon: `{"coordinates":[[[139.7,35.6],[139.8,35.6],[139.8,35.7],[139.7,35.6]]],"type":"Polygon"}`},
+			expectedWhere: "deleted_at IS NULL AND ST_Intersects(ST_SetSRID(ST_MakePoint(longitude, latitude), 4326), ST_SetSRID(ST_GeomFromGeoJSON($1), 4326))",
+			expectedArgs:  []interface{}{`{"coordinates":[[[139.7,35.6],[139.8,35.6],[139.8,35.7],[139.7,35.6]]],"type":"Polygon"}`},
+		},
+		{
+			name:          "radius without longitude is ignored",
+			filter:        StoreFilter{Latitude: &lat, Radius: &radius},
+			expectedWhere: "deleted_at IS NULL",
+		},
+		{
+			name:          "business day and time",
+			filter:        StoreFilter{BusinessDay: "monday", BusinessTime: "12:00"},
+			expectedWhere: "deleted_at IS NULL AND " + expectedOpenAt("$1", "$2", "(EXTRACT(EPOCH FROM $3::time)::integer / 60)", "0"),
+			expectedArgs:  []interface{}{"monday", "sunday", "12:00"},
+		},
+		{
+			name:          "business day and time on a date",
+			filter:        StoreFilter{BusinessDay: "tuesday", BusinessTime: "12:00", BusinessDate: &businessDate},
+			expectedWhere: "deleted_at IS NULL AND " + expectedOpenOn("$2", "(EXTRACT(EPOCH FROM $1::time)::integer / 60)", "0", "$3", "$4"),
+			expectedArgs:  []interface{}{"12:00", "2024-05-07", false, true},
+		},
+		{
+			name:          "business day on a date",
+			filter:        StoreFilter{BusinessDay: "tuesday", BusinessDate: &businessDate},
+			expectedWhere: "deleted_at IS NULL AND COALESCE(day_schedule_on(business_hours, business_hour_exceptions, $1::date, $2)->>'is_closed', 'false') != 'true'",
+			expectedArgs:  []interface{}{"2024-05-07", false},
+		},
+		{
+			name:          "business day only",
+			filter:        StoreFilter{BusinessDay: "monday"},
+			expectedWhere: "deleted_at IS NULL AND (business_hours IS NULL OR business_hours->$1->>'is_closed' != 'true')",
+			expectedArgs:  []interface{}{"monday"},
+		},
+		{
+			name:          "business time only",
+			filter:        StoreFilter{BusinessTime: "12:00"},
+			expectedWhere: "deleted_at IS NULL AND (" + strings.Join(weekly, " OR ") + ")",
+			expectedArgs:  []interface{}{"12:00"},
+		},
+		{
+			name:          "open at",
+			filter:        StoreFilter{OpenAt: &openAt, LastOrderMargin: 30},
+			expectedWhere: "deleted_at IS NULL AND " + expectedOpenOn("$3", "$1", "$2", "$4", "$5"),
+			expectedArgs:  []interface{}{90, 30, "2024-05-06", true, true},
+		},
+		{
+			name:          "min rating",
+			filter:        StoreFilter{MinRating: &minRating},
+			expectedWhere: "deleted_at IS NULL AND EXISTS (SELECT 1 FROM reviews r WHERE r.store_id = stores.id AND r.rating >= $1)",
+			expectedArgs:  []interface{}{minRating},
+		},
+		{
+			name:          "visited by anyone",
+			filter:        StoreFilter{Visited: &visited},
+			expectedWhere: "deleted_at IS NULL AND EXISTS (SELECT 1 FROM reviews r WHERE r.store_id = stores.id AND r.is_visited)",
+		},
+		{
+			name:          "visited by nobody",
+			filter:        StoreFilter{Visited: &notVisited},
+			expectedWhere: "deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM reviews r WHERE r.store_id = stores.id AND r.is_visited)",
+		},
+		{
+			name:          "visited by user",
+			filter:        StoreFilter{VisitedBy: &userID},
+			expectedWhere: "deleted_at IS NULL AND EXISTS (SELECT 1 FROM reviews r WHERE r.store_id = stores.id AND r.is_visited AND r.user_id = $1)",
+			expectedArgs:  []interface{}{userID},
+		},
+		{
+			name:          "not visited by user",
+			filter:        StoreFilter{Visited: &notVisited, VisitedBy: &userID},
+			expectedWhere: "deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM reviews r WHERE r.store_id = stores.id AND r.is_visited AND r.user_id = $1)",
+			expectedArgs:  []interface{}{userID},
+		},
+		{
+			name:          "price range",
+			filter:        StoreFilter{MinPrice: &minPrice, MaxPrice: &maxPrice},
+			expectedWhere: "deleted_at IS NULL AND " + averagePrice + " >= $1 AND " + averagePrice + " <= $2",
+			expectedArgs:  []interface{}{minPrice, maxPrice},
+		},
+		{
+			name:          "list",
+			filter:        StoreFilter{ListID: &listID},
+			expectedWhere: "deleted_at IS NULL AND EXISTS (SELECT 1 FROM store_list_entries e WHERE e.store_id = stores.id AND e.list_id = $1)",
+			expectedArgs:  []interface{}{listID},
+		},
+		{
+			name: "all filters",
+			filter: StoreFilter{
+				Name:         "カフェ",
+				Query:        "ラテ",
+				Categories:   []string{"カフェ"},
+				Tags:         []string{"人気", "駅近"},
+				Latitude:     &lat,
+				Longitude:    &lng,
+				Radius:       &radius,
+				BusinessDay:  "friday",
+				BusinessTime: "20:00",
+			},
+			expectedWhere: "deleted_at IS NULL AND name ILIKE $1 AND " + expectedSearch("$2") +
+				" AND categories ?| ARRAY[$3]::text[] AND tags ?& ARRAY[$4,$5]::text[]" +
+				" AND ST_DWithin(location, ST_Point($6, $7)::geography, $8)" +
+				" AND " + expectedOpenAt("$9", "$10", "(EXTRACT(EPOCH FROM $11::time)::integer / 60)", "0"),
+			expectedArgs: []interface{}{"%カフェ%", "ラテ", "カフェ", "人気", "駅近", lng, lat, radius, "friday", "thursday", "20:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := buildStoreCountQuery(&tt.filter)
+			assert.Equal(t, normalizeSQL("SELECT COUNT(*) FROM stores WHERE "+tt.expectedWhere), normalizeSQL(query))
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestBuildStoreListQuery(t *testing.T) {
+	lat, lng, radius := 35.68, 139.76, 500.0
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	id := uuid.New()
+
+	selectFrom := func(sortKey string) string {
+		return "SELECT " + storeColumns + ", rs.avg_rating, rs.review_count, rs.last_visit, " + sortKey + " AS sort_key" +
+			" FROM stores LEFT JOIN LATERAL (SELECT AVG(r.rating)::float8 AS avg_rating, COUNT(*) AS review_count," +
+			" MAX(r.visit_date) FILTER (WHERE r.is_visited) AS last_visit FROM reviews r WHERE r.store_id = stores.id) rs ON true" +
+			" WHERE deleted_at IS NULL"
+	}
+	// Ordering, cursors and the sort key share the KNN distance
+	nearest := func(lng, lat string) string {
+		return fmt.Sprintf("location <-> ST_Point(%s, %s)::geography", lng, lat)
+	}
+
+	tests := []struct {
+		name          string
+		filter        StoreFilter
+		expectedQuery string
+		expectedArgs  []interface{}
+	}{
+		{
+			name:          "newest first without limit",
+			filter:        StoreFilter{},
+			expectedQuery: selectFrom("0::float8") + " ORDER BY created_at DESC, id DESC",
+		},
+		{
+			name:          "limit fetches one more and offset",
+			filter:        StoreFilter{Name: "カフェ", Limit: 20, Offset: 40},
+			expectedQuery: selectFrom("0::float8") + " AND name ILIKE $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3",
+			expectedArgs:  []interface{}{"%カフェ%", 21, 40},
+		},
+		{
+			name: "cursor replaces offset",
+			filter: StoreFilter{
+				Limit:  20,
+				Offset: 40,
+				Cursor: &StoreCursor{Order: StoreOrderCreatedAt, CreatedAt: createdAt, ID: id},
+			},
+			expectedQuery: selectFrom("0::float8") + " AND (created_at, id) < ($1, $2) ORDER BY created_at DESC, id DESC LIMIT $3",
+			expectedArgs:  []interface{}{createdAt, id, 21},
+		},
+		{
+			name: "proximity",
+			filter: StoreFilter{
+				Latitude: &lat, Longitude: &lng, Radius: &radius, OrderByProximity: true, Limit: 10,
+			},
+			expectedQuery: selectFrom(nearest("$4", "$5")) +
+				" AND ST_DWithin(location, ST_Point($1, $2)::geography, $3)" +
+				" ORDER BY " + nearest("$4", "$5") + " ASC, id ASC LIMIT $6",
+			expectedArgs: []interface{}{lng, lat, radius, lng, lat, 11},
+		},
+		{
+			name: "proximity with cursor",
+			filter: StoreFilter{
+				Latitude: &lat, Longitude: &lng, OrderByProximity: true, Limit: 10,
+				Cursor: &StoreCursor{Order: StoreOrderDistance, SortKey: 120.5, CreatedAt: createdAt, ID: id},
+			},
+			expectedQuery: selectFrom(nearest("$1", "$2")) + " AND (" + nearest("$1", "$2") + ", id) > ($3, $4)" +
+				" ORDER BY " + nearest("$1", "$2") + " ASC, id ASC LIMIT $5",
+			expectedArgs: []interface{}{lng, lat, 120.5, id, 11},
+		},
+		{
+			name:          "relevance",
+			filter:        StoreFilter{Query: "ラーメン", Limit: 10},
+			expectedQuery: selectFrom("("+expectedRank("$2")+")::float8") + " AND " + expectedSearch("$1") + " ORDER BY sort_key DESC, created_at DESC, id DESC LIMIT $3",
+			expectedArgs:  []interface{}{"ラーメン", "ラーメン", 11},
+		},
+		{
+			name: "relevance with cursor",
+			filter: StoreFilter{
+				Query: "ラーメン", Limit: 10,
+				Cursor: &StoreCursor{Order: StoreOrderRelevance, SortKey: 2.5, CreatedAt: createdAt, ID: id},
+			},
+			expectedQuery: selectFrom("("+expectedRank("$2")+")::float8") + " AND " + expectedSearch("$1") +
+				" AND ((" + expectedRank("$2") + ")::float8, created_at, id) < ($3, $4, $5)" +
+				" ORDER BY sort_key DESC, created_at DESC, id DESC LIMIT $6",
+			expectedArgs: []interface{}{"ラーメン", "ラーメン", 2.5, createdAt, id, 11},
+		},
+		{
+			name:          "sort overrides relevance",
+			filter:        StoreFilter{Query: "ラーメン", Sort: StoreSortAvgRating, Limit: 10},
+			expectedQuery: selectFrom("COALESCE(rs.avg_rating, 0)") + " AND " + expectedSearch("$1") + " ORDER BY sort_key DESC, id DESC LIMIT $2",
+			expectedArgs:  []interface{}{"ラーメン", 11},
+		},
+		{
+			name: "ascending rating with cursor keeps unrated stores last",
+			filter: StoreFilter{
+				Sort: StoreSortAvgRating, SortAscending: true, Limit: 10,
+				Cursor: &StoreCursor{Order: "avg_rating:asc", SortKey: 3.5, ID: id},
+			},
+			expectedQuery: selectFrom("COALESCE(rs.avg_rating, 6)") + " AND (COALESCE(rs.avg_rating, 6), id) > ($1, $2) ORDER BY sort_key ASC, id ASC LIMIT $3",
+			expectedArgs:  []interface{}{3.5, id, 11},
+		},
+		{
+			name: "review count with cursor",
+			filter: StoreFilter{
+				Sort: StoreSortReviewCount, Limit: 10,
+				Cursor: &StoreCursor{Order: "review_count:desc", SortKey: 4, ID: id},
+			},
+			expectedQuery: selectFrom("rs.review_count::float8") + " AND (rs.review_count::float8, id) < ($1, $2) ORDER BY sort_key DESC, id DESC LIMIT $3",
+			expectedArgs:  []interface{}{4.0, id, 11},
+		},
+		{
+			name:          "review count ascending puts stores without reviews last",
+			filter:        StoreFilter{Sort: StoreSortReviewCount, SortAscending: true},
+			expectedQuery: selectFrom("COALESCE(NULLIF(rs.review_count, 0)::float8, 1e15)") + " ORDER BY sort_key ASC, id ASC",
+		},
+		{
+			name:          "last visit",
+			filter:        StoreFilter{Sort: StoreSortLastVisit},
+			expectedQuery: selectFrom("COALESCE(EXTRACT(EPOCH FROM rs.last_visit)::float8, -1e15)") + " ORDER BY sort_key DESC, id DESC",
+		},
+		{
+			name:          "updated at ascending",
+			filter:        StoreFilter{Sort: StoreSortUpdatedAt, SortAscending: true},
+			expectedQuery: selectFrom("EXTRACT(EPOCH FROM updated_at)::float8") + " ORDER BY sort_key ASC, id ASC",
+		},
+		{
+			name: "name with cursor",
+			filter: StoreFilter{
+				Sort: StoreSortName, SortAscending: true, Limit: 10,
+				Cursor: &StoreCursor{Order: "name:asc", SortText: "喫茶店", ID: id},
+			},
+			expectedQuery: selectFrom("0::float8") + " AND (name, id) > ($1, $2) ORDER BY name ASC, id ASC LIMIT $3",
+			expectedArgs:  []interface{}{"喫茶店", id, 11},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := buildStoreListQuery(&tt.filter)
+			assert.Equal(t, normalizeSQL(tt.expectedQuery), normalizeSQL(query))
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestBuildStoreClusterQuery(t *testing.T) {
+	filter := StoreFilter{
+		Categories:  []string{"カフェ"},
+		BoundingBox: &BoundingBox{MinLongitude: 139.7, MinLatitude: 35.6, MaxLongitude: 139.8, MaxLatitude: 35.7},
+	}
+
+	query, args := buildStoreClusterQuery(&filter, 0.01)
+	normalized := normalizeSQL(query)
+
+	// The filter conditions come first, then the cell size
+	assert.Contains(t, normalized, "ST_SnapToGrid(ST_SetSRID(ST_MakePoint(longitude, latitude), 4326), $6) AS cell FROM stores"+
+		" WHERE deleted_at IS NULL AND categories ?| ARRAY[$1]::text[]"+
+		" AND ST_SetSRID(ST_MakePoint(longitude, latitude), 4326) && ST_MakeEnvelope($2, $3, $4, $5, 4326)")
+	assert.Contains(t, normalized, "LEFT JOIN cell_categories cc ON cc.cell = c.cell ORDER BY c.store_count DESC")
+	assert.Equal(t, []interface{}{"カフェ", 139.7, 35.6, 139.8, 35.7, 0.01}, args)
+}
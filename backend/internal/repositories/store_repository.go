@@ -2,9 +2,12 @@ package repositories
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sukimise/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -17,34 +20,50 @@ func NewStoreRepository(db *sql.DB) *StoreRepository {
 	return &StoreRepository{db: db}
 }
 
+// Create inserts a store and records its creation in the store history
 func (r *StoreRepository) Create(store *models.Store) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
-		INSERT INTO stores (id, name, address, latitude, longitude, categories, business_hours, 
+		INSERT INTO stores (id, name, address, latitude, longitude, categories, business_hours, business_hour_exceptions, 
 						  parking_info, website_url, google_map_url, sns_urls, 
 						  tags, photos, created_by, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW(), NOW())
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW(), NOW())
+		RETURNING created_at, updated_at
 	`
 	store.ID = uuid.New()
-	_, err := r.db.Exec(query,
+	err = tx.QueryRow(query,
 		store.ID, store.Name, store.Address, store.Latitude, store.Longitude,
-		store.Categories, store.BusinessHours, store.ParkingInfo,
+		store.Categories, store.BusinessHours, store.BusinessHourExceptions, store.ParkingInfo,
 		store.WebsiteURL, store.GoogleMapURL, store.SnsUrls, store.Tags,
 		store.Photos, store.CreatedBy,
-	)
-	return err
+	).Scan(&store.CreatedAt, &store.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := insertStoreRevision(tx, store.CreatedBy, models.StoreRevisionActionCreate, nil, store, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (r *StoreRepository) GetByID(id uuid.UUID) (*models.Store, error) {
 	var store models.Store
 	query := `
-		SELECT id, name, address, latitude, longitude, categories, business_hours,
+		SELECT id, name, address, latitude, longitude, categories, business_hours, business_hour_exceptions,
 			   parking_info, website_url, google_map_url, sns_urls,
 			   tags, photos, created_by, created_at, updated_at
-		FROM stores WHERE id = $1
+		FROM stores WHERE id = $1 AND deleted_at IS NULL
 	`
 	err := r.db.QueryRow(query, id).Scan(
 		&store.ID, &store.Name, &store.Address, &store.Latitude, &store.Longitude,
-		&store.Categories, &store.BusinessHours, &store.ParkingInfo,
+		&store.Categories, &store.BusinessHours, &store.BusinessHourExceptions, &store.ParkingInfo,
 		&store.WebsiteURL, &store.GoogleMapURL, &store.SnsUrls, &store.Tags,
 		&store.Photos, &store.CreatedBy, &store.CreatedAt, &store.UpdatedAt,
 	)
@@ -54,8 +73,17 @@ func (r *StoreRepository) GetByID(id uuid.UUID) (*models.Store, error) {
 	return &store, nil
 }
 
+// BoundingBox is a map area in WGS 84 degrees
+type BoundingBox struct {
+	MinLongitude float64
+	MinLatitude  float64
+	MaxLongitude float64
+	MaxLatitude  float64
+}
+
 type StoreFilter struct {
 	Name              string
+	Query             string // Full-text search over name, address, parking info, categories, tags and reviews
 	Categories        []string
 	CategoriesOperator string // "OR" or "AND"
 	Tags              []string
@@ -63,145 +91,226 @@ type StoreFilter struct {
 	Latitude          *float64
 	Longitude         *float64
 	Radius            *float64
+	BoundingBox       *BoundingBox // Stores inside this map area
+	Polygon           string       // Stores inside this GeoJSON Polygon or MultiPolygon
 	BusinessDay       string
 	BusinessTime      string
+	BusinessDate      *time.Time // Date of BusinessDay, so that exceptions and public holidays apply
+	OpenAt            *time.Time // Stores open at this time in its location, including overnight hours
+	LastOrderMargin   int        // With OpenAt, minutes that must remain before the last order
 	OrderByProximity  bool // Order by distance from latitude/longitude
+	MinRating         *float64   // Stores with at least one review rated this or higher
+	Visited           *bool      // Stores with (true) or without (false) a review marked as visited
+	VisitedBy         *uuid.UUID // Restricts Visited to the reviews of this user
+	MinPrice          *int       // Average payment amount of the latest 3 reviews, as shown on the store page
+	MaxPrice          *int
+	ListID            *uuid.UUID // Stores in this list; the caller checks that the list may be seen
 	Limit             int
 	Offset            int
+	Sort              string       // One of the StoreSort* keys, takes precedence over proximity and relevance
+	SortAscending     bool
+	Cursor            *StoreCursor // Keyset pagination: list stores after this one, Offset is ignored
 }
 
+// GetAll returns the stores matching the filter
 func (r *StoreRepository) GetAll(filter *StoreFilter) ([]*models.Store, error) {
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
+	stores, _, err := r.GetPage(filter)
+	return stores, err
+}
 
-	baseQuery := `
-		SELECT id, name, address, latitude, longitude, categories, business_hours,
-			   parking_info, website_url, google_map_url, sns_urls,
-			   tags, photos, created_by, created_at, updated_at
-		FROM stores
-	`
+// GetPage returns the stores matching the filter and, when more stores follow,
+// the cursor for the next page. With filter.Cursor set, the page starts after
+// the cursor instead of at filter.Offset.
+func (r *StoreRepository) GetPage(filter *StoreFilter) ([]*models.Store, *StoreCursor, error) {
+	query, args := buildStoreListQuery(filter)
 
-	if filter.Name != "" {
-		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", argIndex))
-		args = append(args, "%"+filter.Name+"%")
-		argIndex++
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
 	}
+	defer rows.Close()
 
-	if len(filter.Categories) > 0 {
-		placeholders := make([]string, len(filter.Categories))
-		for i, cat := range filter.Categories {
-			placeholders[i] = fmt.Sprintf("$%d", argIndex)
-			args = append(args, cat)
-			argIndex++
-		}
-		
-		// デフォルトはOR、ANDが指定された場合は&&演算子を使用
-		operator := "?|" // OR演算子
-		if filter.CategoriesOperator == "AND" {
-			operator = "?&" // AND演算子
+	var stores []*models.Store
+	var sortKeys []float64
+	for rows.Next() {
+		var store models.Store
+		var summary models.StoreReviewSummary
+		var key float64
+		err := rows.Scan(
+			&store.ID, &store.Name, &store.Address, &store.Latitude, &store.Longitude,
+			&store.Categories, &store.BusinessHours, &store.BusinessHourExceptions, &store.ParkingInfo,
+			&store.WebsiteURL, &store.GoogleMapURL, &store.SnsUrls, &store.Tags,
+			&store.Photos, &store.CreatedBy, &store.CreatedAt, &store.UpdatedAt,
+			&summary.AverageRating, &summary.ReviewCount, &summary.LastVisitDate, &key,
+		)
+		if err != nil {
+			return nil, nil, err
 		}
-		conditions = append(conditions, fmt.Sprintf("categories %s ARRAY[%s]::text[]", operator, strings.Join(placeholders, ",")))
+		store.ReviewSummary = &summary
+		stores = append(stores, &store)
+		sortKeys = append(sortKeys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if filter.Limit <= 0 || len(stores) <= filter.Limit {
+		return stores, nil, nil
+	}
+
+	stores = stores[:filter.Limit]
+	last := stores[filter.Limit-1]
+	next := &StoreCursor{
+		Order:     filter.Order(),
+		SortKey:   sortKeys[filter.Limit-1],
+		CreatedAt: last.CreatedAt,
+		ID:        last.ID,
+	}
+	if filter.Sort == StoreSortName {
+		next.SortText = last.Name
+	}
+	return stores, next, nil
+}
+
+// GetCount returns the total count of stores matching the filter
+func (r *StoreRepository) GetCount(filter *StoreFilter) (int, error) {
+	query, args := buildStoreCountQuery(filter)
+
+	var count int
+	err := r.db.QueryRow(query, args...).Scan(&count)
+	return count, err
+}
+
+// GetClusters groups the stores matching the filter into grid cells of
+// cellSize degrees. Categories of each cluster are sorted by store count.
+func (r *StoreRepository) GetClusters(filter *StoreFilter, cellSize float64) ([]*models.StoreCluster, error) {
+	query, args := buildStoreClusterQuery(filter, cellSize)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	if len(filter.Tags) > 0 {
-		placeholders := make([]string, len(filter.Tags))
-		for i, tag := range filter.Tags {
-			placeholders[i] = fmt.Sprintf("$%d", argIndex)
-			args = append(args, tag)
-			argIndex++
+	var clusters []*models.StoreCluster
+	for rows.Next() {
+		var cluster models.StoreCluster
+		var categories []byte
+		if err := rows.Scan(&cluster.Count, &cluster.Latitude, &cluster.Longitude, &cluster.StoreID, &categories); err != nil {
+			return nil, err
 		}
-		
-		// デフォルトはAND、ORが指定された場合は?|演算子を使用
-		operator := "?&" // AND演算子
-		if filter.TagsOperator == "OR" {
-			operator = "?|" // OR演算子
+
+		var counts map[string]int
+		if err := json.Unmarshal(categories, &counts); err != nil {
+			return nil, err
 		}
-		conditions = append(conditions, fmt.Sprintf("tags %s ARRAY[%s]::text[]", operator, strings.Join(placeholders, ",")))
-	}
-
-	if filter.Latitude != nil && filter.Longitude != nil && filter.Radius != nil {
-		conditions = append(conditions, fmt.Sprintf(`
-			ST_DWithin(
-				ST_Point(longitude, latitude)::geography,
-				ST_Point($%d, $%d)::geography,
-				$%d
-			)
-		`, argIndex, argIndex+1, argIndex+2))
-		args = append(args, *filter.Longitude, *filter.Latitude, *filter.Radius)
-		argIndex += 3
-	}
-
-
-	// 営業時間検索（JSON形式対応）
-	if filter.BusinessDay != "" && filter.BusinessTime != "" {
-		// 両方指定: 指定曜日の指定時間に営業している店
-		conditions = append(conditions, fmt.Sprintf(`
-			(business_hours IS NOT NULL AND
-			 business_hours->$%d->>'is_closed' != 'true' AND
-			 EXISTS (
-			   SELECT 1 FROM jsonb_array_elements(business_hours->$%d->'time_slots') AS slot
-			   WHERE $%d::time >= (slot->>'open_time')::time 
-			     AND $%d::time <= COALESCE(
-			       NULLIF(slot->>'last_order_time', ''),
-			       slot->>'close_time'
-			     )::time
-			 ))`, argIndex, argIndex+1, argIndex+2, argIndex+3))
-		args = append(args, filter.BusinessDay, filter.BusinessDay, filter.BusinessTime, filter.BusinessTime)
-		argIndex += 4
-	} else if filter.BusinessDay != "" {
-		// 営業日のみ指定: その日が休業日ではない店
-		conditions = append(conditions, fmt.Sprintf(`
-			(business_hours IS NULL OR 
-			 business_hours->$%d->>'is_closed' != 'true')`, argIndex))
-		args = append(args, filter.BusinessDay)
-		argIndex++
-	} else if filter.BusinessTime != "" {
-		// 営業時間のみ指定: 1週間のうちどこか1日でもその時間に営業している店
-		timeConditions := []string{}
-		for _, day := range []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"} {
-			timeConditions = append(timeConditions, fmt.Sprintf(`
-				(business_hours->$%d->>'is_closed' != 'true' AND
-				 EXISTS (
-				   SELECT 1 FROM jsonb_array_elements(business_hours->$%d->'time_slots') AS slot
-				   WHERE $%d::time >= (slot->>'open_time')::time 
-				     AND $%d::time <= COALESCE(
-				       NULLIF(slot->>'last_order_time', ''),
-				       slot->>'close_time'
-				     )::time
-				 ))`, argIndex, argIndex+1, argIndex+2, argIndex+3))
-			args = append(args, day, day, filter.BusinessTime, filter.BusinessTime)
-			argIndex += 4
+		cluster.Categories = make([]models.StoreClusterCategory, 0, len(counts))
+		for name, count := range counts {
+			cluster.Categories = append(cluster.Categories, models.StoreClusterCategory{Name: name, Count: count})
 		}
-		conditions = append(conditions, "("+strings.Join(timeConditions, " OR ")+")")
+		sort.Slice(cluster.Categories, func(i, j int) bool {
+			a, b := cluster.Categories[i], cluster.Categories[j]
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			return a.Name < b.Name
+		})
+
+		clusters = append(clusters, &cluster)
 	}
 
-	query := baseQuery
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	return clusters, rows.Err()
+}
+
+// Update saves a store and records the changed fields in the store history.
+// store.UpdatedAt must be the value the changes were based on; if the store
+// has been updated since, ErrVersionConflict is returned.
+func (r *StoreRepository) Update(store *models.Store, actorID uuid.UUID) error {
+	return r.update(store, actorID, models.StoreRevisionActionUpdate, nil)
+}
+
+// Rollback saves a store from the snapshot of an earlier revision, regardless of its current version
+func (r *StoreRepository) Rollback(store *models.Store, revisionID, actorID uuid.UUID) error {
+	return r.update(store, actorID, models.StoreRevisionActionRollback, &revisionID)
+}
+
+func (r *StoreRepository) update(store *models.Store, actorID uuid.UUID, action string, sourceRevisionID *uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
-	// Add ordering
-	if filter.OrderByProximity && filter.Latitude != nil && filter.Longitude != nil {
-		query += fmt.Sprintf(" ORDER BY ST_Distance(ST_Point(longitude, latitude)::geography, ST_Point($%d, $%d)::geography) ASC", argIndex, argIndex+1)
-		args = append(args, *filter.Longitude, *filter.Latitude)
-		argIndex += 2
-	} else {
-		query += " ORDER BY created_at DESC"
+	before, err := getStoreForUpdate(tx, store.ID, false)
+	if err != nil {
+		return err
+	}
+	if action == models.StoreRevisionActionUpdate && !before.UpdatedAt.Equal(store.UpdatedAt) {
+		return ErrVersionConflict
 	}
 
-	if filter.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT $%d", argIndex)
-		args = append(args, filter.Limit)
-		argIndex++
+	query := `
+		UPDATE stores SET 
+			name = $2, address = $3, latitude = $4, longitude = $5, categories = $6,
+			business_hours = $7, business_hour_exceptions = $8, parking_info = $9, website_url = $10,
+			google_map_url = $11, sns_urls = $12, tags = $13, photos = $14, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING updated_at
+	`
+	err = tx.QueryRow(query,
+		store.ID, store.Name, store.Address, store.Latitude, store.Longitude,
+		store.Categories, store.BusinessHours, store.BusinessHourExceptions, store.ParkingInfo,
+		store.WebsiteURL, store.GoogleMapURL, store.SnsUrls, store.Tags, store.Photos,
+	).Scan(&store.UpdatedAt)
+	if err != nil {
+		return err
 	}
 
-	if filter.Offset > 0 {
-		query += fmt.Sprintf(" OFFSET $%d", argIndex)
-		args = append(args, filter.Offset)
+	if err := insertStoreRevision(tx, actorID, action, before, store, sourceRevisionID); err != nil {
+		return err
 	}
 
-	rows, err := r.db.Query(query, args...)
+	return tx.Commit()
+}
+
+// Delete moves a store to the trash. Its reviews are kept until the store is purged.
+func (r *StoreRepository) Delete(id, actorID uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getStoreForUpdate(tx, id, false)
+	if err != nil {
+		return err
+	}
+
+	after := *before
+	query := `UPDATE stores SET deleted_at = NOW() WHERE id = $1 RETURNING deleted_at`
+	if err := tx.QueryRow(query, id).Scan(&after.DeletedAt); err != nil {
+		return err
+	}
+
+	if err := insertStoreRevision(tx, actorID, models.StoreRevisionActionDelete, before, &after, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetDeleted returns stores in the trash, most recently deleted first
+func (r *StoreRepository) GetDeleted(limit, offset int) ([]*models.Store, error) {
+	query := `
+		SELECT id, name, address, latitude, longitude, categories, business_hours, business_hour_exceptions,
+			   parking_info, website_url, google_map_url, sns_urls,
+			   tags, photos, created_by, created_at, updated_at, deleted_at
+		FROM stores
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT $1 OFFSET $2
+	`
+	rows, err := r.db.Query(query, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -212,9 +321,9 @@ func (r *StoreRepository) GetAll(filter *StoreFilter) ([]*models.Store, error) {
 		var store models.Store
 		err := rows.Scan(
 			&store.ID, &store.Name, &store.Address, &store.Latitude, &store.Longitude,
-			&store.Categories, &store.BusinessHours, &store.ParkingInfo,
+			&store.Categories, &store.BusinessHours, &store.BusinessHourExceptions, &store.ParkingInfo,
 			&store.WebsiteURL, &store.GoogleMapURL, &store.SnsUrls, &store.Tags,
-			&store.Photos, &store.CreatedBy, &store.CreatedAt, &store.UpdatedAt,
+			&store.Photos, &store.CreatedBy, &store.CreatedAt, &store.UpdatedAt, &store.DeletedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -225,139 +334,54 @@ func (r *StoreRepository) GetAll(filter *StoreFilter) ([]*models.Store, error) {
 	return stores, nil
 }
 
-// GetCount returns the total count of stores matching the filter
-func (r *StoreRepository) GetCount(filter *StoreFilter) (int, error) {
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
-
-	baseQuery := `SELECT COUNT(*) FROM stores`
+// GetDeletedCount returns the number of stores in the trash
+func (r *StoreRepository) GetDeletedCount() (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM stores WHERE deleted_at IS NOT NULL`).Scan(&count)
+	return count, err
+}
 
-	if filter.Name != "" {
-		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", argIndex))
-		args = append(args, "%"+filter.Name+"%")
-		argIndex++
+// Restore moves a store out of the trash. Returns sql.ErrNoRows if the store is not in the trash.
+func (r *StoreRepository) Restore(id, actorID uuid.UUID) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
 	}
+	defer tx.Rollback()
 
-	if len(filter.Categories) > 0 {
-		placeholders := make([]string, len(filter.Categories))
-		for i, cat := range filter.Categories {
-			placeholders[i] = fmt.Sprintf("$%d", argIndex)
-			args = append(args, cat)
-			argIndex++
-		}
-		
-		operator := "?|" // OR演算子
-		if filter.CategoriesOperator == "AND" {
-			operator = "?&" // AND演算子
-		}
-		conditions = append(conditions, fmt.Sprintf("categories %s ARRAY[%s]::text[]", operator, strings.Join(placeholders, ",")))
+	before, err := getStoreForUpdate(tx, id, true)
+	if err != nil {
+		return err
 	}
 
-	if len(filter.Tags) > 0 {
-		placeholders := make([]string, len(filter.Tags))
-		for i, tag := range filter.Tags {
-			placeholders[i] = fmt.Sprintf("$%d", argIndex)
-			args = append(args, tag)
-			argIndex++
-		}
-		
-		operator := "?&" // AND演算子
-		if filter.TagsOperator == "OR" {
-			operator = "?|" // OR演算子
-		}
-		conditions = append(conditions, fmt.Sprintf("tags %s ARRAY[%s]::text[]", operator, strings.Join(placeholders, ",")))
-	}
-
-	// 近隣検索（緯度・経度・半径指定）
-	if filter.Latitude != nil && filter.Longitude != nil && filter.Radius != nil {
-		conditions = append(conditions, fmt.Sprintf(`
-			(6371 * acos(cos(radians($%d)) * cos(radians(latitude)) * 
-			cos(radians(longitude) - radians($%d)) + sin(radians($%d)) * 
-			sin(radians(latitude)))) <= $%d`, argIndex, argIndex+1, argIndex, argIndex+2))
-		args = append(args, *filter.Latitude, *filter.Longitude, *filter.Latitude, *filter.Radius)
-		argIndex += 3
-	}
-
-	// 営業時間検索（JSON形式対応）
-	if filter.BusinessDay != "" && filter.BusinessTime != "" {
-		// 両方指定: 指定曜日の指定時間に営業している店
-		conditions = append(conditions, fmt.Sprintf(`
-			(business_hours IS NOT NULL AND
-			 business_hours->$%d->>'is_closed' != 'true' AND
-			 EXISTS (
-			   SELECT 1 FROM jsonb_array_elements(business_hours->$%d->'time_slots') AS slot
-			   WHERE $%d::time >= (slot->>'open_time')::time 
-			     AND $%d::time <= COALESCE(
-			       NULLIF(slot->>'last_order_time', ''),
-			       slot->>'close_time'
-			     )::time
-			 ))`, argIndex, argIndex+1, argIndex+2, argIndex+3))
-		args = append(args, filter.BusinessDay, filter.BusinessDay, filter.BusinessTime, filter.BusinessTime)
-		argIndex += 4
-	} else if filter.BusinessDay != "" {
-		// 営業日のみ指定: その日が休業日ではない店
-		conditions = append(conditions, fmt.Sprintf(`
-			(business_hours IS NULL OR 
-			 business_hours->$%d->>'is_closed' != 'true')`, argIndex))
-		args = append(args, filter.BusinessDay)
-		argIndex++
-	} else if filter.BusinessTime != "" {
-		// 営業時間のみ指定: 1週間のうちどこか1日でもその時間に営業している店
-		timeConditions := []string{}
-		for _, day := range []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"} {
-			timeConditions = append(timeConditions, fmt.Sprintf(`
-				(business_hours->$%d->>'is_closed' != 'true' AND
-				 EXISTS (
-				   SELECT 1 FROM jsonb_array_elements(business_hours->$%d->'time_slots') AS slot
-				   WHERE $%d::time >= (slot->>'open_time')::time 
-				     AND $%d::time <= COALESCE(
-				       NULLIF(slot->>'last_order_time', ''),
-				       slot->>'close_time'
-					     )::time
-				 ))`, argIndex, argIndex+1, argIndex+2, argIndex+3))
-			args = append(args, day, day, filter.BusinessTime, filter.BusinessTime)
-			argIndex += 4
-		}
-		conditions = append(conditions, "("+strings.Join(timeConditions, " OR ")+")")
+	if _, err := tx.Exec(`UPDATE stores SET deleted_at = NULL WHERE id = $1`, id); err != nil {
+		return err
 	}
 
-	if len(conditions) > 0 {
-		baseQuery += " WHERE " + strings.Join(conditions, " AND ")
+	after := *before
+	after.DeletedAt = nil
+	if err := insertStoreRevision(tx, actorID, models.StoreRevisionActionRestore, before, &after, nil); err != nil {
+		return err
 	}
 
-	var count int
-	err := r.db.QueryRow(baseQuery, args...).Scan(&count)
-	return count, err
+	return tx.Commit()
 }
 
-func (r *StoreRepository) Update(store *models.Store) error {
-	query := `
-		UPDATE stores SET 
-			name = $2, address = $3, latitude = $4, longitude = $5, categories = $6,
-			business_hours = $7, parking_info = $8, website_url = $9,
-			google_map_url = $10, sns_urls = $11, tags = $12, photos = $13, updated_at = NOW()
-		WHERE id = $1
-	`
-	_, err := r.db.Exec(query,
-		store.ID, store.Name, store.Address, store.Latitude, store.Longitude,
-		store.Categories, store.BusinessHours, store.ParkingInfo,
-		store.WebsiteURL, store.GoogleMapURL, store.SnsUrls, store.Tags, store.Photos,
-	)
-	return err
-}
-
-func (r *StoreRepository) Delete(id uuid.UUID) error {
-	query := `DELETE FROM stores WHERE id = $1`
-	_, err := r.db.Exec(query, id)
-	return err
+// PurgeDeleted permanently removes stores that were moved to the trash before the given time
+func (r *StoreRepository) PurgeDeleted(before time.Time) (int64, error) {
+	query := `DELETE FROM stores WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+	result, err := r.db.Exec(query, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 func (r *StoreRepository) GetAllCategories() ([]string, error) {
 	query := `
 		SELECT DISTINCT jsonb_array_elements_text(categories) as category 
 		FROM stores 
-		WHERE categories IS NOT NULL AND jsonb_array_length(categories) > 0
+		WHERE deleted_at IS NULL AND categories IS NOT NULL AND jsonb_array_length(categories) > 0
 		ORDER BY category
 	`
 	rows, err := r.db.Query(query)
@@ -382,7 +406,7 @@ func (r *StoreRepository) GetAllTags() ([]string, error) {
 	query := `
 		SELECT DISTINCT jsonb_array_elements_text(tags) as tag 
 		FROM stores 
-		WHERE tags IS NOT NULL AND jsonb_array_length(tags) > 0
+		WHERE deleted_at IS NULL AND tags IS NOT NULL AND jsonb_array_length(tags) > 0
 		ORDER BY tag
 	`
 	rows, err := r.db.Query(query)
@@ -404,29 +428,40 @@ func (r *StoreRepository) GetAllTags() ([]string, error) {
 }
 
 // FindDuplicateByLocationAndName finds duplicate stores based on:
-// 1. Location proximity (within 50 meters using PostGIS)
-// 2. Exact name match
-// Both conditions must be met for a store to be considered duplicate
-func (r *StoreRepository) FindDuplicateByLocationAndName(name string, latitude, longitude float64) (*models.Store, error) {
+// 1. Location proximity (within policy.Radius meters using PostGIS)
+// 2. Name match according to policy.NameMatch
+// Stores in the trash are ignored.
+// Both conditions must be met for a store to be considered duplicate.
+// With fuzzy matching the most similar name wins, otherwise the oldest store.
+func (r *StoreRepository) FindDuplicateByLocationAndName(name string, latitude, longitude float64, policy models.DuplicatePolicy) (*models.Store, error) {
+	args := []interface{}{name, longitude, latitude, policy.Radius}
+	nameCondition := "name = $1"
+	orderBy := "created_at ASC"
+	switch policy.NameMatch {
+	case models.DuplicateNameNormalized:
+		nameCondition = "replace(normalize_search_text(name), ' ', '') = replace(normalize_search_text($1), ' ', '')"
+	case models.DuplicateNameFuzzy:
+		nameCondition = "similarity(normalize_search_text(name), normalize_search_text($1)) >= $5"
+		orderBy = "similarity(normalize_search_text(name), normalize_search_text($1)) DESC, created_at ASC"
+		args = append(args, policy.MinSimilarity)
+	}
+
 	query := `
-		SELECT id, name, address, latitude, longitude, categories, business_hours,
+		SELECT id, name, address, latitude, longitude, categories, business_hours, business_hour_exceptions,
 			   parking_info, website_url, google_map_url, sns_urls,
 			   tags, photos, created_by, created_at, updated_at
 		FROM stores 
-		WHERE name = $1
-		  AND ST_DWithin(
-		        ST_Point(longitude, latitude)::geography,
-		        ST_Point($2, $3)::geography,
-		        50
-		      )
-		ORDER BY created_at ASC
+		WHERE ` + nameCondition + `
+		  AND deleted_at IS NULL
+		  AND ST_DWithin(location, ST_Point($2, $3)::geography, $4)
+		ORDER BY ` + orderBy + `
 		LIMIT 1
 	`
 	
 	var store models.Store
-	err := r.db.QueryRow(query, name, longitude, latitude).Scan(
+	err := r.db.QueryRow(query, args...).Scan(
 		&store.ID, &store.Name, &store.Address, &store.Latitude, &store.Longitude,
-		&store.Categories, &store.BusinessHours, &store.ParkingInfo,
+		&store.Categories, &store.BusinessHours, &store.BusinessHourExceptions, &store.ParkingInfo,
 		&store.WebsiteURL, &store.GoogleMapURL, &store.SnsUrls, &store.Tags,
 		&store.Photos, &store.CreatedBy, &store.CreatedAt, &store.UpdatedAt,
 	)
@@ -442,3 +477,81 @@ func (r *StoreRepository) FindDuplicateByLocationAndName(name string, latitude,
 	return &store, nil
 }
 
+
+// BulkApply applies one operation to many stores in a single transaction and
+// returns a result per store, in the order of ids. check is called with each
+// store before and after the change; a non-nil result skips the store and is
+// reported instead. Any database error rolls back the whole operation.
+func (r *StoreRepository) BulkApply(ids []uuid.UUID, operation models.StoreBulkOperation, actorID uuid.UUID, check func(before, after *models.Store) *models.StoreBulkResult) ([]*models.StoreBulkResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock all rows up front in a fixed order so that concurrent bulk operations cannot deadlock
+	if len(ids) > 0 {
+		placeholders := make([]string, len(ids))
+		args := make([]interface{}, len(ids))
+		for i, id := range ids {
+			placeholders[i] = fmt.Sprintf("$%d", i+1)
+			args[i] = id
+		}
+		query := fmt.Sprintf(`SELECT id FROM stores WHERE id IN (%s) ORDER BY id FOR UPDATE`, strings.Join(placeholders, ","))
+		if _, err := tx.Exec(query, args...); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]*models.StoreBulkResult, 0, len(ids))
+	for _, id := range ids {
+		before, err := getStoreForUpdate(tx, id, false)
+		if err == sql.ErrNoRows {
+			results = append(results, &models.StoreBulkResult{StoreID: id, Status: models.BulkStatusNotFound})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		after := *before
+		if operation.Operation == models.BulkOperationDelete {
+			if result := check(before, &after); result != nil {
+				results = append(results, result)
+				continue
+			}
+			if err := tx.QueryRow(`UPDATE stores SET deleted_at = NOW() WHERE id = $1 RETURNING deleted_at`, id).Scan(&after.DeletedAt); err != nil {
+				return nil, err
+			}
+			if err := insertStoreRevision(tx, actorID, models.StoreRevisionActionDelete, before, &after, nil); err != nil {
+				return nil, err
+			}
+			results = append(results, &models.StoreBulkResult{StoreID: id, Status: models.BulkStatusDeleted})
+			continue
+		}
+
+		changed := operation.Apply(&after)
+		if result := check(before, &after); result != nil {
+			results = append(results, result)
+			continue
+		}
+		if !changed {
+			results = append(results, &models.StoreBulkResult{StoreID: id, Status: models.BulkStatusUnchanged})
+			continue
+		}
+
+		query := `UPDATE stores SET categories = $2, tags = $3, updated_at = NOW() WHERE id = $1 RETURNING updated_at`
+		if err := tx.QueryRow(query, id, after.Categories, after.Tags).Scan(&after.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if err := insertStoreRevision(tx, actorID, models.StoreRevisionActionUpdate, before, &after, nil); err != nil {
+			return nil, err
+		}
+		results = append(results, &models.StoreBulkResult{StoreID: id, Status: models.BulkStatusUpdated})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
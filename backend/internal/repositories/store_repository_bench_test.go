@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sukimise/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+)
+
+// benchmarkStoreCount is the number of stores the geo benchmarks run against
+const benchmarkStoreCount = 100000
+
+// BenchmarkStoreRepository_Geo measures radius, proximity, nearest and
+// duplicate searches against benchmarkStoreCount stores spread over Tokyo. It needs a
+// migrated database in BENCHMARK_DATABASE_URL and removes its stores afterwards:
+//
+//	BENCHMARK_DATABASE_URL=postgres://... go test -run '^$' -bench Geo ./internal/repositories
+func BenchmarkStoreRepository_Geo(b *testing.B) {
+	dbURL := os.Getenv("BENCHMARK_DATABASE_URL")
+	if dbURL == "" {
+		b.Skip("BENCHMARK_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close() })
+
+	seedBenchmarkStores(b, db)
+
+	repo := NewStoreRepository(db)
+	lat, lng := 35.681, 139.767 // 東京駅
+
+	for _, radius := range []float64{500, 2000, 10000} {
+		b.Run(fmt.Sprintf("radius_%.0fm", radius), func(b *testing.B) {
+			filter := &StoreFilter{Latitude: &lat, Longitude: &lng, Radius: &radius, Limit: 20}
+			for i := 0; i < b.N; i++ {
+				if _, _, err := repo.GetPage(filter); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("radius_count_%.0fm", radius), func(b *testing.B) {
+			filter := &StoreFilter{Latitude: &lat, Longitude: &lng, Radius: &radius}
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.GetCount(filter); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("proximity_%.0fm", radius), func(b *testing.B) {
+			filter := &StoreFilter{Latitude: &lat, Longitude: &lng, Radius: &radius, OrderByProximity: true, Limit: 20}
+			for i := 0; i < b.N; i++ {
+				if _, _, err := repo.GetPage(filter); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+
+	// Nearest stores without a radius rely on the KNN index scan
+	b.Run("nearest", func(b *testing.B) {
+		filter := &StoreFilter{Latitude: &lat, Longitude: &lng, OrderByProximity: true, Limit: 20}
+		for i := 0; i < b.N; i++ {
+			if _, _, err := repo.GetPage(filter); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	for _, policy := range []models.DuplicatePolicy{
+		models.DefaultDuplicatePolicy,
+		{Radius: 50, NameMatch: models.DuplicateNameNormalized},
+		{Radius: 50, NameMatch: models.DuplicateNameFuzzy, MinSimilarity: 0.5},
+	} {
+		policy := policy
+		b.Run("duplicate/"+policy.NameMatch, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.FindDuplicateByLocationAndName("Benchmark 1", lat, lng, policy); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// seedBenchmarkStores inserts the benchmark stores, created by a new user,
+// and deletes them together with the user when the benchmark ends
+func seedBenchmarkStores(b *testing.B, db *sql.DB) {
+	b.Helper()
+
+	userID := uuid.New()
+	_, err := db.Exec(`INSERT INTO users (id, username, email, password, role) VALUES ($1, $2, $3, '', 'editor')`,
+		userID, "benchmark-"+userID.String(), "benchmark-"+userID.String()+"@example.com")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() {
+		// Deleting the user cascades to its stores
+		if _, err := db.Exec(`DELETE FROM users WHERE id = $1`, userID); err != nil {
+			b.Error(err)
+		}
+	})
+
+	_, err = db.Exec(`
+		INSERT INTO stores (name, address, latitude, longitude, created_by)
+		SELECT 'Benchmark ' || i, '東京都', 35.5 + random() * 0.4, 139.5 + random() * 0.5, $1
+		FROM generate_series(1, $2::integer) AS i
+	`, userID, benchmarkStoreCount)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if _, err := db.Exec(`ANALYZE stores`); err != nil {
+		b.Fatal(err)
+	}
+}
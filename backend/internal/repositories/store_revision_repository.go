@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"database/sql"
+	"encoding/json"
+	"sukimise/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Store revisions are written by StoreRepository in the same transaction as
+// the change they record, so their queries live alongside it.
+
+// GetRevisions returns the change history of a store, newest first.
+// Snapshots are not loaded; use GetRevision for a single revision.
+func (r *StoreRepository) GetRevisions(storeID uuid.UUID, limit, offset int) ([]*models.StoreRevision, error) {
+	query := `
+		SELECT sr.id, sr.store_id, sr.user_id, COALESCE(u.username, ''), sr.action,
+			   sr.changes, sr.source_revision_id, sr.created_at
+		FROM store_revisions sr
+		LEFT JOIN users u ON sr.user_id = u.id
+		WHERE sr.store_id = $1
+		ORDER BY sr.created_at DESC, sr.id
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(query, storeID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []*models.StoreRevision
+	for rows.Next() {
+		var revision models.StoreRevision
+		err := rows.Scan(
+			&revision.ID, &revision.StoreID, &revision.UserID, &revision.Username, &revision.Action,
+			&revision.Changes, &revision.SourceRevisionID, &revision.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+// GetRevisionCount returns the number of revisions of a store
+func (r *StoreRepository) GetRevisionCount(storeID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM store_revisions WHERE store_id = $1`, storeID).Scan(&count)
+	return count, err
+}
+
+// GetRevision returns a single revision including the store snapshot
+func (r *StoreRepository) GetRevision(id uuid.UUID) (*models.StoreRevision, error) {
+	query := `
+		SELECT sr.id, sr.store_id, sr.user_id, COALESCE(u.username, ''), sr.action,
+			   sr.changes, sr.snapshot, sr.source_revision_id, sr.created_at
+		FROM store_revisions sr
+		LEFT JOIN users u ON sr.user_id = u.id
+		WHERE sr.id = $1
+	`
+	var revision models.StoreRevision
+	var snapshot []byte
+	err := r.db.QueryRow(query, id).Scan(
+		&revision.ID, &revision.StoreID, &revision.UserID, &revision.Username, &revision.Action,
+		&revision.Changes, &snapshot, &revision.SourceRevisionID, &revision.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	revision.Snapshot = &models.Store{}
+	if err := json.Unmarshal(snapshot, revision.Snapshot); err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// getStoreForUpdate locks a store row for the rest of the transaction and returns its current state
+func getStoreForUpdate(tx *sql.Tx, id uuid.UUID, deleted bool) (*models.Store, error) {
+	query := `
+		SELECT id, name, address, latitude, longitude, categories, business_hours, business_hour_exceptions,
+			   parking_info, website_url, google_map_url, sns_urls,
+			   tags, photos, created_by, created_at, updated_at, deleted_at
+		FROM stores WHERE id = $1 AND (deleted_at IS NOT NULL) = $2
+		FOR UPDATE
+	`
+	var store models.Store
+	err := tx.QueryRow(query, id, deleted).Scan(
+		&store.ID, &store.Name, &store.Address, &store.Latitude, &store.Longitude,
+		&store.Categories, &store.BusinessHours, &store.BusinessHourExceptions, &store.ParkingInfo,
+		&store.WebsiteURL, &store.GoogleMapURL, &store.SnsUrls, &store.Tags,
+		&store.Photos, &store.CreatedBy, &store.CreatedAt, &store.UpdatedAt, &store.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &store, nil
+}
+
+// insertStoreRevision records the difference between two states of a store.
+// Updates that change nothing are not recorded.
+func insertStoreRevision(tx *sql.Tx, actorID uuid.UUID, action string, before, after *models.Store, sourceRevisionID *uuid.UUID) error {
+	changes, err := models.DiffStores(before, after)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 && action == models.StoreRevisionActionUpdate {
+		return nil
+	}
+
+	snapshot, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO store_revisions (id, store_id, user_id, action, changes, snapshot, source_revision_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+	`, uuid.New(), after.ID, actorID, action, changes, snapshot, sourceRevisionID)
+	return err
+}
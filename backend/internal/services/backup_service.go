@@ -0,0 +1,132 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sukimise/internal/models"
+	"sukimise/internal/repositories"
+	"time"
+)
+
+// maxBackupLineSize limits a single NDJSON record (stores with many photos can be large)
+const maxBackupLineSize = 4 << 20
+
+type BackupService struct {
+	backupRepo *repositories.BackupRepository
+}
+
+func NewBackupService(backupRepo *repositories.BackupRepository) *BackupService {
+	return &BackupService{backupRepo: backupRepo}
+}
+
+// WriteBackup streams all data as NDJSON, starting with a meta record
+func (s *BackupService) WriteBackup(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+
+	emit := func(recordType string, data interface{}) error {
+		return encoder.Encode(struct {
+			Type string      `json:"type"`
+			Data interface{} `json:"data"`
+		}{Type: recordType, Data: data})
+	}
+
+	meta := models.BackupMeta{Version: models.BackupFormatVersion, CreatedAt: time.Now()}
+	if err := emit(models.BackupTypeMeta, meta); err != nil {
+		return err
+	}
+
+	return s.backupRepo.Export(emit)
+}
+
+// Restore restores a parsed backup in a single transaction
+func (s *BackupService) Restore(data *models.BackupData) (*models.RestoreResult, error) {
+	return s.backupRepo.Restore(data)
+}
+
+// ParseBackup reads an NDJSON backup written by WriteBackup
+func ParseBackup(r io.Reader) (*models.BackupData, error) {
+	data := &models.BackupData{}
+	hasMeta := false
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxBackupLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := scanner.Bytes()
+		if len(raw) == 0 {
+			continue
+		}
+
+		var record models.BackupRecord
+		if err := json.Unmarshal(raw, &record); err != nil {
+			return nil, fmt.Errorf("line %d: invalid JSON: %v", line, err)
+		}
+
+		if !hasMeta && record.Type != models.BackupTypeMeta {
+			return nil, fmt.Errorf("line %d: backup must start with a meta record", line)
+		}
+
+		var err error
+		switch record.Type {
+		case models.BackupTypeMeta:
+			if hasMeta {
+				return nil, fmt.Errorf("line %d: duplicate meta record", line)
+			}
+			hasMeta = true
+			err = json.Unmarshal(record.Data, &data.Meta)
+			if err == nil && data.Meta.Version != models.BackupFormatVersion {
+				return nil, fmt.Errorf("unsupported backup version: %d", data.Meta.Version)
+			}
+		case models.BackupTypeUser:
+			var user models.User
+			err = json.Unmarshal(record.Data, &user)
+			data.Users = append(data.Users, &user)
+		case models.BackupTypeStore:
+			var store models.Store
+			err = json.Unmarshal(record.Data, &store)
+			data.Stores = append(data.Stores, &store)
+		case models.BackupTypeReview:
+			var review models.Review
+			err = json.Unmarshal(record.Data, &review)
+			data.Reviews = append(data.Reviews, &review)
+		case models.BackupTypeMenuItem:
+			var menuItem models.MenuItem
+			err = json.Unmarshal(record.Data, &menuItem)
+			data.MenuItems = append(data.MenuItems, &menuItem)
+		case models.BackupTypeCategoryCustomization:
+			var customization models.CategoryCustomization
+			err = json.Unmarshal(record.Data, &customization)
+			data.CategoryCustomizations = append(data.CategoryCustomizations, &customization)
+		case models.BackupTypeStoreList:
+			var list models.StoreList
+			err = json.Unmarshal(record.Data, &list)
+			data.StoreLists = append(data.StoreLists, &list)
+		case models.BackupTypeStoreListEntry:
+			var entry models.BackupStoreListEntry
+			err = json.Unmarshal(record.Data, &entry)
+			data.StoreListEntries = append(data.StoreListEntries, &entry)
+		case models.BackupTypeShareLink:
+			var link models.ShareLink
+			err = json.Unmarshal(record.Data, &link)
+			data.ShareLinks = append(data.ShareLinks, &link)
+		default:
+			return nil, fmt.Errorf("line %d: unknown record type %q", line, record.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid %s record: %v", line, record.Type, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if !hasMeta {
+		return nil, fmt.Errorf("backup is empty")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"strings"
+	"sukimise/internal/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBackup(t *testing.T) {
+	t.Run("all record types", func(t *testing.T) {
+		input := strings.Join([]string{
+			`{"type":"meta","data":{"version":1,"created_at":"2024-01-01T00:00:00Z"}}`,
+			`{"type":"user","data":{"id":"11111111-1111-1111-1111-111111111111","username":"admin","email":"admin@sukimise.local","role":"admin"}}`,
+			`{"type":"store","data":{"id":"22222222-2222-2222-2222-222222222222","name":"テスト店","latitude":35.6,"longitude":139.7,"tags":["人気"],"created_by":"11111111-1111-1111-1111-111111111111"}}`,
+			``,
+			`{"type":"review","data":{"id":"33333333-3333-3333-3333-333333333333","store_id":"22222222-2222-2222-2222-222222222222","user_id":"11111111-1111-1111-1111-111111111111","rating":5}}`,
+			`{"type":"menu_item","data":{"id":"44444444-4444-4444-4444-444444444444","review_id":"33333333-3333-3333-3333-333333333333","name":"ラーメン"}}`,
+			`{"type":"category_customization","data":{"id":"55555555-5555-5555-5555-555555555555","category_name":"カフェ","icon":"☕","color":"#8B4513"}}`,
+			`{"type":"store_list","data":{"id":"66666666-6666-6666-6666-666666666666","owner_id":"11111111-1111-1111-1111-111111111111","title":"デート候補","visibility":"private"}}`,
+			`{"type":"store_list_entry","data":{"list_id":"66666666-6666-6666-6666-666666666666","store_id":"22222222-2222-2222-2222-222222222222","position":1,"note":"夜景がきれい"}}`,
+			`{"type":"share_link","data":{"id":"77777777-7777-7777-7777-777777777777","scope":"list","list_id":"66666666-6666-6666-6666-666666666666","created_by":"11111111-1111-1111-1111-111111111111","expires_at":"2024-02-01T00:00:00Z"}}`,
+		}, "\n")
+
+		data, err := ParseBackup(strings.NewReader(input))
+		assert.NoError(t, err)
+		assert.Equal(t, models.BackupFormatVersion, data.Meta.Version)
+		assert.Len(t, data.Users, 1)
+		assert.Equal(t, "admin", data.Users[0].Username)
+		assert.Len(t, data.Stores, 1)
+		assert.Equal(t, models.StringArray{"人気"}, data.Stores[0].Tags)
+		assert.Len(t, data.Reviews, 1)
+		assert.Equal(t, 5, data.Reviews[0].Rating)
+		assert.Len(t, data.MenuItems, 1)
+		assert.Len(t, data.CategoryCustomizations, 1)
+		assert.Len(t, data.StoreLists, 1)
+		assert.Equal(t, "デート候補", data.StoreLists[0].Title)
+		assert.Len(t, data.StoreListEntries, 1)
+		assert.Equal(t, data.StoreLists[0].ID, data.StoreListEntries[0].ListID)
+		assert.Equal(t, "夜景がきれい", data.StoreListEntries[0].Note)
+		assert.Len(t, data.ShareLinks, 1)
+		assert.Equal(t, &data.StoreLists[0].ID, data.ShareLinks[0].ListID)
+	})
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "empty", input: ""},
+		{name: "missing meta", input: `{"type":"user","data":{}}`},
+		{name: "unsupported version", input: `{"type":"meta","data":{"version":99}}`},
+		{name: "duplicate meta", input: "{\"type\":\"meta\",\"data\":{\"version\":1}}\n{\"type\":\"meta\",\"data\":{\"version\":1}}"},
+		{name: "unknown type", input: "{\"type\":\"meta\",\"data\":{\"version\":1}}\n{\"type\":\"session\",\"data\":{}}"},
+		{name: "invalid JSON", input: "{\"type\":\"meta\",\"data\":{\"version\":1}}\nnot json"},
+		{name: "invalid record", input: "{\"type\":\"meta\",\"data\":{\"version\":1}}\n{\"type\":\"store\",\"data\":{\"id\":\"not-a-uuid\"}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ParseBackup(strings.NewReader(tt.input))
+			assert.Error(t, err)
+			assert.Nil(t, data)
+		})
+	}
+}
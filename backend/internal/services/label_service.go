@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"sukimise/internal/models"
+	"sukimise/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+type LabelService struct {
+	labelRepo *repositories.LabelRepository
+}
+
+func NewLabelService(labelRepo *repositories.LabelRepository) *LabelService {
+	return &LabelService{labelRepo: labelRepo}
+}
+
+// MergeLabels renames the tags or categories in from to to on every store.
+// from and to should be normalized with NormalizeLabelMerge.
+// With dryRun nothing is changed and the result shows what would be affected.
+func (s *LabelService) MergeLabels(kind string, from []string, to string, actorID uuid.UUID, dryRun bool) (*models.LabelMergeResult, error) {
+	return s.labelRepo.Merge(kind, from, to, actorID, dryRun)
+}
+
+// NormalizeLabelMerge trims the target label and drops blank and duplicate source
+// labels as well as the target itself, then checks that something is left to merge.
+// Source labels are not trimmed, so that labels with stray whitespace can be fixed.
+func NormalizeLabelMerge(from []string, to string) ([]string, string, error) {
+	to = strings.TrimSpace(to)
+	if to == "" {
+		return nil, "", errors.New("target label is required")
+	}
+
+	var sources []string
+	for _, label := range from {
+		if strings.TrimSpace(label) == "" || label == to {
+			continue
+		}
+		duplicate := false
+		for _, source := range sources {
+			if source == label {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			sources = append(sources, label)
+		}
+	}
+
+	if len(sources) == 0 {
+		return nil, "", errors.New("at least one label different from the target is required")
+	}
+	return sources, to, nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeLabelMerge(t *testing.T) {
+	tests := []struct {
+		name         string
+		from         []string
+		to           string
+		expectedFrom []string
+		expectedTo   string
+		wantErr      bool
+	}{
+		{
+			name:         "rename",
+			from:         []string{"らーめん"},
+			to:           " ラーメン ",
+			expectedFrom: []string{"らーめん"},
+			expectedTo:   "ラーメン",
+		},
+		{
+			name:         "merge keeps whitespace in sources and drops target and duplicates",
+			from:         []string{"らーめん", "ラーメン ", "ラーメン", "らーめん", "  "},
+			to:           "ラーメン",
+			expectedFrom: []string{"らーめん", "ラーメン "},
+			expectedTo:   "ラーメン",
+		},
+		{name: "empty target", from: []string{"a"}, to: "  ", wantErr: true},
+		{name: "only target", from: []string{"a"}, to: "a", wantErr: true},
+		{name: "no sources", from: nil, to: "a", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := NormalizeLabelMerge(tt.from, tt.to)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFrom, from)
+			assert.Equal(t, tt.expectedTo, to)
+		})
+	}
+}
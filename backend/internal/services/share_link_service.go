@@ -0,0 +1,127 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"sukimise/internal/models"
+	"sukimise/internal/repositories"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidShareToken is returned for share tokens that are malformed, not
+// signed by this server, expired, revoked or of a deleted link
+var ErrInvalidShareToken = errors.New("invalid or expired share link")
+
+// ErrNotShareLinkCreator is returned when someone other than the creator or an admin revokes a link
+var ErrNotShareLinkCreator = errors.New("only the creator can revoke a share link")
+
+type ShareLinkService struct {
+	shareLinkRepo *repositories.ShareLinkRepository
+	secret        []byte
+}
+
+func NewShareLinkService(shareLinkRepo *repositories.ShareLinkRepository, secret string) *ShareLinkService {
+	return &ShareLinkService{shareLinkRepo: shareLinkRepo, secret: []byte(secret)}
+}
+
+// CreateShareLink saves the link and sets its token
+func (s *ShareLinkService) CreateShareLink(link *models.ShareLink) error {
+	if err := s.shareLinkRepo.Create(link); err != nil {
+		return err
+	}
+	link.Token = s.Token(link)
+	return nil
+}
+
+// GetShareLinks returns the links created by createdBy, or all links when it is nil, with their tokens
+func (s *ShareLinkService) GetShareLinks(createdBy *uuid.UUID, limit, offset int) ([]*models.ShareLink, int, error) {
+	links, err := s.shareLinkRepo.GetAll(createdBy, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, link := range links {
+		link.Token = s.Token(link)
+	}
+
+	count, err := s.shareLinkRepo.GetCount(createdBy)
+	if err != nil {
+		return nil, 0, err
+	}
+	return links, count, nil
+}
+
+// RevokeShareLink ends access through a link created by actorID, or by anyone for admins
+func (s *ShareLinkService) RevokeShareLink(id, actorID uuid.UUID, isAdmin bool) (*models.ShareLink, error) {
+	link, err := s.shareLinkRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if link.CreatedBy != actorID && !isAdmin {
+		return nil, ErrNotShareLinkCreator
+	}
+	return s.shareLinkRepo.Revoke(id)
+}
+
+// OpenShareLink checks a share token and counts the access, returning the link it grants
+func (s *ShareLinkService) OpenShareLink(token string, now time.Time) (*models.ShareLink, error) {
+	id, expiresAt, err := s.parseToken(token)
+	if err != nil || !now.Before(expiresAt) {
+		return nil, ErrInvalidShareToken
+	}
+
+	link, err := s.shareLinkRepo.RecordAccess(id)
+	if err == sql.ErrNoRows {
+		return nil, ErrInvalidShareToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// Token returns the token of a link: its ID and expiry, signed with HMAC-SHA256
+// so that tokens cannot be guessed or extended without the server secret
+func (s *ShareLinkService) Token(link *models.ShareLink) string {
+	payload := make([]byte, 24)
+	copy(payload, link.ID[:])
+	binary.BigEndian.PutUint64(payload[16:], uint64(link.ExpiresAt.Unix()))
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload))
+}
+
+// parseToken verifies the signature of a token and returns the link ID and expiry it carries
+func (s *ShareLinkService) parseToken(token string) (uuid.UUID, time.Time, error) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, time.Time{}, ErrInvalidShareToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != 24 {
+		return uuid.Nil, time.Time{}, ErrInvalidShareToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, s.sign(payload)) {
+		return uuid.Nil, time.Time{}, ErrInvalidShareToken
+	}
+
+	id, err := uuid.FromBytes(payload[:16])
+	if err != nil {
+		return uuid.Nil, time.Time{}, ErrInvalidShareToken
+	}
+	expiresAt := time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0)
+	return id, expiresAt, nil
+}
+
+func (s *ShareLinkService) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("share-link:"))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"strings"
+	"sukimise/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareLinkToken(t *testing.T) {
+	s := NewShareLinkService(nil, "test-secret")
+	link := &models.ShareLink{ID: uuid.New(), ExpiresAt: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)}
+	token := s.Token(link)
+
+	id, expiresAt, err := s.parseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, link.ID, id)
+	assert.True(t, link.ExpiresAt.Equal(expiresAt))
+	assert.NotContains(t, token, "/", "tokens are URL-safe")
+
+	payload, signature, _ := strings.Cut(token, ".")
+	other := NewShareLinkService(nil, "other-secret")
+	longer := s.Token(&models.ShareLink{ID: link.ID, ExpiresAt: link.ExpiresAt.AddDate(1, 0, 0)})
+	longerPayload, _, _ := strings.Cut(longer, ".")
+
+	invalid := map[string]string{
+		"other secret":      other.Token(link),
+		"extended expiry":   longerPayload + "." + signature,
+		"missing signature": payload,
+		"bad encoding":      payload + ".***",
+		"short payload":     "AAAA." + signature,
+		"empty":             "",
+	}
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := s.parseToken(token)
+			assert.ErrorIs(t, err, ErrInvalidShareToken)
+		})
+	}
+}
+
+func TestOpenShareLink_RejectsWithoutLookup(t *testing.T) {
+	// The repository is nil: tokens failing these checks never reach the database
+	s := NewShareLinkService(nil, "test-secret")
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	expired := s.Token(&models.ShareLink{ID: uuid.New(), ExpiresAt: now.Add(-time.Minute)})
+
+	for _, token := range []string{expired, "not-a-token", NewShareLinkService(nil, "other").Token(&models.ShareLink{ID: uuid.New(), ExpiresAt: now.Add(time.Hour)})} {
+		_, err := s.OpenShareLink(token, now)
+		assert.ErrorIs(t, err, ErrInvalidShareToken)
+	}
+}
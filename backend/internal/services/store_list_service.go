@@ -0,0 +1,119 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"sukimise/internal/models"
+	"sukimise/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotListOwner is returned when someone other than the owner changes a list
+var ErrNotListOwner = errors.New("only the owner can change a list")
+
+type StoreListService struct {
+	storeListRepo *repositories.StoreListRepository
+}
+
+func NewStoreListService(storeListRepo *repositories.StoreListRepository) *StoreListService {
+	return &StoreListService{storeListRepo: storeListRepo}
+}
+
+// GetLists returns the lists matching the filter that filter.ViewerID may see
+func (s *StoreListService) GetLists(filter *repositories.StoreListFilter) ([]*models.StoreList, error) {
+	return s.storeListRepo.GetAll(filter)
+}
+
+// CheckListVisible returns sql.ErrNoRows unless the list exists and viewerID
+// with viewerRole may see it, so that private lists cannot be told apart from
+// missing ones
+func (s *StoreListService) CheckListVisible(id uuid.UUID, viewerID *uuid.UUID, viewerRole string) error {
+	_, err := s.getVisibleList(id, viewerID, viewerRole)
+	return err
+}
+
+// CheckListOwned returns nil if actorID owns the list, sql.ErrNoRows if the
+// actor may not see it and ErrNotListOwner if the actor may only see it
+func (s *StoreListService) CheckListOwned(id, actorID uuid.UUID, actorRole string) error {
+	_, err := s.getOwnedList(id, actorID, actorRole)
+	return err
+}
+
+// GetList returns a list with its entries, or sql.ErrNoRows when viewerID with
+// viewerRole may not see it
+func (s *StoreListService) GetList(id uuid.UUID, viewerID *uuid.UUID, viewerRole string) (*models.StoreList, error) {
+	list, err := s.getVisibleList(id, viewerID, viewerRole)
+	if err != nil {
+		return nil, err
+	}
+
+	list.Entries, err = s.storeListRepo.GetEntries(id)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *StoreListService) CreateList(list *models.StoreList) error {
+	return s.storeListRepo.Create(list)
+}
+
+// UpdateList saves the fields of list, and its entries unless they are nil
+func (s *StoreListService) UpdateList(list *models.StoreList, actorID uuid.UUID, actorRole string) error {
+	existing, err := s.getOwnedList(list.ID, actorID, actorRole)
+	if err != nil {
+		return err
+	}
+
+	list.OwnerID = existing.OwnerID
+	list.OwnerUsername = existing.OwnerUsername
+	list.CreatedAt = existing.CreatedAt
+	return s.storeListRepo.Update(list)
+}
+
+func (s *StoreListService) DeleteList(id, actorID uuid.UUID, actorRole string) error {
+	if _, err := s.getOwnedList(id, actorID, actorRole); err != nil {
+		return err
+	}
+	return s.storeListRepo.Delete(id)
+}
+
+// AddListEntry adds a store to the end of a list, or updates its note when the list has it already
+func (s *StoreListService) AddListEntry(listID uuid.UUID, entry *models.StoreListEntry, actorID uuid.UUID, actorRole string) error {
+	if _, err := s.getOwnedList(listID, actorID, actorRole); err != nil {
+		return err
+	}
+	return s.storeListRepo.AddEntry(listID, entry)
+}
+
+func (s *StoreListService) RemoveListEntry(listID, storeID, actorID uuid.UUID, actorRole string) error {
+	if _, err := s.getOwnedList(listID, actorID, actorRole); err != nil {
+		return err
+	}
+	return s.storeListRepo.RemoveEntry(listID, storeID)
+}
+
+func (s *StoreListService) getVisibleList(id uuid.UUID, viewerID *uuid.UUID, viewerRole string) (*models.StoreList, error) {
+	list, err := s.storeListRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !list.CanView(viewerID, viewerRole) {
+		return nil, sql.ErrNoRows
+	}
+	return list, nil
+}
+
+// getOwnedList returns the list if actorID owns it. Lists the actor may not
+// see are reported as missing, lists the actor may only see as ErrNotListOwner.
+func (s *StoreListService) getOwnedList(id, actorID uuid.UUID, actorRole string) (*models.StoreList, error) {
+	list, err := s.getVisibleList(id, &actorID, actorRole)
+	if err != nil {
+		return nil, err
+	}
+	if list.OwnerID != actorID {
+		return nil, ErrNotListOwner
+	}
+	return list, nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sukimise/internal/models"
+	"sukimise/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrMergeSameStore is returned when merging a store into itself
+var ErrMergeSameStore = errors.New("cannot merge a store into itself")
+
+type StoreMergeService struct {
+	storeMergeRepo repositories.StoreMergeRepositoryInterface
+}
+
+func NewStoreMergeService(storeMergeRepo repositories.StoreMergeRepositoryInterface) *StoreMergeService {
+	return &StoreMergeService{storeMergeRepo: storeMergeRepo}
+}
+
+// FindDuplicateCandidates returns pairs of stores within radius meters whose
+// names are at least minSimilarity alike, or that share the same spot
+func (s *StoreMergeService) FindDuplicateCandidates(radius, minSimilarity, sameSpotDistance float64, limit int) ([]*models.StoreDuplicateCandidate, error) {
+	return s.storeMergeRepo.FindCandidates(radius, minSimilarity, sameSpotDistance, limit)
+}
+
+// MergeStores merges the source store into the target store in a single transaction.
+// The target gets the labels, photos and SNS URLs of the source, the reviews
+// and list entries of the source are moved to the target, and the source is
+// moved to the trash. When a user reviewed both stores, the source review is
+// merged into the target review and deleted after moving its menu items.
+// With dryRun nothing is changed and the result shows the merged store.
+// sql.ErrNoRows is returned when either store does not exist or is in the trash.
+func (s *StoreMergeService) MergeStores(targetID, sourceID, actorID uuid.UUID, dryRun bool) (*models.StoreMergeResult, error) {
+	if targetID == sourceID {
+		return nil, ErrMergeSameStore
+	}
+
+	tx, err := s.storeMergeRepo.BeginMerge(targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	target, source := tx.Stores()
+	merged := *target
+	models.MergeStoreFields(&merged, source)
+	if err := tx.UpdateTarget(&merged, actorID); err != nil {
+		return nil, err
+	}
+
+	conflicts, err := tx.GetReviewConflicts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to move reviews: %w", err)
+	}
+	for _, conflict := range conflicts {
+		kept, other := conflict[0], conflict[1]
+		models.MergeReviews(kept, other)
+		if err := tx.UpdateReview(kept); err != nil {
+			return nil, fmt.Errorf("failed to merge review %s: %w", other.ID, err)
+		}
+		if err := tx.MoveMenuItems(other.ID, kept.ID); err != nil {
+			return nil, fmt.Errorf("failed to merge review %s: %w", other.ID, err)
+		}
+		if err := tx.DeleteReview(other.ID); err != nil {
+			return nil, fmt.Errorf("failed to merge review %s: %w", other.ID, err)
+		}
+	}
+
+	moved, err := tx.MoveReviews()
+	if err != nil {
+		return nil, fmt.Errorf("failed to move reviews: %w", err)
+	}
+
+	listEntries, err := tx.MergeListEntries()
+	if err != nil {
+		return nil, fmt.Errorf("failed to move list entries: %w", err)
+	}
+
+	if err := tx.TrashSource(actorID); err != nil {
+		return nil, err
+	}
+
+	result := &models.StoreMergeResult{
+		Store:         &merged,
+		MergedStoreID: sourceID,
+		MovedReviews:  moved,
+		MergedReviews: len(conflicts),
+		ListEntries:   listEntries,
+		DryRun:        dryRun,
+	}
+	if dryRun {
+		return result, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"sukimise/internal/models"
+	mocks "sukimise/internal/repositories/mocks"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func mergeTestStores() (target, source *models.Store) {
+	target = &models.Store{
+		ID:         uuid.New(),
+		Name:       "喫茶すきま",
+		Categories: models.StringArray{"カフェ"},
+		Tags:       models.StringArray{"静か"},
+		Photos:     models.StringArray{},
+		SnsUrls:    models.StringArray{},
+	}
+	source = &models.Store{
+		ID:         uuid.New(),
+		Name:       "喫茶 すきま",
+		Categories: models.StringArray{"カフェ", "喫茶店"},
+		Tags:       models.StringArray{"モーニング"},
+		Photos:     models.StringArray{"photo.jpg"},
+		SnsUrls:    models.StringArray{},
+		WebsiteURL: "https://example.com",
+	}
+	return target, source
+}
+
+func TestStoreMergeService_MergeStores(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockStoreMergeRepositoryInterface(ctrl)
+	mockTx := mocks.NewMockStoreMergeTxInterface(ctrl)
+	service := NewStoreMergeService(mockRepo)
+
+	target, source := mergeTestStores()
+	actorID := uuid.New()
+	userID := uuid.New()
+	keptComment, otherComment := "普通", "最高"
+	kept := &models.Review{ID: uuid.New(), UserID: userID, Rating: 3, Comment: &keptComment, UpdatedAt: time.Now().Add(-time.Hour)}
+	other := &models.Review{ID: uuid.New(), UserID: userID, Rating: 5, Comment: &otherComment, UpdatedAt: time.Now()}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().BeginMerge(target.ID, source.ID).Return(mockTx, nil),
+		mockTx.EXPECT().Stores().Return(target, source),
+		mockTx.EXPECT().UpdateTarget(gomock.Any(), actorID).DoAndReturn(func(merged *models.Store, _ uuid.UUID) error {
+			assert.Equal(t, target.ID, merged.ID)
+			assert.Equal(t, models.StringArray{"カフェ", "喫茶店"}, merged.Categories)
+			assert.Equal(t, models.StringArray{"静か", "モーニング"}, merged.Tags)
+			assert.Equal(t, "https://example.com", merged.WebsiteURL)
+			return nil
+		}),
+		mockTx.EXPECT().GetReviewConflicts().Return([][2]*models.Review{{kept, other}}, nil),
+		mockTx.EXPECT().UpdateReview(kept).DoAndReturn(func(review *models.Review) error {
+			// The rating of the newer review wins and both comments are kept
+			assert.Equal(t, 5, review.Rating)
+			assert.Equal(t, "普通\n\n最高", *review.Comment)
+			return nil
+		}),
+		mockTx.EXPECT().MoveMenuItems(other.ID, kept.ID).Return(nil),
+		mockTx.EXPECT().DeleteReview(other.ID).Return(nil),
+		mockTx.EXPECT().MoveReviews().Return(2, nil),
+		mockTx.EXPECT().MergeListEntries().Return(3, nil),
+		mockTx.EXPECT().TrashSource(actorID).Return(nil),
+		mockTx.EXPECT().Commit().Return(nil),
+		mockTx.EXPECT().Rollback().Return(nil),
+	)
+
+	result, err := service.MergeStores(target.ID, source.ID, actorID, false)
+	require.NoError(t, err)
+	assert.Equal(t, target.ID, result.Store.ID)
+	assert.Equal(t, source.ID, result.MergedStoreID)
+	assert.Equal(t, 2, result.MovedReviews)
+	assert.Equal(t, 1, result.MergedReviews)
+	assert.Equal(t, 3, result.ListEntries)
+	assert.False(t, result.DryRun)
+
+	// The locked target is left as it was read, for its revision
+	assert.Equal(t, models.StringArray{"カフェ"}, target.Categories)
+}
+
+func TestStoreMergeService_MergeStores_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockStoreMergeRepositoryInterface(ctrl)
+	mockTx := mocks.NewMockStoreMergeTxInterface(ctrl)
+	service := NewStoreMergeService(mockRepo)
+
+	target, source := mergeTestStores()
+	actorID := uuid.New()
+
+	mockRepo.EXPECT().BeginMerge(target.ID, source.ID).Return(mockTx, nil)
+	mockTx.EXPECT().Stores().Return(target, source)
+	mockTx.EXPECT().UpdateTarget(gomock.Any(), actorID).Return(nil)
+	mockTx.EXPECT().GetReviewConflicts().Return(nil, nil)
+	mockTx.EXPECT().MoveReviews().Return(1, nil)
+	mockTx.EXPECT().MergeListEntries().Return(0, nil)
+	mockTx.EXPECT().TrashSource(actorID).Return(nil)
+	// A dry run is rolled back and never committed
+	mockTx.EXPECT().Commit().Times(0)
+	mockTx.EXPECT().Rollback().Return(nil)
+
+	result, err := service.MergeStores(target.ID, source.ID, actorID, true)
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.MovedReviews)
+	assert.Equal(t, 0, result.MergedReviews)
+	assert.Equal(t, models.StringArray{"カフェ", "喫茶店"}, result.Store.Categories)
+}
+
+func TestStoreMergeService_MergeStores_Errors(t *testing.T) {
+	target, source := mergeTestStores()
+	actorID := uuid.New()
+
+	t.Run("same store", func(t *testing.T) {
+		service := NewStoreMergeService(nil)
+
+		_, err := service.MergeStores(target.ID, target.ID, actorID, false)
+		assert.Equal(t, ErrMergeSameStore, err)
+	})
+
+	t.Run("store not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockStoreMergeRepositoryInterface(ctrl)
+		service := NewStoreMergeService(mockRepo)
+		mockRepo.EXPECT().BeginMerge(target.ID, source.ID).Return(nil, sql.ErrNoRows)
+
+		_, err := service.MergeStores(target.ID, source.ID, actorID, false)
+		assert.Equal(t, sql.ErrNoRows, err)
+	})
+
+	t.Run("failed step rolls back", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockRepo := mocks.NewMockStoreMergeRepositoryInterface(ctrl)
+		mockTx := mocks.NewMockStoreMergeTxInterface(ctrl)
+		service := NewStoreMergeService(mockRepo)
+
+		mockRepo.EXPECT().BeginMerge(target.ID, source.ID).Return(mockTx, nil)
+		mockTx.EXPECT().Stores().Return(target, source)
+		mockTx.EXPECT().UpdateTarget(gomock.Any(), actorID).Return(nil)
+		mockTx.EXPECT().GetReviewConflicts().Return(nil, nil)
+		mockTx.EXPECT().MoveReviews().Return(0, nil)
+		mockTx.EXPECT().MergeListEntries().Return(0, errors.New("database error"))
+		mockTx.EXPECT().Commit().Times(0)
+		mockTx.EXPECT().Rollback().Return(nil)
+
+		_, err := service.MergeStores(target.ID, source.ID, actorID, false)
+		assert.EqualError(t, err, "failed to move list entries: database error")
+	})
+}
@@ -1,18 +1,28 @@
 package services
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sukimise/internal/constants"
 	"sukimise/internal/models"
 	"sukimise/internal/repositories"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrRevisionNotRollbackable is returned when rolling back to a revision that deleted the store
+var ErrRevisionNotRollbackable = errors.New("cannot roll back to a delete revision")
+
 type StoreService struct {
-	storeRepo repositories.StoreRepositoryInterface
+	storeRepo       repositories.StoreRepositoryInterface
+	duplicatePolicy models.DuplicatePolicy
 }
 
-func NewStoreService(storeRepo repositories.StoreRepositoryInterface) *StoreService {
-	return &StoreService{storeRepo: storeRepo}
+func NewStoreService(storeRepo repositories.StoreRepositoryInterface, duplicatePolicy models.DuplicatePolicy) *StoreService {
+	return &StoreService{storeRepo: storeRepo, duplicatePolicy: duplicatePolicy}
 }
 
 func (s *StoreService) CreateStore(store *models.Store) error {
@@ -35,6 +45,65 @@ func (s *StoreService) GetStores(filter *repositories.StoreFilter) ([]*models.St
 	return s.storeRepo.GetAll(filter)
 }
 
+// GetStoresPage returns a page of stores and the cursor of the next page, if any
+func (s *StoreService) GetStoresPage(filter *repositories.StoreFilter) ([]*models.Store, *repositories.StoreCursor, error) {
+	if filter == nil {
+		filter = &repositories.StoreFilter{}
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = 20
+	}
+
+	return s.storeRepo.GetPage(filter)
+}
+
+// GetAllStores returns every store selected by filter in its order, ignoring
+// its limit and offset, by reading pages of constants.MaxLimit stores
+func (s *StoreService) GetAllStores(filter *repositories.StoreFilter) ([]*models.Store, error) {
+	pageFilter := repositories.StoreFilter{}
+	if filter != nil {
+		pageFilter = *filter
+	}
+	pageFilter.Limit = constants.MaxLimit
+	pageFilter.Offset = 0
+	pageFilter.Cursor = nil
+
+	var stores []*models.Store
+	for {
+		page, next, err := s.storeRepo.GetPage(&pageFilter)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, page...)
+		if next == nil {
+			return stores, nil
+		}
+		pageFilter.Cursor = next
+	}
+}
+
+// StoreClusterCellSize returns the width in degrees of the cluster cells at a map zoom level
+func StoreClusterCellSize(zoom int) float64 {
+	return 360 / math.Pow(2, float64(zoom)) / constants.StoreClusterCellsPerTile
+}
+
+// GetStoreClusters groups the stores matching the filter for a map at zoom,
+// keeping only the most common categories of each cluster
+func (s *StoreService) GetStoreClusters(filter *repositories.StoreFilter, zoom int) ([]*models.StoreCluster, error) {
+	clusters, err := s.storeRepo.GetClusters(filter, StoreClusterCellSize(zoom))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cluster := range clusters {
+		if len(cluster.Categories) > constants.StoreClusterTopCategories {
+			cluster.Categories = cluster.Categories[:constants.StoreClusterTopCategories]
+		}
+	}
+	return clusters, nil
+}
+
 func (s *StoreService) GetStoresCount(filter *repositories.StoreFilter) (int, error) {
 	if filter == nil {
 		filter = &repositories.StoreFilter{}
@@ -43,12 +112,80 @@ func (s *StoreService) GetStoresCount(filter *repositories.StoreFilter) (int, er
 	return s.storeRepo.GetCount(filter)
 }
 
-func (s *StoreService) UpdateStore(store *models.Store) error {
-	return s.storeRepo.Update(store)
+// UpdateStore saves a store and records the change made by actorID in its history
+func (s *StoreService) UpdateStore(store *models.Store, actorID uuid.UUID) error {
+	return s.storeRepo.Update(store, actorID)
+}
+
+// GetStoreIDs returns the IDs of at most limit stores matching the filter
+func (s *StoreService) GetStoreIDs(filter *repositories.StoreFilter, limit int) ([]uuid.UUID, error) {
+	idFilter := *filter
+	idFilter.Limit = limit
+	idFilter.Offset = 0
+
+	stores, err := s.storeRepo.GetAll(&idFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, len(stores))
+	for i, store := range stores {
+		ids[i] = store.ID
+	}
+	return ids, nil
+}
+
+// BulkUpdateStores applies one operation to many stores in a single transaction.
+// Stores the actor did not create are skipped unless the actor is an admin,
+// as are stores that would exceed the category or tag limits.
+func (s *StoreService) BulkUpdateStores(ids []uuid.UUID, operation models.StoreBulkOperation, actorID uuid.UUID, isAdmin bool) ([]*models.StoreBulkResult, error) {
+	uniqueIDs := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+
+	return s.storeRepo.BulkApply(uniqueIDs, operation, actorID, func(before, after *models.Store) *models.StoreBulkResult {
+		if before.CreatedBy != actorID && !isAdmin {
+			return &models.StoreBulkResult{StoreID: before.ID, Status: models.BulkStatusForbidden, Error: "You can only update stores you created"}
+		}
+		if len(after.Categories) > constants.MaxStoreCategories {
+			return &models.StoreBulkResult{StoreID: before.ID, Status: models.BulkStatusFailed, Error: fmt.Sprintf("Maximum %d categories allowed", constants.MaxStoreCategories)}
+		}
+		if len(after.Tags) > constants.MaxStoreTags {
+			return &models.StoreBulkResult{StoreID: before.ID, Status: models.BulkStatusFailed, Error: fmt.Sprintf("Maximum %d tags allowed", constants.MaxStoreTags)}
+		}
+		return nil
+	})
+}
+
+// DeleteStore moves a store to the trash
+func (s *StoreService) DeleteStore(id, actorID uuid.UUID) error {
+	return s.storeRepo.Delete(id, actorID)
+}
+
+func (s *StoreService) GetDeletedStores(limit, offset int) ([]*models.Store, error) {
+	if limit == 0 {
+		limit = 20
+	}
+	return s.storeRepo.GetDeleted(limit, offset)
 }
 
-func (s *StoreService) DeleteStore(id uuid.UUID) error {
-	return s.storeRepo.Delete(id)
+func (s *StoreService) GetDeletedStoresCount() (int, error) {
+	return s.storeRepo.GetDeletedCount()
+}
+
+// RestoreStore moves a store out of the trash
+func (s *StoreService) RestoreStore(id, actorID uuid.UUID) error {
+	return s.storeRepo.Restore(id, actorID)
+}
+
+// PurgeDeletedStores permanently removes stores that have been in the trash longer than the retention period
+func (s *StoreService) PurgeDeletedStores(retention time.Duration) (int64, error) {
+	return s.storeRepo.PurgeDeleted(time.Now().Add(-retention))
 }
 
 func (s *StoreService) SearchStores(name string, categories, tags []string, lat, lng, radius *float64, limit, offset int) ([]*models.Store, error) {
@@ -78,8 +215,61 @@ func (s *StoreService) GetAllTags() ([]string, error) {
 	return s.storeRepo.GetAllTags()
 }
 
-// CheckForDuplicate checks if a store with the same name and location (within 50m) already exists
+// CheckForDuplicate checks if a store with a matching name already exists nearby,
+// as decided by the duplicate policy
 func (s *StoreService) CheckForDuplicate(name string, latitude, longitude float64) (*models.Store, error) {
-	return s.storeRepo.FindDuplicateByLocationAndName(name, latitude, longitude)
+	return s.storeRepo.FindDuplicateByLocationAndName(name, latitude, longitude, s.duplicatePolicy)
+}
+
+// DuplicatePolicy returns the policy used by CheckForDuplicate
+func (s *StoreService) DuplicatePolicy() models.DuplicatePolicy {
+	return s.duplicatePolicy
+}
+
+// ImportStore creates the store unless CheckForDuplicate finds it, in which case
+// the existing store is returned. On a dry run nothing is created.
+func (s *StoreService) ImportStore(store *models.Store, dryRun bool) (*models.Store, error) {
+	duplicate, err := s.CheckForDuplicate(store.Name, store.Latitude, store.Longitude)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for duplicates: %w", err)
+	}
+	if duplicate != nil || dryRun {
+		return duplicate, nil
+	}
+	return nil, s.storeRepo.Create(store)
+}
+
+// GetStoreHistory returns the change history of a store, newest first
+func (s *StoreService) GetStoreHistory(storeID uuid.UUID, limit, offset int) ([]*models.StoreRevision, error) {
+	if limit == 0 {
+		limit = 20
+	}
+	return s.storeRepo.GetRevisions(storeID, limit, offset)
+}
+
+func (s *StoreService) GetStoreHistoryCount(storeID uuid.UUID) (int, error) {
+	return s.storeRepo.GetRevisionCount(storeID)
 }
 
+// RollbackStore restores a store to its state right after the given revision.
+// Returns sql.ErrNoRows if the revision does not belong to the store.
+func (s *StoreService) RollbackStore(storeID, revisionID, actorID uuid.UUID) (*models.Store, error) {
+	revision, err := s.storeRepo.GetRevision(revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if revision.StoreID != storeID {
+		return nil, sql.ErrNoRows
+	}
+	if revision.Action == models.StoreRevisionActionDelete || revision.Snapshot == nil {
+		return nil, ErrRevisionNotRollbackable
+	}
+
+	store := *revision.Snapshot
+	store.ID = storeID
+	if err := s.storeRepo.Rollback(&store, revisionID, actorID); err != nil {
+		return nil, err
+	}
+
+	return s.storeRepo.GetByID(storeID)
+}
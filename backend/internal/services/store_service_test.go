@@ -1,11 +1,13 @@
 package services
 
 import (
+	"database/sql"
 	"errors"
 	"sukimise/internal/models"
 	"sukimise/internal/repositories"
 	mocks "sukimise/internal/repositories/mocks"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -17,7 +19,7 @@ func TestStoreService_CreateStore(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockStoreRepositoryInterface(ctrl)
-	service := NewStoreService(mockRepo)
+	service := NewStoreService(mockRepo, models.DefaultDuplicatePolicy)
 
 	store := &models.Store{
 		Name:    "Test Store",
@@ -121,6 +123,56 @@ func TestStoreService_GetStores(t *testing.T) {
 	})
 }
 
+func TestStoreService_GetStoresPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockStoreRepositoryInterface(ctrl)
+	service := &StoreService{storeRepo: mockRepo}
+
+	expectedStores := []*models.Store{{ID: uuid.New(), Name: "Store 1"}}
+	cursor := &repositories.StoreCursor{Order: repositories.StoreOrderCreatedAt, ID: uuid.New()}
+
+	t.Run("zero limit sets default", func(t *testing.T) {
+		filter := &repositories.StoreFilter{Cursor: cursor}
+		expectedFilter := &repositories.StoreFilter{Cursor: cursor, Limit: 20}
+		next := &repositories.StoreCursor{Order: repositories.StoreOrderCreatedAt, ID: expectedStores[0].ID}
+		mockRepo.EXPECT().GetPage(expectedFilter).Return(expectedStores, next, nil)
+
+		stores, nextCursor, err := service.GetStoresPage(filter)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedStores, stores)
+		assert.Equal(t, next, nextCursor)
+	})
+}
+
+func TestStoreClusterCellSize(t *testing.T) {
+	// A 256px tile spans 360 degrees at zoom 0 and is split into 4 cells
+	assert.Equal(t, 90.0, StoreClusterCellSize(0))
+	assert.InDelta(t, 0.02197, StoreClusterCellSize(12), 0.00001)
+}
+
+func TestStoreService_GetStoreClusters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockStoreRepositoryInterface(ctrl)
+	service := &StoreService{storeRepo: mockRepo}
+
+	filter := &repositories.StoreFilter{BoundingBox: &repositories.BoundingBox{MinLongitude: 139, MinLatitude: 35, MaxLongitude: 140, MaxLatitude: 36}}
+	clusters := []*models.StoreCluster{
+		{Count: 5, Categories: []models.StoreClusterCategory{{Name: "カフェ", Count: 3}, {Name: "ラーメン", Count: 2}, {Name: "和食", Count: 1}, {Name: "バー", Count: 1}}},
+		{Count: 1, Categories: []models.StoreClusterCategory{{Name: "カフェ", Count: 1}}},
+	}
+	mockRepo.EXPECT().GetClusters(filter, StoreClusterCellSize(10)).Return(clusters, nil)
+
+	result, err := service.GetStoreClusters(filter, 10)
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, []models.StoreClusterCategory{{Name: "カフェ", Count: 3}, {Name: "ラーメン", Count: 2}, {Name: "和食", Count: 1}}, result[0].Categories)
+	assert.Len(t, result[1].Categories, 1)
+}
+
 func TestStoreService_UpdateStore(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -133,18 +185,19 @@ func TestStoreService_UpdateStore(t *testing.T) {
 		Name:    "Updated Store",
 		Address: "Updated Address",
 	}
+	actorID := uuid.New()
 
 	t.Run("success", func(t *testing.T) {
-		mockRepo.EXPECT().Update(store).Return(nil)
+		mockRepo.EXPECT().Update(store, actorID).Return(nil)
 
-		err := service.UpdateStore(store)
+		err := service.UpdateStore(store, actorID)
 		assert.NoError(t, err)
 	})
 
 	t.Run("repository error", func(t *testing.T) {
-		mockRepo.EXPECT().Update(store).Return(errors.New("update failed"))
+		mockRepo.EXPECT().Update(store, actorID).Return(errors.New("update failed"))
 
-		err := service.UpdateStore(store)
+		err := service.UpdateStore(store, actorID)
 		assert.Error(t, err)
 		assert.Equal(t, "update failed", err.Error())
 	})
@@ -158,18 +211,19 @@ func TestStoreService_DeleteStore(t *testing.T) {
 	service := &StoreService{storeRepo: mockRepo}
 
 	storeID := uuid.New()
+	actorID := uuid.New()
 
 	t.Run("success", func(t *testing.T) {
-		mockRepo.EXPECT().Delete(storeID).Return(nil)
+		mockRepo.EXPECT().Delete(storeID, actorID).Return(nil)
 
-		err := service.DeleteStore(storeID)
+		err := service.DeleteStore(storeID, actorID)
 		assert.NoError(t, err)
 	})
 
 	t.Run("repository error", func(t *testing.T) {
-		mockRepo.EXPECT().Delete(storeID).Return(errors.New("delete failed"))
+		mockRepo.EXPECT().Delete(storeID, actorID).Return(errors.New("delete failed"))
 
-		err := service.DeleteStore(storeID)
+		err := service.DeleteStore(storeID, actorID)
 		assert.Error(t, err)
 		assert.Equal(t, "delete failed", err.Error())
 	})
@@ -229,6 +283,42 @@ func TestStoreService_SearchStores(t *testing.T) {
 	})
 }
 
+func TestStoreService_ImportStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockStoreRepositoryInterface(ctrl)
+	service := NewStoreService(mockRepo, models.DefaultDuplicatePolicy)
+
+	store := &models.Store{Name: "Test Store", Latitude: 35.681, Longitude: 139.767}
+
+	t.Run("creates a new store", func(t *testing.T) {
+		mockRepo.EXPECT().FindDuplicateByLocationAndName("Test Store", 35.681, 139.767, models.DefaultDuplicatePolicy).Return(nil, nil)
+		mockRepo.EXPECT().Create(store).Return(nil)
+
+		duplicate, err := service.ImportStore(store, false)
+		assert.NoError(t, err)
+		assert.Nil(t, duplicate)
+	})
+
+	t.Run("skips a duplicate", func(t *testing.T) {
+		existing := &models.Store{ID: uuid.New(), Name: "Test Store"}
+		mockRepo.EXPECT().FindDuplicateByLocationAndName("Test Store", 35.681, 139.767, models.DefaultDuplicatePolicy).Return(existing, nil)
+
+		duplicate, err := service.ImportStore(store, false)
+		assert.NoError(t, err)
+		assert.Equal(t, existing, duplicate)
+	})
+
+	t.Run("dry run does not create", func(t *testing.T) {
+		mockRepo.EXPECT().FindDuplicateByLocationAndName("Test Store", 35.681, 139.767, models.DefaultDuplicatePolicy).Return(nil, nil)
+
+		duplicate, err := service.ImportStore(store, true)
+		assert.NoError(t, err)
+		assert.Nil(t, duplicate)
+	})
+}
+
 func TestStoreService_GetAllCategories(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -279,4 +369,145 @@ func TestStoreService_GetAllTags(t *testing.T) {
 		assert.Error(t, err)
 		assert.Nil(t, tags)
 	})
-}
\ No newline at end of file
+}
+
+func TestStoreService_RestoreStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockStoreRepositoryInterface(ctrl)
+	service := &StoreService{storeRepo: mockRepo}
+
+	storeID := uuid.New()
+	actorID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.EXPECT().Restore(storeID, actorID).Return(nil)
+
+		err := service.RestoreStore(storeID, actorID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("not in trash", func(t *testing.T) {
+		mockRepo.EXPECT().Restore(storeID, actorID).Return(sql.ErrNoRows)
+
+		err := service.RestoreStore(storeID, actorID)
+		assert.Equal(t, sql.ErrNoRows, err)
+	})
+}
+
+func TestStoreService_PurgeDeletedStores(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockStoreRepositoryInterface(ctrl)
+	service := &StoreService{storeRepo: mockRepo}
+
+	retention := 30 * 24 * time.Hour
+
+	mockRepo.EXPECT().PurgeDeleted(gomock.Any()).DoAndReturn(func(before time.Time) (int64, error) {
+		assert.WithinDuration(t, time.Now().Add(-retention), before, time.Minute)
+		return 3, nil
+	})
+
+	purged, err := service.PurgeDeletedStores(retention)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
+
+func TestStoreService_RollbackStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockStoreRepositoryInterface(ctrl)
+	service := &StoreService{storeRepo: mockRepo}
+
+	storeID := uuid.New()
+	revisionID := uuid.New()
+	actorID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		snapshot := &models.Store{ID: storeID, Name: "Old Name"}
+		mockRepo.EXPECT().GetRevision(revisionID).Return(&models.StoreRevision{
+			ID:       revisionID,
+			StoreID:  storeID,
+			Action:   models.StoreRevisionActionUpdate,
+			Snapshot: snapshot,
+		}, nil)
+		mockRepo.EXPECT().Rollback(snapshot, revisionID, actorID).Return(nil)
+		mockRepo.EXPECT().GetByID(storeID).Return(snapshot, nil)
+
+		store, err := service.RollbackStore(storeID, revisionID, actorID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Old Name", store.Name)
+	})
+
+	t.Run("revision of another store", func(t *testing.T) {
+		mockRepo.EXPECT().GetRevision(revisionID).Return(&models.StoreRevision{
+			ID:       revisionID,
+			StoreID:  uuid.New(),
+			Action:   models.StoreRevisionActionUpdate,
+			Snapshot: &models.Store{},
+		}, nil)
+
+		store, err := service.RollbackStore(storeID, revisionID, actorID)
+		assert.Equal(t, sql.ErrNoRows, err)
+		assert.Nil(t, store)
+	})
+
+	t.Run("delete revision", func(t *testing.T) {
+		mockRepo.EXPECT().GetRevision(revisionID).Return(&models.StoreRevision{
+			ID:       revisionID,
+			StoreID:  storeID,
+			Action:   models.StoreRevisionActionDelete,
+			Snapshot: &models.Store{ID: storeID},
+		}, nil)
+
+		store, err := service.RollbackStore(storeID, revisionID, actorID)
+		assert.Equal(t, ErrRevisionNotRollbackable, err)
+		assert.Nil(t, store)
+	})
+}
+
+func TestStoreService_BulkUpdateStores(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockStoreRepositoryInterface(ctrl)
+	service := &StoreService{storeRepo: mockRepo}
+
+	actorID := uuid.New()
+	ownStore := &models.Store{ID: uuid.New(), CreatedBy: actorID}
+	otherStore := &models.Store{ID: uuid.New(), CreatedBy: uuid.New()}
+	operation := models.StoreBulkOperation{Operation: models.BulkOperationAddTags, Values: []string{"人気"}}
+
+	t.Run("duplicate IDs are applied once and permissions are checked", func(t *testing.T) {
+		mockRepo.EXPECT().BulkApply([]uuid.UUID{ownStore.ID, otherStore.ID}, operation, actorID, gomock.Any()).
+			DoAndReturn(func(ids []uuid.UUID, op models.StoreBulkOperation, actor uuid.UUID, check func(before, after *models.Store) *models.StoreBulkResult) ([]*models.StoreBulkResult, error) {
+				assert.Nil(t, check(ownStore, ownStore))
+
+				result := check(otherStore, otherStore)
+				assert.Equal(t, models.BulkStatusForbidden, result.Status)
+				assert.Equal(t, otherStore.ID, result.StoreID)
+
+				tooManyTags := &models.Store{ID: ownStore.ID, CreatedBy: actorID, Tags: make(models.StringArray, 21)}
+				result = check(ownStore, tooManyTags)
+				assert.Equal(t, models.BulkStatusFailed, result.Status)
+				return nil, nil
+			})
+
+		_, err := service.BulkUpdateStores([]uuid.UUID{ownStore.ID, otherStore.ID, ownStore.ID}, operation, actorID, false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("admin may change any store", func(t *testing.T) {
+		mockRepo.EXPECT().BulkApply([]uuid.UUID{otherStore.ID}, operation, actorID, gomock.Any()).
+			DoAndReturn(func(ids []uuid.UUID, op models.StoreBulkOperation, actor uuid.UUID, check func(before, after *models.Store) *models.StoreBulkResult) ([]*models.StoreBulkResult, error) {
+				assert.Nil(t, check(otherStore, otherStore))
+				return nil, nil
+			})
+
+		_, err := service.BulkUpdateStores([]uuid.UUID{otherStore.ID}, operation, actorID, true)
+		assert.NoError(t, err)
+	})
+}
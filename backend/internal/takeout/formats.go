@@ -0,0 +1,249 @@
+package takeout
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// geoJSONFile is the "Saved Places.json" file of Takeout
+type geoJSONFile struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Geometry *struct {
+		Type        string    `json:"type"`
+		Coordinates []float64 `json:"coordinates"`
+	} `json:"geometry"`
+	Properties geoJSONProperties `json:"properties"`
+}
+
+// geoJSONProperties covers both the current Takeout format (google_maps_url,
+// location.name) and the older one (Google Maps URL, Location.Business Name).
+// Field names match case-insensitively, so location also reads Location.
+type geoJSONProperties struct {
+	GoogleMapsURL       string `json:"google_maps_url"`
+	LegacyGoogleMapsURL string `json:"Google Maps URL"`
+	Title               string `json:"Title"`
+	Location            struct {
+		Name           string `json:"name"`
+		BusinessName   string `json:"Business Name"`
+		Address        string `json:"address"`
+		GeoCoordinates *struct {
+			Latitude  json.RawMessage `json:"Latitude"`
+			Longitude json.RawMessage `json:"Longitude"`
+		} `json:"Geo Coordinates"`
+	} `json:"location"`
+}
+
+// ParseGeoJSON reads the saved places GeoJSON of Takeout
+func ParseGeoJSON(r io.Reader) ([]Place, error) {
+	var file geoJSONFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON: %v", err)
+	}
+	if file.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("GeoJSON must be a FeatureCollection, got %q", file.Type)
+	}
+
+	places := make([]Place, 0, len(file.Features))
+	for i, feature := range file.Features {
+		properties := feature.Properties
+		place := Place{
+			Index:        i + 1,
+			Name:         firstNonEmpty(properties.Location.Name, properties.Location.BusinessName, properties.Title),
+			Address:      properties.Location.Address,
+			GoogleMapURL: firstNonEmpty(properties.GoogleMapsURL, properties.LegacyGoogleMapsURL),
+		}
+
+		var latitude, longitude float64
+		found := false
+		if geometry := feature.Geometry; geometry != nil && geometry.Type == "Point" && len(geometry.Coordinates) >= 2 {
+			longitude, latitude, found = geometry.Coordinates[0], geometry.Coordinates[1], true
+		}
+		if coordinates := properties.Location.GeoCoordinates; (!found || latitude == 0 && longitude == 0) && coordinates != nil {
+			lat, err1 := parseJSONFloat(coordinates.Latitude)
+			lng, err2 := parseJSONFloat(coordinates.Longitude)
+			if err1 == nil && err2 == nil {
+				latitude, longitude, found = lat, lng, true
+			}
+		}
+		place.setCoordinates(latitude, longitude, found)
+
+		places = append(places, place)
+	}
+	return places, nil
+}
+
+// parseJSONFloat reads a number written either as a JSON number or a string
+func parseJSONFloat(raw json.RawMessage) (float64, error) {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		value = string(raw)
+	}
+	return strconv.ParseFloat(strings.TrimSpace(value), 64)
+}
+
+// csvColumns are the accepted headers of each CSV field. Takeout writes
+// Title, Note and URL; the others are read when a list was edited by hand.
+var csvColumns = map[string][]string{
+	"name":      {"title", "タイトル", "name", "名前", "店名"},
+	"url":       {"url", "google maps url"},
+	"address":   {"address", "住所"},
+	"latitude":  {"latitude", "緯度"},
+	"longitude": {"longitude", "経度"},
+}
+
+// ParseCSV reads a saved list CSV of Takeout
+func ParseCSV(r io.Reader) ([]Place, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %v", err)
+	}
+	content = bytes.TrimPrefix(content, []byte{0xEF, 0xBB, 0xBF})
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for field, names := range csvColumns {
+			for _, candidate := range names {
+				if _, ok := columns[field]; !ok && name == candidate {
+					columns[field] = i
+				}
+			}
+		}
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("missing Title column")
+	}
+
+	var places []Place
+	row := 1
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		row++
+		if err != nil {
+			places = append(places, Place{Index: row, Err: fmt.Errorf("invalid CSV row: %v", err)})
+			continue
+		}
+
+		get := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(fields[i])
+		}
+		// Takeout leaves an empty row below the header
+		if get("name") == "" && get("url") == "" {
+			continue
+		}
+
+		place := Place{Index: row, Name: get("name"), Address: get("address"), GoogleMapURL: get("url")}
+		latitude, err1 := strconv.ParseFloat(get("latitude"), 64)
+		longitude, err2 := strconv.ParseFloat(get("longitude"), 64)
+		place.setCoordinates(latitude, longitude, err1 == nil && err2 == nil)
+
+		places = append(places, place)
+	}
+	return places, nil
+}
+
+type kmlPlacemark struct {
+	Name         string `xml:"name"`
+	Address      string `xml:"address"`
+	Description  string `xml:"description"`
+	ExtendedData []struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value"`
+	} `xml:"ExtendedData>Data"`
+	Point struct {
+		Coordinates string `xml:"coordinates"`
+	} `xml:"Point"`
+}
+
+// ParseKML reads the placemarks of a KML file, such as a Google My Maps export.
+// Placemarks may be nested in folders at any depth.
+func ParseKML(r io.Reader) ([]Place, error) {
+	decoder := xml.NewDecoder(r)
+
+	var places []Place
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid KML: %v", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Placemark" {
+			continue
+		}
+
+		var placemark kmlPlacemark
+		if err := decoder.DecodeElement(&placemark, &start); err != nil {
+			return nil, fmt.Errorf("invalid KML placemark: %v", err)
+		}
+
+		place := Place{Index: len(places) + 1, Name: strings.TrimSpace(placemark.Name), Address: strings.TrimSpace(placemark.Address)}
+		for _, data := range placemark.ExtendedData {
+			switch strings.ToLower(data.Name) {
+			case "address", "住所":
+				if place.Address == "" {
+					place.Address = strings.TrimSpace(data.Value)
+				}
+			case "url", "google maps url", "google_map_url":
+				place.GoogleMapURL = strings.TrimSpace(data.Value)
+			}
+		}
+		if description := strings.TrimSpace(placemark.Description); place.GoogleMapURL == "" && strings.HasPrefix(description, "https://") && !strings.ContainsAny(description, " \n") {
+			place.GoogleMapURL = description
+		}
+
+		// lng,lat[,altitude]
+		var latitude, longitude float64
+		found := false
+		if parts := strings.Split(strings.TrimSpace(placemark.Point.Coordinates), ","); len(parts) >= 2 {
+			lng, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+			lat, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+			if err1 == nil && err2 == nil {
+				latitude, longitude, found = lat, lng, true
+			}
+		}
+		place.setCoordinates(latitude, longitude, found)
+
+		places = append(places, place)
+	}
+	return places, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
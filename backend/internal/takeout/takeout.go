@@ -0,0 +1,205 @@
+// Package takeout reads the saved places lists of Google Maps exported by
+// Google Takeout (GeoJSON or CSV) or Google My Maps (KML) and imports them
+// as stores. It works offline: coordinates come from the file itself, or
+// from the Maps URL when the file has none.
+package takeout
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sukimise/internal/models"
+	"sukimise/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// Place is a saved place read from a Takeout file. Err is set when the
+// entry could not be read, e.g. because it has no coordinates.
+type Place struct {
+	Index        int // 1-based position in the file (row number for CSV)
+	Name         string
+	Address      string
+	Latitude     float64
+	Longitude    float64
+	GoogleMapURL string
+	Err          error
+}
+
+// Parse reads a Takeout file, choosing the format by its extension
+func Parse(filename string, r io.Reader) ([]Place, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json", ".geojson":
+		return ParseGeoJSON(r)
+	case ".csv":
+		return ParseCSV(r)
+	case ".kml":
+		return ParseKML(r)
+	default:
+		return nil, fmt.Errorf("unsupported file type %q: expected .json, .geojson, .csv or .kml", filepath.Ext(filename))
+	}
+}
+
+// ListName returns the list name of a Takeout file, which Takeout uses as
+// the file name, e.g. "行きたい.csv"
+func ListName(filename string) string {
+	base := filepath.Base(strings.ReplaceAll(filename, "\\", "/"))
+	return strings.TrimSpace(strings.TrimSuffix(base, filepath.Ext(base)))
+}
+
+// Store converts the place into a new store tagged with the list name.
+// Takeout CSV files and many GeoJSON features and placemarks have no
+// address, so the coordinates stand in for it.
+func (p Place) Store(listName string, userID uuid.UUID) (*models.Store, error) {
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	if strings.TrimSpace(p.Name) == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if err := utils.ValidateCoordinates(p.Latitude, p.Longitude); err != nil {
+		return nil, fmt.Errorf("invalid coordinates: %v", err)
+	}
+
+	address := strings.TrimSpace(p.Address)
+	if address == "" {
+		address = fmt.Sprintf("%.6f, %.6f", p.Latitude, p.Longitude)
+	}
+
+	googleMapURL := p.GoogleMapURL
+	if utils.ValidateURL(googleMapURL) != nil {
+		googleMapURL = ""
+	}
+
+	tags := models.StringArray{}
+	if listName = strings.TrimSpace(listName); listName != "" {
+		tags = append(tags, listName)
+	}
+
+	return &models.Store{
+		Name:                   strings.TrimSpace(p.Name),
+		Address:                address,
+		Latitude:               p.Latitude,
+		Longitude:              p.Longitude,
+		Categories:             models.StringArray{},
+		BusinessHours:          models.GetDefaultBusinessHours(),
+		BusinessHourExceptions: models.BusinessHourExceptions{},
+		GoogleMapURL:           googleMapURL,
+		SnsUrls:                models.StringArray{},
+		Tags:                   tags,
+		Photos:                 models.StringArray{},
+		CreatedBy:              userID,
+	}, nil
+}
+
+// StoreImporter creates a store unless a duplicate of it already exists
+type StoreImporter interface {
+	ImportStore(store *models.Store, dryRun bool) (*models.Store, error)
+}
+
+// Result is the outcome of importing a single place. A nil StoreID with a
+// nil DuplicateOf and Err means the place would be created on a dry run.
+type Result struct {
+	Index       int
+	Name        string
+	StoreID     *uuid.UUID
+	DuplicateOf *uuid.UUID
+	Err         error
+}
+
+// Import creates a store for each place, skipping those already registered
+func Import(places []Place, listName string, userID uuid.UUID, dryRun bool, importer StoreImporter) []Result {
+	results := make([]Result, 0, len(places))
+	for _, place := range places {
+		result := Result{Index: place.Index, Name: place.Name}
+
+		store, err := place.Store(listName, userID)
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+
+		duplicate, err := importer.ImportStore(store, dryRun)
+		switch {
+		case err != nil:
+			log.Printf("Failed to import place %d (%s): %v", place.Index, place.Name, err)
+			result.Err = fmt.Errorf("failed to create store")
+		case duplicate != nil:
+			result.DuplicateOf = &duplicate.ID
+		case !dryRun:
+			result.StoreID = &store.ID
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+var (
+	// @35.6812,139.7671,17z
+	atCoordinatesPattern = regexp.MustCompile(`@(-?\d+(?:\.\d+)?),(-?\d+(?:\.\d+)?)`)
+	// !3d35.6812!4d139.7671 in the data parameter of place URLs
+	dataCoordinatesPattern = regexp.MustCompile(`!3d(-?\d+(?:\.\d+)?)!4d(-?\d+(?:\.\d+)?)`)
+	// 35.6812,139.7671 in search paths and q/query/ll parameters
+	plainCoordinatesPattern = regexp.MustCompile(`^\s*(-?\d+(?:\.\d+)?)\s*,\s*(-?\d+(?:\.\d+)?)\s*$`)
+)
+
+// coordinatesFromURL extracts the coordinates of a Google Maps URL, if it has any
+func coordinatesFromURL(rawURL string) (latitude, longitude float64, ok bool) {
+	parse := func(match []string) (float64, float64, bool) {
+		lat, err1 := strconv.ParseFloat(match[1], 64)
+		lng, err2 := strconv.ParseFloat(match[2], 64)
+		if err1 != nil || err2 != nil || utils.ValidateCoordinates(lat, lng) != nil {
+			return 0, 0, false
+		}
+		return lat, lng, true
+	}
+
+	// The place marker is more precise than the map center after @
+	if match := dataCoordinatesPattern.FindStringSubmatch(rawURL); match != nil {
+		if lat, lng, ok := parse(match); ok {
+			return lat, lng, true
+		}
+	}
+	if match := atCoordinatesPattern.FindStringSubmatch(rawURL); match != nil {
+		if lat, lng, ok := parse(match); ok {
+			return lat, lng, true
+		}
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, 0, false
+	}
+	candidates := []string{u.Query().Get("q"), u.Query().Get("query"), u.Query().Get("ll")}
+	if _, rest, found := strings.Cut(u.Path, "/maps/search/"); found {
+		candidates = append(candidates, strings.TrimSuffix(rest, "/"))
+	}
+	for _, candidate := range candidates {
+		if match := plainCoordinatesPattern.FindStringSubmatch(candidate); match != nil {
+			if lat, lng, ok := parse(match); ok {
+				return lat, lng, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// setCoordinates sets the coordinates of the place, falling back to its Maps URL.
+// Takeout writes [0, 0] for places it has no coordinates for.
+func (p *Place) setCoordinates(latitude, longitude float64, found bool) {
+	if found && (latitude != 0 || longitude != 0) {
+		p.Latitude, p.Longitude = latitude, longitude
+		return
+	}
+	if lat, lng, ok := coordinatesFromURL(p.GoogleMapURL); ok {
+		p.Latitude, p.Longitude = lat, lng
+		return
+	}
+	p.Err = fmt.Errorf("no coordinates in the file or the Maps URL")
+}
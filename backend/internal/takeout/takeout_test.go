@@ -0,0 +1,247 @@
+package takeout
+
+import (
+	"errors"
+	"strings"
+	"sukimise/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGeoJSON(t *testing.T) {
+	input := `{
+		"type": "FeatureCollection",
+		"features": [
+			{
+				"type": "Feature",
+				"geometry": {"type": "Point", "coordinates": [139.7671, 35.6812]},
+				"properties": {
+					"date": "2023-04-01T10:00:00Z",
+					"google_maps_url": "http://maps.google.com/?cid=123",
+					"location": {"address": "東京都千代田区丸の内1丁目", "country_code": "JP", "name": "東京駅"}
+				}
+			},
+			{
+				"type": "Feature",
+				"geometry": {"type": "Point", "coordinates": [0, 0]},
+				"properties": {
+					"Google Maps URL": "https://www.google.com/maps/place/x/@35.6586,139.7454,17z",
+					"Title": "東京タワー",
+					"Location": {"Address": "東京都港区芝公園4丁目", "Business Name": "東京タワー"}
+				}
+			},
+			{
+				"type": "Feature",
+				"geometry": {"type": "Point", "coordinates": [0, 0]},
+				"properties": {
+					"Title": "座標なし",
+					"Location": {"Address": "不明", "Geo Coordinates": {"Latitude": "35.7101", "Longitude": "139.8107"}}
+				}
+			},
+			{
+				"type": "Feature",
+				"geometry": {"type": "Point", "coordinates": [0, 0]},
+				"properties": {"google_maps_url": "http://maps.google.com/?cid=456", "location": {"name": "不明な場所"}}
+			}
+		]
+	}`
+
+	places, err := ParseGeoJSON(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, places, 4)
+
+	assert.Equal(t, Place{Index: 1, Name: "東京駅", Address: "東京都千代田区丸の内1丁目", Latitude: 35.6812, Longitude: 139.7671, GoogleMapURL: "http://maps.google.com/?cid=123"}, places[0])
+
+	assert.Equal(t, "東京タワー", places[1].Name)
+	assert.Equal(t, "東京都港区芝公園4丁目", places[1].Address)
+	assert.Equal(t, 35.6586, places[1].Latitude)
+	assert.Equal(t, 139.7454, places[1].Longitude)
+
+	assert.NoError(t, places[2].Err)
+	assert.Equal(t, 35.7101, places[2].Latitude)
+
+	assert.Error(t, places[3].Err)
+}
+
+func TestParseGeoJSON_Invalid(t *testing.T) {
+	_, err := ParseGeoJSON(strings.NewReader(`{"type": "Feature"}`))
+	assert.Error(t, err)
+
+	_, err = ParseGeoJSON(strings.NewReader(`not json`))
+	assert.Error(t, err)
+}
+
+func TestParseCSV(t *testing.T) {
+	input := "\xEF\xBB\xBFTitle,Note,URL,Tags,Comment\n" +
+		",,,,\n" +
+		"東京駅,,\"https://www.google.com/maps/search/35.6812,139.7671\",,\n" +
+		"上野公園,,https://www.google.com/maps/place/%E4%B8%8A%E9%87%8E/data=!4m2!3m1!1s0x0:0x0!3d35.7148!4d139.7737,,\n" +
+		"不明,,https://www.google.com/maps/place/x/data=!4m2!3m1!1s0x0:0x0,,\n"
+
+	places, err := ParseCSV(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, places, 3)
+
+	assert.Equal(t, 3, places[0].Index)
+	assert.Equal(t, "東京駅", places[0].Name)
+	assert.Equal(t, 35.6812, places[0].Latitude)
+	assert.Equal(t, 139.7671, places[0].Longitude)
+
+	assert.Equal(t, 35.7148, places[1].Latitude)
+	assert.Equal(t, 139.7737, places[1].Longitude)
+
+	assert.Error(t, places[2].Err)
+}
+
+func TestParseCSV_AddressAndCoordinateColumns(t *testing.T) {
+	input := "タイトル,住所,緯度,経度\n喫茶店,東京都台東区,35.71,139.77\n"
+
+	places, err := ParseCSV(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, places, 1)
+	assert.Equal(t, Place{Index: 2, Name: "喫茶店", Address: "東京都台東区", Latitude: 35.71, Longitude: 139.77}, places[0])
+}
+
+func TestParseCSV_MissingTitle(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader("Note,URL\n,\n"))
+	assert.Error(t, err)
+}
+
+func TestParseKML(t *testing.T) {
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <name>行きたい</name>
+    <Folder>
+      <Placemark>
+        <name>東京駅</name>
+        <address>東京都千代田区丸の内1丁目</address>
+        <Point><coordinates>139.7671,35.6812,0</coordinates></Point>
+      </Placemark>
+      <Folder>
+        <Placemark>
+          <name>上野公園</name>
+          <ExtendedData><Data name="住所"><value>東京都台東区上野公園</value></Data></ExtendedData>
+          <Point><coordinates>139.7737,35.7148</coordinates></Point>
+        </Placemark>
+      </Folder>
+    </Folder>
+  </Document>
+</kml>`
+
+	places, err := ParseKML(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, places, 2)
+
+	assert.Equal(t, Place{Index: 1, Name: "東京駅", Address: "東京都千代田区丸の内1丁目", Latitude: 35.6812, Longitude: 139.7671}, places[0])
+	assert.Equal(t, "東京都台東区上野公園", places[1].Address)
+	assert.Equal(t, 35.7148, places[1].Latitude)
+}
+
+func TestParse_UnsupportedExtension(t *testing.T) {
+	_, err := Parse("places.txt", strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestListName(t *testing.T) {
+	assert.Equal(t, "行きたい", ListName("Takeout/Saved/行きたい.csv"))
+	assert.Equal(t, "Saved Places", ListName(`C:\Takeout\Maps (your places)\Saved Places.json`))
+}
+
+func TestPlace_Store(t *testing.T) {
+	userID := uuid.New()
+
+	store, err := Place{Name: " 東京駅 ", Address: "東京都", Latitude: 35.68, Longitude: 139.76, GoogleMapURL: "http://maps.google.com/?cid=1"}.Store("行きたい", userID)
+	require.NoError(t, err)
+	assert.Equal(t, "東京駅", store.Name)
+	assert.Equal(t, models.StringArray{"行きたい"}, store.Tags)
+	assert.Equal(t, "http://maps.google.com/?cid=1", store.GoogleMapURL)
+	assert.Equal(t, userID, store.CreatedBy)
+
+	store, err = Place{Name: "東京駅", Latitude: 35.6812, Longitude: 139.7671}.Store("行きたい", userID)
+	require.NoError(t, err)
+	assert.Equal(t, "35.681200, 139.767100", store.Address)
+}
+
+type fakeImporter struct {
+	existing map[string]*models.Store
+	created  []*models.Store
+	err      error
+}
+
+func (f *fakeImporter) ImportStore(store *models.Store, dryRun bool) (*models.Store, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if duplicate, ok := f.existing[store.Name]; ok {
+		return duplicate, nil
+	}
+	if !dryRun {
+		store.ID = uuid.New()
+		f.created = append(f.created, store)
+	}
+	return nil, nil
+}
+
+func TestImport(t *testing.T) {
+	existing := &models.Store{ID: uuid.New(), Name: "東京駅"}
+	importer := &fakeImporter{existing: map[string]*models.Store{"東京駅": existing}}
+	places := []Place{
+		{Index: 1, Name: "東京駅", Address: "東京都", Latitude: 35.68, Longitude: 139.76},
+		{Index: 2, Name: "上野公園", Address: "東京都", Latitude: 35.71, Longitude: 139.77},
+		{Index: 3, Name: "不明", Err: errors.New("no coordinates in the file or the Maps URL")},
+	}
+
+	results := Import(places, "行きたい", uuid.New(), false, importer)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, &existing.ID, results[0].DuplicateOf)
+	assert.Nil(t, results[0].StoreID)
+
+	require.Len(t, importer.created, 1)
+	assert.Equal(t, &importer.created[0].ID, results[1].StoreID)
+	assert.Equal(t, models.StringArray{"行きたい"}, importer.created[0].Tags)
+
+	assert.EqualError(t, results[2].Err, "no coordinates in the file or the Maps URL")
+}
+
+func TestImport_TakeoutCSV(t *testing.T) {
+	// Takeout saved lists have no address or coordinate columns
+	input := "\xEF\xBB\xBFTitle,Note,URL,Tags,Comment\n" +
+		",,,,\n" +
+		"東京駅,待ち合わせ,\"https://www.google.com/maps/place/%E6%9D%B1%E4%BA%AC%E9%A7%85/@35.6812,139.7671,17z\",,\n" +
+		"上野公園,,https://www.google.com/maps/place/%E4%B8%8A%E9%87%8E/data=!4m2!3m1!1s0x0:0x0!3d35.7148!4d139.7737,,\n"
+
+	places, err := ParseCSV(strings.NewReader(input))
+	require.NoError(t, err)
+
+	importer := &fakeImporter{}
+	results := Import(places, "行きたい", uuid.New(), false, importer)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+		assert.NotNil(t, result.StoreID)
+	}
+
+	require.Len(t, importer.created, 2)
+	assert.Equal(t, "東京駅", importer.created[0].Name)
+	assert.Equal(t, "35.681200, 139.767100", importer.created[0].Address)
+	assert.Equal(t, models.StringArray{"行きたい"}, importer.created[0].Tags)
+	assert.Equal(t, "35.714800, 139.773700", importer.created[1].Address)
+}
+
+func TestImport_DryRunAndErrors(t *testing.T) {
+	places := []Place{{Index: 1, Name: "上野公園", Address: "東京都", Latitude: 35.71, Longitude: 139.77}}
+
+	importer := &fakeImporter{}
+	results := Import(places, "", uuid.New(), true, importer)
+	assert.Empty(t, importer.created)
+	assert.Nil(t, results[0].StoreID)
+	assert.NoError(t, results[0].Err)
+
+	results = Import(places, "", uuid.New(), false, &fakeImporter{err: errors.New("connection refused")})
+	assert.EqualError(t, results[0].Err, "failed to create store")
+}
@@ -22,6 +22,7 @@ type MetaInfo struct {
 	Limit      int  `json:"limit,omitempty"`
 	Offset     int  `json:"offset,omitempty"`
 	TotalPages *int `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"` // Cursor of the next page for keyset pagination
 }
 
 // PaginationRequest represents pagination parameters
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"encoding/json"
+)
+
+// MergePatch applies a JSON Merge Patch (RFC 7396) to a JSON document and
+// returns the patched document. Members of the patch that are null remove the
+// member from the target, objects are merged recursively and any other value
+// replaces the target member as a whole.
+func MergePatch(target, patch []byte) ([]byte, error) {
+	var targetValue interface{}
+	if len(target) > 0 {
+		if err := json.Unmarshal(target, &targetValue); err != nil {
+			return nil, err
+		}
+	}
+
+	var patchValue interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergePatchValue(targetValue, patchValue))
+}
+
+func mergePatchValue(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergePatchValue(targetObject[name], value)
+	}
+	return targetObject
+}
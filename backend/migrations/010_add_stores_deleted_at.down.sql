@@ -0,0 +1,5 @@
+-- Permanently remove stores that were in the trash before dropping the column
+DELETE FROM stores WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_stores_deleted_at;
+ALTER TABLE stores DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,5 @@
+-- Soft delete for stores: deleted stores stay in the trash until purged
+ALTER TABLE stores ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Partial index for trash listing and purging
+CREATE INDEX idx_stores_deleted_at ON stores(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_store_revisions_user_id;
+DROP INDEX IF EXISTS idx_store_revisions_store_id_created_at;
+DROP TABLE IF EXISTS store_revisions;
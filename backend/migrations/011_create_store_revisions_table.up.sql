@@ -0,0 +1,15 @@
+-- Change history of stores. One row is written for every create, update,
+-- delete, restore and rollback of a store.
+CREATE TABLE store_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'delete', 'restore', 'rollback')),
+    changes JSONB NOT NULL DEFAULT '{}', -- {"field": {"old": ..., "new": ...}}
+    snapshot JSONB NOT NULL, -- Store state after the change
+    source_revision_id UUID REFERENCES store_revisions(id) ON DELETE SET NULL, -- Revision a rollback restored
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_store_revisions_store_id_created_at ON store_revisions(store_id, created_at DESC);
+CREATE INDEX idx_store_revisions_user_id ON store_revisions(user_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_reviews_search_text;
+DROP INDEX IF EXISTS idx_stores_search_text;
+
+ALTER TABLE reviews DROP COLUMN IF EXISTS search_text;
+ALTER TABLE stores DROP COLUMN IF EXISTS search_text;
+
+DROP FUNCTION IF EXISTS search_like_pattern(TEXT);
+DROP FUNCTION IF EXISTS normalize_search_text(TEXT);
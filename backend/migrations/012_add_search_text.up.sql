@@ -0,0 +1,32 @@
+-- Full-text search over stores and reviews (GET /stores?q=)
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Normalizes text for search: NFKC folds full-width/half-width variants
+-- (ＡＢＣ, ｶﾞ), lower() folds case and katakana is folded into hiragana
+CREATE OR REPLACE FUNCTION normalize_search_text(input TEXT) RETURNS TEXT AS $$
+    SELECT translate(
+        lower(normalize(COALESCE(input, ''), NFKC)),
+        'ァアィイゥウェエォオカガキギクグケゲコゴサザシジスズセゼソゾタダチヂッツヅテデトドナニヌネノハバパヒビピフブプヘベペホボポマミムメモャヤュユョヨラリルレロヮワヰヱヲンヴヵヶヽヾ',
+        'ぁあぃいぅうぇえぉおかがきぎくぐけげこごさざしじすずせぜそぞただちぢっつづてでとどなにぬねのはばぱひびぴふぶぷへべぺほぼぽまみむめもゃやゅゆょよらりるれろゎわゐゑをんゔゕゖゝゞ'
+    )
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+-- LIKE pattern matching the normalized query anywhere in the text
+CREATE OR REPLACE FUNCTION search_like_pattern(query TEXT) RETURNS TEXT AS $$
+    SELECT '%' || replace(replace(replace(normalize_search_text(query), '\', '\\'), '%', '\%'), '_', '\_') || '%'
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+ALTER TABLE stores ADD COLUMN search_text TEXT GENERATED ALWAYS AS (
+    normalize_search_text(
+        name || ' ' || address || ' ' || COALESCE(parking_info, '') || ' ' ||
+        COALESCE(categories::text, '') || ' ' || COALESCE(tags::text, '')
+    )
+) STORED;
+
+ALTER TABLE reviews ADD COLUMN search_text TEXT GENERATED ALWAYS AS (
+    normalize_search_text(COALESCE(comment, '') || ' ' || COALESCE(food_notes, ''))
+) STORED;
+
+-- Trigram indexes serve both substring (LIKE) and fuzzy (<%) matches
+CREATE INDEX idx_stores_search_text ON stores USING GIN (search_text gin_trgm_ops);
+CREATE INDEX idx_reviews_search_text ON reviews USING GIN (search_text gin_trgm_ops);
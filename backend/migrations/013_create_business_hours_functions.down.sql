@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS business_hours_open_at(JSONB, TEXT, TEXT, INTEGER, INTEGER);
+DROP FUNCTION IF EXISTS day_time_slots(JSONB);
+DROP FUNCTION IF EXISTS time_slot_open_at(JSONB, INTEGER, INTEGER);
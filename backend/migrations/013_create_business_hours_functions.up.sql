@@ -0,0 +1,36 @@
+-- Business-hours evaluation shared by the business_day/business_time and open_now/open_at filters
+
+-- Whether a time slot is open at the given minute of its day. The minute may
+-- exceed 1440 for the early hours of the following day, when a slot closing
+-- at or before its opening time runs past midnight (e.g. 18:00-02:00).
+-- A slot stops taking customers at its last order (or close) time, and
+-- margin minutes earlier when margin is given.
+CREATE OR REPLACE FUNCTION time_slot_open_at(slot JSONB, minute INTEGER, margin INTEGER) RETURNS BOOLEAN AS $$
+    SELECT open_minute <= minute
+       AND minute + margin <= CASE WHEN end_minute <= open_minute THEN end_minute + 1440 ELSE end_minute END
+    FROM (
+        SELECT EXTRACT(EPOCH FROM NULLIF(slot->>'open_time', '')::time)::integer / 60 AS open_minute,
+               EXTRACT(EPOCH FROM COALESCE(NULLIF(slot->>'last_order_time', ''), NULLIF(slot->>'close_time', ''))::time)::integer / 60 AS end_minute
+    ) AS slot_minutes
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+-- The time slots of a day schedule, none when time_slots is missing or null
+CREATE OR REPLACE FUNCTION day_time_slots(schedule JSONB) RETURNS SETOF JSONB AS $$
+    SELECT jsonb_array_elements(CASE WHEN jsonb_typeof(schedule->'time_slots') = 'array' THEN schedule->'time_slots' ELSE '[]' END)
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+-- Whether business hours are open on day (monday...sunday) at the minute of
+-- the day, including slots of previous_day that run past midnight
+CREATE OR REPLACE FUNCTION business_hours_open_at(hours JSONB, day TEXT, previous_day TEXT, minute INTEGER, margin INTEGER) RETURNS BOOLEAN AS $$
+    SELECT hours IS NOT NULL AND (
+        (COALESCE(hours->day->>'is_closed', 'false') != 'true' AND EXISTS (
+            SELECT 1 FROM day_time_slots(hours->day) AS slot
+            WHERE time_slot_open_at(slot, minute, margin)
+        ))
+        OR
+        (COALESCE(hours->previous_day->>'is_closed', 'false') != 'true' AND EXISTS (
+            SELECT 1 FROM day_time_slots(hours->previous_day) AS slot
+            WHERE time_slot_open_at(slot, minute + 1440, margin)
+        ))
+    )
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
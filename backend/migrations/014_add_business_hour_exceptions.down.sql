@@ -0,0 +1,7 @@
+DROP FUNCTION IF EXISTS business_hours_open_on(JSONB, JSONB, DATE, INTEGER, INTEGER, BOOLEAN, BOOLEAN);
+DROP FUNCTION IF EXISTS day_schedule_open_at(JSONB, INTEGER, INTEGER);
+DROP FUNCTION IF EXISTS day_schedule_on(JSONB, JSONB, DATE, BOOLEAN);
+DROP FUNCTION IF EXISTS business_hour_exception_on(JSONB, DATE, BOOLEAN);
+DROP FUNCTION IF EXISTS weekday_name(DATE);
+
+ALTER TABLE stores DROP COLUMN IF EXISTS business_hour_exceptions;
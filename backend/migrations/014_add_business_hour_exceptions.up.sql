@@ -0,0 +1,55 @@
+-- Per-date exceptions to the weekly business hours: closures, special hours,
+-- annual periods, public holidays and nth-weekday rules
+ALTER TABLE stores ADD COLUMN business_hour_exceptions JSONB NOT NULL DEFAULT '[]';
+
+-- The business_hours key of a date (monday...sunday)
+CREATE OR REPLACE FUNCTION weekday_name(on_date DATE) RETURNS TEXT AS $$
+    SELECT (ARRAY['monday', 'tuesday', 'wednesday', 'thursday', 'friday', 'saturday', 'sunday'])[EXTRACT(ISODOW FROM on_date)::integer]
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+-- The exception applying on a date, NULL when there is none. is_holiday tells
+-- whether the date is a public holiday, which the database does not know.
+-- date exceptions take precedence over annual, holiday and nth_weekday ones.
+CREATE OR REPLACE FUNCTION business_hour_exception_on(exceptions JSONB, on_date DATE, is_holiday BOOLEAN) RETURNS JSONB AS $$
+    SELECT e
+    FROM jsonb_array_elements(CASE WHEN jsonb_typeof(exceptions) = 'array' THEN exceptions ELSE '[]' END) AS e
+    WHERE CASE e->>'type'
+        WHEN 'date' THEN
+            on_date BETWEEN (e->>'date')::date AND COALESCE(NULLIF(e->>'end_date', ''), e->>'date')::date
+        WHEN 'annual' THEN
+            -- MM-DD periods may wrap around the new year, e.g. 12-29 to 01-03
+            CASE WHEN e->>'date' <= COALESCE(NULLIF(e->>'end_date', ''), e->>'date')
+                THEN to_char(on_date, 'MM-DD') BETWEEN e->>'date' AND COALESCE(NULLIF(e->>'end_date', ''), e->>'date')
+                ELSE to_char(on_date, 'MM-DD') >= e->>'date' OR to_char(on_date, 'MM-DD') <= e->>'end_date'
+            END
+        WHEN 'holiday' THEN
+            is_holiday
+        WHEN 'nth_weekday' THEN
+            e->>'weekday' = weekday_name(on_date)
+            AND (e->>'nth')::integer = (EXTRACT(DAY FROM on_date)::integer - 1) / 7 + 1
+        ELSE false
+    END
+    ORDER BY array_position(ARRAY['date', 'annual', 'holiday', 'nth_weekday'], e->>'type')
+    LIMIT 1
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+-- The day schedule of a date: the matching exception, or else the weekly business hours
+CREATE OR REPLACE FUNCTION day_schedule_on(hours JSONB, exceptions JSONB, on_date DATE, is_holiday BOOLEAN) RETURNS JSONB AS $$
+    SELECT COALESCE(business_hour_exception_on(exceptions, on_date, is_holiday), hours->weekday_name(on_date))
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+-- Whether a day schedule is open at the minute of its day (see time_slot_open_at)
+CREATE OR REPLACE FUNCTION day_schedule_open_at(schedule JSONB, minute INTEGER, margin INTEGER) RETURNS BOOLEAN AS $$
+    SELECT COALESCE(schedule->>'is_closed', 'false') != 'true' AND EXISTS (
+        SELECT 1 FROM day_time_slots(schedule) AS slot
+        WHERE time_slot_open_at(slot, minute, margin)
+    )
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+-- Whether a store is open on a date at the minute of the day, applying its
+-- exceptions and including slots of the previous date that run past midnight
+CREATE OR REPLACE FUNCTION business_hours_open_on(hours JSONB, exceptions JSONB, on_date DATE, minute INTEGER, margin INTEGER,
+                                                  is_holiday BOOLEAN, previous_is_holiday BOOLEAN) RETURNS BOOLEAN AS $$
+    SELECT day_schedule_open_at(day_schedule_on(hours, exceptions, on_date, is_holiday), minute, margin)
+        OR day_schedule_open_at(day_schedule_on(hours, exceptions, on_date - 1, previous_is_holiday), minute + 1440, margin)
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
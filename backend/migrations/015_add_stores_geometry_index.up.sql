@@ -0,0 +1,3 @@
+-- Spatial index on the store coordinates for bounding-box and polygon searches.
+-- Queries must use the same expression, ST_SetSRID(ST_MakePoint(longitude, latitude), 4326).
+CREATE INDEX IF NOT EXISTS idx_stores_geometry ON stores USING GIST ((ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)));
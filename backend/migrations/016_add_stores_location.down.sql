@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_stores_location_gist;
+ALTER TABLE stores DROP COLUMN IF EXISTS location;
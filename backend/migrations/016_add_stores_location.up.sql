@@ -0,0 +1,9 @@
+-- Geography point of each store, kept in sync with latitude/longitude, so that
+-- radius, proximity and duplicate searches can use a spatial index.
+-- init-db.sql used to create a plain location column that nothing populated.
+ALTER TABLE stores DROP COLUMN IF EXISTS location;
+
+ALTER TABLE stores ADD COLUMN location GEOGRAPHY(POINT, 4326)
+    GENERATED ALWAYS AS (ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)::geography) STORED;
+
+CREATE INDEX idx_stores_location_gist ON stores USING GIST (location);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_store_list_entries_store_id;
+DROP INDEX IF EXISTS idx_store_list_entries_list_id_position;
+DROP INDEX IF EXISTS idx_store_lists_owner_id;
+DROP TABLE IF EXISTS store_list_entries;
+DROP TABLE IF EXISTS store_lists;
@@ -0,0 +1,26 @@
+-- Personal lists of stores, such as "デート候補" or "出張で行きたい".
+-- visibility decides who besides the owner can see a list:
+-- private (the owner only), editors (logged-in users) or viewers (everyone).
+CREATE TABLE store_lists (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    visibility VARCHAR(20) NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'editors', 'viewers')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Stores of a list in the owner's order, each with a note
+CREATE TABLE store_list_entries (
+    list_id UUID NOT NULL REFERENCES store_lists(id) ON DELETE CASCADE,
+    store_id UUID NOT NULL REFERENCES stores(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (list_id, store_id)
+);
+
+CREATE INDEX idx_store_lists_owner_id ON store_lists(owner_id);
+CREATE INDEX idx_store_list_entries_list_id_position ON store_list_entries(list_id, position);
+CREATE INDEX idx_store_list_entries_store_id ON store_list_entries(store_id);
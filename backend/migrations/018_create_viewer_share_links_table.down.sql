@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_viewer_share_links_expires_at;
+DROP INDEX IF EXISTS idx_viewer_share_links_created_by;
+DROP TABLE IF EXISTS viewer_share_links;
@@ -0,0 +1,24 @@
+-- Read-only share links for a single store, list or saved store filter.
+-- Like viewer_login_history they let people without an account see stores,
+-- but only the shared resource. The token handed out is signed with the
+-- server secret and carries the link ID and expiry; this table records
+-- revocation and how often the link was used.
+CREATE TABLE viewer_share_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    scope VARCHAR(20) NOT NULL CHECK (scope IN ('store', 'list', 'filter')),
+    store_id UUID REFERENCES stores(id) ON DELETE CASCADE,
+    list_id UUID REFERENCES store_lists(id) ON DELETE CASCADE,
+    filter_query TEXT, -- GET /stores query parameters, e.g. categories=カフェ&open_now=true
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    access_count INTEGER NOT NULL DEFAULT 0,
+    last_accessed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK ((scope = 'store') = (store_id IS NOT NULL)),
+    CHECK ((scope = 'list') = (list_id IS NOT NULL)),
+    CHECK ((scope = 'filter') = (filter_query IS NOT NULL))
+);
+
+CREATE INDEX idx_viewer_share_links_created_by ON viewer_share_links(created_by, created_at DESC);
+CREATE INDEX idx_viewer_share_links_expires_at ON viewer_share_links(expires_at);
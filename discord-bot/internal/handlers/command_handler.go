@@ -1,13 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"log"
-	"strings"
 
 	"sukimise-discord-bot/internal/services"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/google/uuid"
 )
 
 type CommandHandler struct {
@@ -55,6 +56,12 @@ var commands = []*discordgo.ApplicationCommand{
 				Description: "Google Maps URL of the store",
 				Required:    true,
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "force",
+				Description: "Register the store even if a duplicate already exists",
+				Required:    false,
+			},
 		},
 	},
 	{
@@ -163,28 +170,40 @@ func (h *CommandHandler) handleAddCommand(s *discordgo.Session, i *discordgo.Int
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
 	})
 
-	options := i.ApplicationCommandData().Options
-	googleMapURL := options[0].StringValue()
+	var googleMapURL string
+	force := false
+	for _, option := range i.ApplicationCommandData().Options {
+		switch option.Name {
+		case "url":
+			googleMapURL = option.StringValue()
+		case "force":
+			force = option.BoolValue()
+		}
+	}
 
 	discordID := i.Member.User.ID
 
 	// Add store from Google Maps URL
-	storeResp, err := h.discordService.AddStoreFromGoogleMaps(discordID, googleMapURL)
+	storeResp, err := h.discordService.AddStoreFromGoogleMaps(discordID, googleMapURL, force)
 	if err != nil {
 		var content string
 		errorMsg := err.Error()
 		
 		// Check if this is a duplicate error
-		if strings.Contains(errorMsg, "重複する店舗が見つかりました") {
+		var duplicateErr *services.DuplicateStoreError
+		if errors.As(err, &duplicateErr) {
+			existing := "Webサイトで店舗を検索してください"
+			if duplicateErr.Conflict.DuplicateID != uuid.Nil {
+				existing = fmt.Sprintf("%s\n%s/stores/%s", duplicateErr.Conflict.Duplicate.Name,
+					h.frontendBaseURL, duplicateErr.Conflict.DuplicateID)
+			}
 			content = fmt.Sprintf("⚠️ **重複店舗検出**\n\n"+
 				"指定された店舗は既にデータベースに登録されている可能性があります。\n\n"+
 				"**詳細:**\n%s\n\n"+
-				"**確認方法:**\n"+
-				"• Webサイト (http://localhost) で店舗を検索してください\n"+
-				"• 同じ名前で同じ場所（50m以内）の店舗が既に存在します\n\n"+
+				"**既存の店舗:**\n%s\n\n"+
 				"**対処法:**\n"+
 				"• 既存店舗にレビューを追加してください\n"+
-				"• 店舗名や場所が異なる場合は管理者にお知らせください", errorMsg)
+				"• 別の店舗の場合は `force:True` を付けて /add を再実行すると登録できます", duplicateErr.Details, existing)
 		} else {
 			content = fmt.Sprintf("❌ **Store Registration Failed**\n%s", errorMsg)
 		}
@@ -231,9 +250,10 @@ Connect your Discord account to your Sukimise account.
 Disconnect your Discord account from Sukimise.
 • Removes the link between your Discord and Sukimise accounts
 
-**🏪 /add <google_maps_url>**
+**🏪 /add <google_maps_url> [force]**
 Add a store from Google Maps URL to Sukimise database.
 • Required: Google Maps URL (must start with https://www.google.com/maps/place/)
+• Optional: force:True registers the store even if a duplicate was found
 • Note: You must be connected to Sukimise first using /connect
 
 **❓ /help**
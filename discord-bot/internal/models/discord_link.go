@@ -93,4 +93,11 @@ type StoreCreateResponse struct {
 type APIResponse struct {
 	Success bool                 `json:"success"`
 	Data    StoreCreateResponse  `json:"data"`
-}
\ No newline at end of file
+}
+
+// StoreDuplicateConflict is the data of the 409 returned when the store
+// already exists in Sukimise
+type StoreDuplicateConflict struct {
+	DuplicateID uuid.UUID           `json:"duplicate_id"`
+	Duplicate   StoreCreateResponse `json:"duplicate"`
+}
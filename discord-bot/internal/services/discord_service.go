@@ -16,6 +16,17 @@ import (
 	"github.com/google/uuid"
 )
 
+// DuplicateStoreError is returned when Sukimise already has the store.
+// Adding the store again with force creates it anyway.
+type DuplicateStoreError struct {
+	Conflict models.StoreDuplicateConflict
+	Details  string
+}
+
+func (e *DuplicateStoreError) Error() string {
+	return fmt.Sprintf("重複する店舗が見つかりました: %s", e.Details)
+}
+
 type DiscordService struct {
 	db             *sql.DB
 	sukimiseAPIURL string
@@ -93,7 +104,9 @@ func (s *DiscordService) DisconnectDiscordUser(discordID string) error {
 	return nil
 }
 
-func (s *DiscordService) AddStoreFromGoogleMaps(discordID, googleMapURL string) (*models.StoreCreateResponse, error) {
+// AddStoreFromGoogleMaps registers the store of a Google Maps URL in Sukimise.
+// Unless force is set, a store that already exists is reported as a *DuplicateStoreError.
+func (s *DiscordService) AddStoreFromGoogleMaps(discordID, googleMapURL string, force bool) (*models.StoreCreateResponse, error) {
 	// Get Discord link
 	_, err := s.GetDiscordLink(discordID)
 	if err == sql.ErrNoRows {
@@ -121,9 +134,9 @@ func (s *DiscordService) AddStoreFromGoogleMaps(discordID, googleMapURL string)
 	}
 
 	// Create store via Sukimise API
-	storeResp, err := s.createStoreViaSukimise(discordID, storeInfo)
+	storeResp, err := s.createStoreViaSukimise(discordID, storeInfo, force)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create store via Sukimise API: %v", err)
+		return nil, fmt.Errorf("failed to create store via Sukimise API: %w", err)
 	}
 
 	return storeResp, nil
@@ -222,7 +235,7 @@ func (s *DiscordService) authenticateWithSukimise(username, password string) (*m
 	return &authResp, nil
 }
 
-func (s *DiscordService) createStoreViaSukimise(discordID string, storeInfo *models.StoreCreateRequest) (*models.StoreCreateResponse, error) {
+func (s *DiscordService) createStoreViaSukimise(discordID string, storeInfo *models.StoreCreateRequest, force bool) (*models.StoreCreateResponse, error) {
 	// Ensure we have a valid access token (refresh if necessary)
 	accessToken, err := s.ensureValidToken(discordID)
 	if err != nil {
@@ -234,7 +247,12 @@ func (s *DiscordService) createStoreViaSukimise(discordID string, storeInfo *mod
 		return nil, fmt.Errorf("failed to marshal store creation request: %v", err)
 	}
 
-	req, err := http.NewRequest("POST", s.sukimiseAPIURL+"/api/v1/stores", bytes.NewBuffer(jsonData))
+	storesURL := s.sukimiseAPIURL + "/api/v1/stores"
+	if force {
+		storesURL += "?force=true"
+	}
+
+	req, err := http.NewRequest("POST", storesURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create store request: %v", err)
 	}
@@ -268,7 +286,7 @@ func (s *DiscordService) createStoreViaSukimise(discordID string, storeInfo *mod
 		}
 		
 		// Create a new request with the refreshed token (need to recreate body)
-		newReq, err := http.NewRequest("POST", s.sukimiseAPIURL+"/api/v1/stores", bytes.NewBuffer(jsonData))
+		newReq, err := http.NewRequest("POST", storesURL, bytes.NewBuffer(jsonData))
 		if err != nil {
 			return nil, fmt.Errorf("failed to create retry request: %v", err)
 		}
@@ -287,9 +305,10 @@ func (s *DiscordService) createStoreViaSukimise(discordID string, storeInfo *mod
 		
 		// Handle duplicate store error (HTTP 409 Conflict)
 		if resp.StatusCode == http.StatusConflict {
-			// Parse error response to get details
+			// Parse error response to get the existing store and details
 			var errorResp struct {
-				Success bool `json:"success"`
+				Success bool                          `json:"success"`
+				Data    models.StoreDuplicateConflict `json:"data"`
 				Error   struct {
 					Code    string `json:"code"`
 					Message string `json:"message"`
@@ -298,10 +317,10 @@ func (s *DiscordService) createStoreViaSukimise(discordID string, storeInfo *mod
 			}
 			
 			if err := json.Unmarshal(bodyBytes, &errorResp); err == nil {
-				return nil, fmt.Errorf("重複する店舗が見つかりました: %s", errorResp.Error.Details)
+				return nil, &DuplicateStoreError{Conflict: errorResp.Data, Details: errorResp.Error.Details}
 			}
 			
-			return nil, fmt.Errorf("重複する店舗が見つかりました: この店舗は既に登録されています")
+			return nil, &DuplicateStoreError{Details: "この店舗は既に登録されています"}
 		}
 		
 		return nil, fmt.Errorf("store creation failed with status %d: %s", resp.StatusCode, string(bodyBytes))